@@ -0,0 +1,62 @@
+package client
+
+import "github.com/boichique/movie-reviews/contracts"
+
+func (c *Client) CreateCollection(req *contracts.AuthenticatedRequest[*contracts.CreateCollectionRequest]) (*contracts.Collection, error) {
+	var collection contracts.Collection
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		SetResult(&collection).
+		Post(c.path("/api/collections"))
+
+	return &collection, err
+}
+
+func (c *Client) GetCollections(req *contracts.GetCollectionsPaginatedRequest) (*contracts.PaginatedResponse[contracts.Collection], error) {
+	var collections contracts.PaginatedResponse[contracts.Collection]
+
+	_, err := c.client.R().
+		SetResult(&collections).
+		SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/collections"))
+
+	return &collections, err
+}
+
+func (c *Client) GetCollectionByID(collectionID int) (*contracts.CollectionDetails, error) {
+	var collection contracts.CollectionDetails
+
+	_, err := c.client.R().
+		SetResult(&collection).
+		Get(c.path("/api/collections/%d", collectionID))
+
+	return &collection, err
+}
+
+func (c *Client) UpdateCollection(req *contracts.AuthenticatedRequest[*contracts.UpdateCollectionRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		Put(c.path("/api/collections/%d", req.Request.CollectionID))
+
+	return err
+}
+
+func (c *Client) SetCollectionMovies(req *contracts.AuthenticatedRequest[*contracts.SetCollectionMoviesRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		Put(c.path("/api/collections/%d/movies", req.Request.CollectionID))
+
+	return err
+}
+
+func (c *Client) DeleteCollection(req *contracts.AuthenticatedRequest[*contracts.GetOrDeleteCollectionRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/collections/%d", req.Request.CollectionID))
+
+	return err
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/go-resty/resty/v2"
+)
+
+// Tagged is an entity along with its ETag and the time it was last modified.
+// Entity is nil when the entity has not changed since the ETag passed along with the request.
+type Tagged[T any] struct {
+	Entity       *T
+	ETag         string
+	LastModified time.Time
+}
+
+func (c *Client) GetMovieConditional(movieID int, ifNoneMatch string) (*Tagged[contracts.MovieDetails], error) {
+	return getConditional[contracts.MovieDetails](c, c.path("/api/movies/%d", movieID), ifNoneMatch)
+}
+
+func (c *Client) GetStarConditional(starID int, ifNoneMatch string) (*Tagged[contracts.StarDetails], error) {
+	return getConditional[contracts.StarDetails](c, c.path("/api/stars/%d", starID), ifNoneMatch)
+}
+
+func (c *Client) GetGenreConditional(genreID int, ifNoneMatch string) (*Tagged[contracts.Genre], error) {
+	return getConditional[contracts.Genre](c, c.path("/api/genres/%d", genreID), ifNoneMatch)
+}
+
+func (c *Client) GetUserConditional(userID int, ifNoneMatch string) (*Tagged[contracts.User], error) {
+	return getConditional[contracts.User](c, c.path("/api/users/%d", userID), ifNoneMatch)
+}
+
+func (c *Client) GetReviewConditional(reviewID int, ifNoneMatch string) (*Tagged[contracts.Review], error) {
+	return getConditional[contracts.Review](c, c.path("/api/reviews/%d", reviewID), ifNoneMatch)
+}
+
+// getConditional gets the entity unless it still has the ETag ifNoneMatch, which is left out when empty.
+func getConditional[T any](c *Client, path, ifNoneMatch string) (*Tagged[T], error) {
+	var entity T
+
+	req := c.client.R().SetResult(&entity)
+	if ifNoneMatch != "" {
+		req.SetHeader(contracts.HeaderIfNoneMatch, ifNoneMatch)
+	}
+	res, err := req.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged := &Tagged[T]{ETag: res.Header().Get(contracts.HeaderETag)}
+	if lastModified, err := http.ParseTime(res.Header().Get(contracts.HeaderLastModified)); err == nil {
+		tagged.LastModified = lastModified
+	}
+	if res.StatusCode() != http.StatusNotModified {
+		tagged.Entity = &entity
+	}
+
+	return tagged, nil
+}
+
+// conditional starts a request which succeeds only while the entity has the ETag ifMatch, unless it is empty.
+func (c *Client) conditional(ifMatch string) *resty.Request {
+	req := c.client.R()
+	if ifMatch != "" {
+		req.SetHeader(contracts.HeaderIfMatch, ifMatch)
+	}
+
+	return req
+}
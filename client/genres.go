@@ -35,7 +35,7 @@ func (c *Client) GetGenres() ([]*contracts.Genre, error) {
 }
 
 func (c *Client) UpdateGenre(req *contracts.AuthenticatedRequest[*contracts.UpdateGenreRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Put(c.path("/api/genres/%d", req.Request.GenreID))
@@ -44,7 +44,7 @@ func (c *Client) UpdateGenre(req *contracts.AuthenticatedRequest[*contracts.Upda
 }
 
 func (c *Client) DeleteGenre(req *contracts.AuthenticatedRequest[*contracts.GetOrDeleteGenreRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Delete(c.path("/api/genres/%d", req.Request.GenreID))
@@ -0,0 +1,54 @@
+package client
+
+import "github.com/boichique/movie-reviews/contracts"
+
+func (c *Client) CreateMovieRole(req *contracts.AuthenticatedRequest[*contracts.CreateMovieRoleRequest]) (*contracts.MovieRole, error) {
+	var role *contracts.MovieRole
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		SetResult(&role).
+		Post(c.path("/api/movie-roles"))
+
+	return role, err
+}
+
+func (c *Client) GetMovieRoleByID(roleID int) (*contracts.MovieRole, error) {
+	var role contracts.MovieRole
+
+	_, err := c.client.R().
+		SetResult(&role).
+		Get(c.path("/api/movie-roles/%d", roleID))
+
+	return &role, err
+}
+
+func (c *Client) GetMovieRoles(req *contracts.GetMovieRolesRequest) ([]*contracts.MovieRole, error) {
+	var roles []*contracts.MovieRole
+
+	_, err := c.client.R().
+		SetResult(&roles).
+		SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/movie-roles"))
+
+	return roles, err
+}
+
+func (c *Client) UpdateMovieRole(req *contracts.AuthenticatedRequest[*contracts.UpdateMovieRoleRequest]) error {
+	_, err := c.conditional(req.IfMatch).
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		Put(c.path("/api/movie-roles/%d", req.Request.RoleID))
+
+	return err
+}
+
+func (c *Client) DeleteMovieRole(req *contracts.AuthenticatedRequest[*contracts.GetOrDeleteMovieRoleRequest]) error {
+	_, err := c.conditional(req.IfMatch).
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		Delete(c.path("/api/movie-roles/%d", req.Request.RoleID))
+
+	return err
+}
@@ -1,6 +1,10 @@
 package client
 
-import "github.com/boichique/movie-reviews/contracts"
+import (
+	"bytes"
+
+	"github.com/boichique/movie-reviews/contracts"
+)
 
 func (c *Client) GetMovie(movieID int) (*contracts.MovieDetails, error) {
 	var m contracts.MovieDetails
@@ -12,6 +16,31 @@ func (c *Client) GetMovie(movieID int) (*contracts.MovieDetails, error) {
 	return &m, err
 }
 
+func (c *Client) GetMovieByExternalID(source, externalID string) (*contracts.MovieDetails, error) {
+	var m contracts.MovieDetails
+
+	_, err := c.client.R().
+		SetResult(&m).
+		SetPathParams(map[string]string{"source": source, "id": externalID}).
+		Get(c.path("/api/movies/by-external/{source}/{id}"))
+
+	return &m, err
+}
+
+func (c *Client) PutMovieExternalID(req *contracts.AuthenticatedRequest[*contracts.PutMovieExternalIDRequest]) (*contracts.ExternalID, error) {
+	var id contracts.ExternalID
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetHeader("Content-Type", "application/json").
+		SetResult(&id).
+		SetBody(req.Request).
+		SetPathParams(map[string]string{"source": req.Request.Source}).
+		Put(c.path("/api/movies/%d/external-ids/{source}", req.Request.MovieID))
+
+	return &id, err
+}
+
 func (c *Client) GetMovies(req *contracts.GetMoviesPaginatedRequest) (*contracts.PaginatedResponse[contracts.Movie], error) {
 	var res contracts.PaginatedResponse[contracts.Movie]
 
@@ -23,6 +52,31 @@ func (c *Client) GetMovies(req *contracts.GetMoviesPaginatedRequest) (*contracts
 	return &res, err
 }
 
+func (c *Client) GetMoviesAuthenticated(req *contracts.AuthenticatedRequest[*contracts.GetMoviesPaginatedRequest]) (*contracts.PaginatedResponse[contracts.Movie], error) {
+	var res contracts.PaginatedResponse[contracts.Movie]
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetResult(&res).
+		SetQueryParams(req.Request.ToQueryParams()).
+		Get(c.path("/api/movies"))
+
+	return &res, err
+}
+
+func (c *Client) GetMoviesWithFacets(req *contracts.GetMoviesPaginatedRequest) (*contracts.MoviesPaginatedResponse, error) {
+	var res contracts.MoviesPaginatedResponse
+	params := req.ToQueryParams()
+	params["facets"] = "true"
+
+	_, err := c.client.R().
+		SetResult(&res).
+		SetQueryParams(params).
+		Get(c.path("/api/movies"))
+
+	return &res, err
+}
+
 func (c *Client) CreateMovie(req *contracts.AuthenticatedRequest[*contracts.CreateMovieRequest]) (*contracts.MovieDetails, error) {
 	var g *contracts.MovieDetails
 	_, err := c.client.R().
@@ -35,7 +89,7 @@ func (c *Client) CreateMovie(req *contracts.AuthenticatedRequest[*contracts.Crea
 }
 
 func (c *Client) UpdateMovie(req *contracts.AuthenticatedRequest[*contracts.UpdateMovieRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetHeader("Content-Type", "application/json").
 		SetBody(req.Request).
@@ -44,8 +98,21 @@ func (c *Client) UpdateMovie(req *contracts.AuthenticatedRequest[*contracts.Upda
 	return err
 }
 
-func (c *Client) DeleteMovie(req *contracts.AuthenticatedRequest[*contracts.DeleteMovieRequest]) error {
+func (c *Client) PatchMovie(req *contracts.AuthenticatedRequest[*contracts.PatchMovieRequest]) (*contracts.MovieDetails, error) {
+	var movie contracts.MovieDetails
+
 	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetHeader("Content-Type", contracts.MIMEApplicationMergePatchJSON).
+		SetBody(req.Request).
+		SetResult(&movie).
+		Patch(c.path("/api/movies/%d", req.Request.MovieID))
+
+	return &movie, err
+}
+
+func (c *Client) DeleteMovie(req *contracts.AuthenticatedRequest[*contracts.DeleteMovieRequest]) error {
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetHeader("Content-Type", "application/json").
 		SetBody(req.Request).
@@ -53,3 +120,114 @@ func (c *Client) DeleteMovie(req *contracts.AuthenticatedRequest[*contracts.Dele
 
 	return err
 }
+
+// GetMovieLocalized gets the movie in the locale of the request, or in the languages of acceptLanguage
+// if the request has no locale.
+func (c *Client) GetMovieLocalized(req *contracts.GetMovieRequest, acceptLanguage string) (*contracts.MovieDetails, error) {
+	var m contracts.MovieDetails
+
+	r := c.client.R().SetResult(&m)
+	if req.Locale != "" {
+		r.SetQueryParam("locale", req.Locale)
+	}
+	if acceptLanguage != "" {
+		r.SetHeader("Accept-Language", acceptLanguage)
+	}
+	_, err := r.Get(c.path("/api/movies/%d", req.MovieID))
+
+	return &m, err
+}
+
+func (c *Client) GetMovieTranslations(movieID int) ([]*contracts.MovieTranslation, error) {
+	var translations []*contracts.MovieTranslation
+
+	_, err := c.client.R().
+		SetResult(&translations).
+		Get(c.path("/api/movies/%d/translations", movieID))
+
+	return translations, err
+}
+
+func (c *Client) PutMovieTranslation(req *contracts.AuthenticatedRequest[*contracts.PutMovieTranslationRequest]) (*contracts.MovieTranslation, error) {
+	var translation contracts.MovieTranslation
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetHeader("Content-Type", "application/json").
+		SetResult(&translation).
+		SetBody(req.Request).
+		Put(c.path("/api/movies/%d/translations/%s", req.Request.MovieID, req.Request.Locale))
+
+	return &translation, err
+}
+
+func (c *Client) DeleteMovieTranslation(req *contracts.AuthenticatedRequest[*contracts.DeleteMovieTranslationRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/movies/%d/translations/%s", req.Request.MovieID, req.Request.Locale))
+
+	return err
+}
+
+func (c *Client) UploadMoviePoster(req *contracts.AuthenticatedRequest[*contracts.UploadMoviePosterRequest], image []byte) (*contracts.Image, error) {
+	var res contracts.Image
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetFileReader("image", "image", bytes.NewReader(image)).
+		SetResult(&res).
+		Put(c.path("/api/movies/%d/poster", req.Request.MovieID))
+
+	return &res, err
+}
+
+func (c *Client) DeleteMoviePoster(req *contracts.AuthenticatedRequest[*contracts.DeleteMoviePosterRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/movies/%d/poster", req.Request.MovieID))
+
+	return err
+}
+
+func (c *Client) GetMovieVersions(movieID int) ([]*contracts.MovieVersion, error) {
+	var versions []*contracts.MovieVersion
+
+	_, err := c.client.R().
+		SetResult(&versions).
+		Get(c.path("/api/movies/%d/versions", movieID))
+
+	return versions, err
+}
+
+func (c *Client) GetMovieVersion(req *contracts.GetMovieVersionRequest) (*contracts.MovieVersionDetails, error) {
+	var version contracts.MovieVersionDetails
+
+	_, err := c.client.R().
+		SetResult(&version).
+		Get(c.path("/api/movies/%d/versions/%d", req.MovieID, req.Version))
+
+	return &version, err
+}
+
+func (c *Client) RollbackMovie(req *contracts.AuthenticatedRequest[*contracts.RollbackMovieRequest]) (*contracts.MovieDetails, error) {
+	var movie contracts.MovieDetails
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetResult(&movie).
+		Post(c.path("/api/movies/%d/versions/%d/rollback", req.Request.MovieID, req.Request.Version))
+
+	return &movie, err
+}
+
+func (c *Client) MergeMovies(req *contracts.AuthenticatedRequest[*contracts.MergeMoviesRequest]) (*contracts.MovieDetails, error) {
+	var movie contracts.MovieDetails
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		SetResult(&movie).
+		Post(c.path("/api/movies/%d/merge", req.Request.MovieID))
+
+	return &movie, err
+}
@@ -2,6 +2,7 @@ package client
 
 import "github.com/boichique/movie-reviews/contracts"
 
+// Paginate fetches all items by following the next cursors of the responses.
 func Paginate[I any, Req contracts.PaginationSetter](
 	req Req,
 	queryFn func(Req) (*contracts.PaginatedResponse[I], error),
@@ -15,10 +16,10 @@ func Paginate[I any, Req contracts.PaginationSetter](
 		}
 		items = append(items, res.Items...)
 
-		if len(items) >= res.Total {
+		if res.NextCursor == nil {
 			break
 		}
-		req.SetPage(res.Page + 1)
+		req.SetAfter(*res.NextCursor)
 	}
 	return items, nil
 }
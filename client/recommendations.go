@@ -0,0 +1,14 @@
+package client
+
+import "github.com/boichique/movie-reviews/contracts"
+
+func (c *Client) GetRecommendations(req *contracts.AuthenticatedRequest[*contracts.GetRecommendationsRequest]) ([]*contracts.Recommendation, error) {
+	var recommendations []*contracts.Recommendation
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetResult(&recommendations).
+		Get(c.path("/api/users/%d/recommendations", req.Request.UserID))
+
+	return recommendations, err
+}
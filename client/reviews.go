@@ -36,7 +36,7 @@ func (c *Client) GetReviews(req *contracts.GetReviewsRequest) (*contracts.Pagina
 }
 
 func (c *Client) UpdateReview(req *contracts.AuthenticatedRequest[*contracts.UpdateReviewRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Put(c.path("/api/users/%d/reviews/%d", req.Request.UserID, req.Request.ReviewID))
@@ -45,7 +45,7 @@ func (c *Client) UpdateReview(req *contracts.AuthenticatedRequest[*contracts.Upd
 }
 
 func (c *Client) DeleteReview(req *contracts.AuthenticatedRequest[*contracts.DeleteReviewRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		Delete(c.path("/api/users/%d/reviews/%d", req.Request.UserID, req.Request.ReviewID))
 
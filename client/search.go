@@ -0,0 +1,25 @@
+package client
+
+import "github.com/boichique/movie-reviews/contracts"
+
+func (c *Client) Suggest(req *contracts.SuggestRequest) ([]*contracts.Suggestion, error) {
+	var suggestions []*contracts.Suggestion
+
+	_, err := c.client.R().
+		SetResult(&suggestions).
+		SetQueryParam("q", req.SearchTerm).
+		Get(c.path("/api/search/suggest"))
+
+	return suggestions, err
+}
+
+func (c *Client) Search(req *contracts.SearchRequest) (*contracts.SearchResponse, error) {
+	var res contracts.SearchResponse
+
+	_, err := c.client.R().
+		SetResult(&res).
+		SetQueryParams(req.ToQueryParams()).
+		Get(c.path("/api/search"))
+
+	return &res, err
+}
@@ -1,6 +1,10 @@
 package client
 
-import "github.com/boichique/movie-reviews/contracts"
+import (
+	"bytes"
+
+	"github.com/boichique/movie-reviews/contracts"
+)
 
 func (c *Client) CreateStar(req *contracts.AuthenticatedRequest[*contracts.CreateStarRequest]) (*contracts.StarDetails, error) {
 	var star *contracts.StarDetails
@@ -35,8 +39,43 @@ func (c *Client) GetStarByID(starID int) (*contracts.StarDetails, error) {
 	return &star, err
 }
 
-func (c *Client) UpdateStar(req *contracts.AuthenticatedRequest[*contracts.UpdateStarRequest]) error {
+func (c *Client) GetStarFilmography(starID int) (*contracts.Filmography, error) {
+	var filmography contracts.Filmography
+
+	_, err := c.client.R().
+		SetResult(&filmography).
+		Get(c.path("/api/stars/%d/filmography", starID))
+
+	return &filmography, err
+}
+
+func (c *Client) GetStarByExternalID(source, externalID string) (*contracts.StarDetails, error) {
+	var star contracts.StarDetails
+
+	_, err := c.client.R().
+		SetResult(&star).
+		SetPathParams(map[string]string{"source": source, "id": externalID}).
+		Get(c.path("/api/stars/by-external/{source}/{id}"))
+
+	return &star, err
+}
+
+func (c *Client) PutStarExternalID(req *contracts.AuthenticatedRequest[*contracts.PutStarExternalIDRequest]) (*contracts.ExternalID, error) {
+	var id contracts.ExternalID
+
 	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetHeader("Content-Type", "application/json").
+		SetResult(&id).
+		SetBody(req.Request).
+		SetPathParams(map[string]string{"source": req.Request.Source}).
+		Put(c.path("/api/stars/%d/external-ids/{source}", req.Request.StarID))
+
+	return &id, err
+}
+
+func (c *Client) UpdateStar(req *contracts.AuthenticatedRequest[*contracts.UpdateStarRequest]) error {
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Put(c.path("/api/stars/%d", req.Request.StarID))
@@ -44,11 +83,56 @@ func (c *Client) UpdateStar(req *contracts.AuthenticatedRequest[*contracts.Updat
 	return err
 }
 
-func (c *Client) DeleteStar(req *contracts.AuthenticatedRequest[*contracts.DeleteStarRequest]) error {
+func (c *Client) PatchStar(req *contracts.AuthenticatedRequest[*contracts.PatchStarRequest]) (*contracts.StarDetails, error) {
+	var star contracts.StarDetails
+
 	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetHeader("Content-Type", contracts.MIMEApplicationMergePatchJSON).
+		SetBody(req.Request).
+		SetResult(&star).
+		Patch(c.path("/api/stars/%d", req.Request.StarID))
+
+	return &star, err
+}
+
+func (c *Client) DeleteStar(req *contracts.AuthenticatedRequest[*contracts.DeleteStarRequest]) error {
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Delete(c.path("/api/stars/%d", req.Request.StarID))
 
 	return err
 }
+
+func (c *Client) MergeStars(req *contracts.AuthenticatedRequest[*contracts.MergeStarsRequest]) (*contracts.StarDetails, error) {
+	var star contracts.StarDetails
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetBody(req.Request).
+		SetResult(&star).
+		Post(c.path("/api/stars/%d/merge", req.Request.StarID))
+
+	return &star, err
+}
+
+func (c *Client) UploadStarPhoto(req *contracts.AuthenticatedRequest[*contracts.UploadStarPhotoRequest], image []byte) (*contracts.Image, error) {
+	var res contracts.Image
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetFileReader("image", "image", bytes.NewReader(image)).
+		SetResult(&res).
+		Put(c.path("/api/stars/%d/photo", req.Request.StarID))
+
+	return &res, err
+}
+
+func (c *Client) DeleteStarPhoto(req *contracts.AuthenticatedRequest[*contracts.DeleteStarPhotoRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/stars/%d/photo", req.Request.StarID))
+
+	return err
+}
@@ -0,0 +1,23 @@
+package client
+
+import "github.com/boichique/movie-reviews/contracts"
+
+func (c *Client) GetTrash(req *contracts.AuthenticatedRequest[*contracts.GetTrashRequest]) (*contracts.PaginatedResponse[contracts.TrashItem], error) {
+	var items contracts.PaginatedResponse[contracts.TrashItem]
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetResult(&items).
+		SetQueryParams(req.Request.ToQueryParams()).
+		Get(c.path("/api/trash/%s", req.Request.Entity))
+
+	return &items, err
+}
+
+func (c *Client) RestoreFromTrash(req *contracts.AuthenticatedRequest[*contracts.RestoreFromTrashRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		Post(c.path("/api/trash/%s/%d/restore", req.Request.Entity, req.Request.ID))
+
+	return err
+}
@@ -1,6 +1,11 @@
 package client
 
-import "github.com/boichique/movie-reviews/contracts"
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/boichique/movie-reviews/contracts"
+)
 
 func (c *Client) GetUserByID(userID int) (*contracts.User, error) {
 	var user contracts.User
@@ -23,7 +28,7 @@ func (c *Client) GetUserByUsername(username string) (*contracts.User, error) {
 }
 
 func (c *Client) UpdateUserBio(req *contracts.AuthenticatedRequest[*contracts.UpdateUserBioRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Put(c.path("/api/users/%d", req.Request.UserID))
@@ -32,7 +37,7 @@ func (c *Client) UpdateUserBio(req *contracts.AuthenticatedRequest[*contracts.Up
 }
 
 func (c *Client) UpdateUserRole(req *contracts.AuthenticatedRequest[*contracts.UpdateUserRoleRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Put(c.path("/api/users/%d/role/%s", req.Request.UserID, req.Request.Role))
@@ -41,10 +46,44 @@ func (c *Client) UpdateUserRole(req *contracts.AuthenticatedRequest[*contracts.U
 }
 
 func (c *Client) DeleteUser(req *contracts.AuthenticatedRequest[*contracts.GetOrDeleteUserRequest]) error {
-	_, err := c.client.R().
+	_, err := c.conditional(req.IfMatch).
 		SetAuthToken(req.AccessToken).
 		SetBody(req.Request).
 		Delete(c.path("/api/users/%d", req.Request.UserID))
 
 	return err
 }
+
+func (c *Client) UploadUserAvatar(req *contracts.AuthenticatedRequest[*contracts.UploadUserAvatarRequest], image []byte) (*contracts.Avatar, error) {
+	var res contracts.Avatar
+
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		SetFileReader("image", "image", bytes.NewReader(image)).
+		SetResult(&res).
+		Put(c.path("/api/users/%d/avatar", req.Request.UserID))
+
+	return &res, err
+}
+
+func (c *Client) DeleteUserAvatar(req *contracts.AuthenticatedRequest[*contracts.DeleteUserAvatarRequest]) error {
+	_, err := c.client.R().
+		SetAuthToken(req.AccessToken).
+		Delete(c.path("/api/users/%d/avatar", req.Request.UserID))
+
+	return err
+}
+
+func (c *Client) GetUserIdenticon(req *contracts.GetUserIdenticonRequest) ([]byte, error) {
+	r := c.client.R()
+	if req.Size != 0 {
+		r.SetQueryParam("size", strconv.Itoa(req.Size))
+	}
+
+	res, err := r.Get(c.path("/api/users/%d/identicon", req.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body(), nil
+}
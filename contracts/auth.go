@@ -18,6 +18,8 @@ type LoginUserResponse struct {
 type AuthenticatedRequest[T any] struct {
 	AccessToken string
 	Request     T
+	// IfMatch is the ETag the entity must still have for PUT and DELETE requests to succeed, empty for no precondition
+	IfMatch string
 }
 
 func NewAuthenticated[T any](request T, accessToken string) *AuthenticatedRequest[T] {
@@ -26,3 +28,9 @@ func NewAuthenticated[T any](request T, accessToken string) *AuthenticatedReques
 		AccessToken: accessToken,
 	}
 }
+
+// WithIfMatch makes the request conditional on the entity still having the etag.
+func (r *AuthenticatedRequest[T]) WithIfMatch(etag string) *AuthenticatedRequest[T] {
+	r.IfMatch = etag
+	return r
+}
@@ -0,0 +1,68 @@
+package contracts
+
+import "time"
+
+type Collection struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CollectionDetails holds the movies of the collection in their order, and the rating over all of their reviews.
+type CollectionDetails struct {
+	Collection
+	AvgRating    *float64           `json:"avg_rating,omitempty"`
+	RatingsCount int                `json:"ratings_count"`
+	Movies       []*CollectionMovie `json:"movies"`
+}
+
+type CollectionMovie struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+	Poster      *Image    `json:"poster,omitempty"`
+}
+
+// MovieCollection refers to the collection of a movie, Position is the 1-based place of the movie in it.
+type MovieCollection struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+type CreateCollectionRequest struct {
+	Name        string  `json:"name" validate:"min=1,max=128"`
+	Description *string `json:"description,omitempty" validate:"max=2000"`
+}
+
+type GetCollectionsPaginatedRequest struct {
+	PaginatedRequest
+	Sort *string `query:"sort"`
+}
+
+type GetOrDeleteCollectionRequest struct {
+	CollectionID int `param:"collectionID" validate:"nonzero"`
+}
+
+type UpdateCollectionRequest struct {
+	CollectionID int     `param:"collectionID" validate:"nonzero"`
+	Name         string  `json:"name" validate:"min=1,max=128"`
+	Description  *string `json:"description,omitempty" validate:"max=2000"`
+}
+
+// SetCollectionMoviesRequest replaces the movies of the collection, MovieIDs are in the order of the collection.
+type SetCollectionMoviesRequest struct {
+	CollectionID int   `param:"collectionID" validate:"nonzero"`
+	MovieIDs     []int `json:"movie_ids"`
+}
+
+func (r *GetCollectionsPaginatedRequest) ToQueryParams() map[string]string {
+	params := r.PaginatedRequest.ToQueryParams()
+	if r.Sort != nil {
+		params["sort"] = *r.Sort
+	}
+
+	return params
+}
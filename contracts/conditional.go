@@ -0,0 +1,12 @@
+package contracts
+
+// Headers of conditional requests. Single entities are sent with an ETag, which GET requests pass in If-None-Match
+// to be answered 304 Not Modified while the entity is unchanged, and PUT and DELETE requests pass in If-Match
+// to be refused with 412 Precondition Failed when the entity has changed since.
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+
+	HeaderLastModified = "Last-Modified"
+)
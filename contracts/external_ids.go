@@ -0,0 +1,35 @@
+package contracts
+
+// ExternalID identifies a movie or a star in an outside catalog, e.g. {"source": "imdb", "id": "tt0076759"}.
+// An entity has at most one ID per source, and an ID of a source belongs to a single entity.
+type ExternalID struct {
+	Source string `json:"source" validate:"nonzero,max=32"`
+	ID     string `json:"id" validate:"nonzero,max=64"`
+}
+
+type GetByExternalIDRequest struct {
+	Source     string `param:"source" validate:"nonzero,max=32"`
+	ExternalID string `param:"id" validate:"nonzero,max=64"`
+}
+
+// PutExternalIDRequest sets the ID of a movie or a star in the source, replacing the one it had there.
+type PutExternalIDRequest struct {
+	Source     string `param:"source" validate:"nonzero,max=32"`
+	ExternalID string `json:"id" validate:"nonzero,max=64"`
+}
+
+type PutMovieExternalIDRequest struct {
+	MovieID int `param:"movieID" validate:"nonzero"`
+	PutExternalIDRequest
+}
+
+type PutStarExternalIDRequest struct {
+	StarID int `param:"starID" validate:"nonzero"`
+	PutExternalIDRequest
+}
+
+// GetMovieByExternalIDRequest localizes the movie the same way as GetMovieRequest.
+type GetMovieByExternalIDRequest struct {
+	GetByExternalIDRequest
+	Locale string `query:"locale" validate:"locale"`
+}
@@ -0,0 +1,24 @@
+package contracts
+
+// Image is an uploaded picture, the thumbnail is a JPEG scaled down to a fixed width.
+// Images are uploaded as the "image" field of a multipart form.
+type Image struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+type UploadMoviePosterRequest struct {
+	MovieID int `param:"movieID" validate:"nonzero"`
+}
+
+type DeleteMoviePosterRequest struct {
+	MovieID int `param:"movieID" validate:"nonzero"`
+}
+
+type UploadStarPhotoRequest struct {
+	StarID int `param:"starID" validate:"nonzero"`
+}
+
+type DeleteStarPhotoRequest struct {
+	StarID int `param:"starID" validate:"nonzero"`
+}
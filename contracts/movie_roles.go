@@ -0,0 +1,38 @@
+package contracts
+
+// MovieRole is a role stars are credited with in movies. Category is cast for the roles in front of the camera,
+// e.g. actor, and crew for the roles behind it, e.g. cinematographer.
+type MovieRole struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// GetMovieRolesRequest lists the roles, only the ones of the category if it is set.
+type GetMovieRolesRequest struct {
+	Category *string `query:"category" validate:"roleCategory"`
+}
+
+type GetOrDeleteMovieRoleRequest struct {
+	RoleID int `param:"roleID" validate:"nonzero"`
+}
+
+type CreateMovieRoleRequest struct {
+	Name     string `json:"name" validate:"min=2,max=50"`
+	Category string `json:"category" validate:"roleCategory"`
+}
+
+type UpdateMovieRoleRequest struct {
+	RoleID   int    `param:"roleID" validate:"nonzero"`
+	Name     string `json:"name" validate:"min=2,max=50"`
+	Category string `json:"category" validate:"roleCategory"`
+}
+
+func (r *GetMovieRolesRequest) ToQueryParams() map[string]string {
+	params := make(map[string]string)
+	if r.Category != nil {
+		params["category"] = *r.Category
+	}
+
+	return params
+}
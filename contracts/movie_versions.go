@@ -0,0 +1,61 @@
+package contracts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MovieVersion lists the changes a version made to the previous one, the first version has no changes.
+type MovieVersion struct {
+	Version    int            `json:"version"`
+	ChangedBy  *int           `json:"changed_by,omitempty"`
+	RollbackOf *int           `json:"rollback_of,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Changes    []*FieldChange `json:"changes"`
+}
+
+type MovieVersionDetails struct {
+	MovieVersion
+	Snapshot *MovieSnapshot `json:"snapshot"`
+}
+
+// FieldChange holds the JSON values of the field before and after the change.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old"`
+	New   json.RawMessage `json:"new"`
+}
+
+type MovieSnapshot struct {
+	Title           string            `json:"title"`
+	Description     string            `json:"description"`
+	ReleaseDate     time.Time         `json:"release_date"`
+	Language        string            `json:"language"`
+	RuntimeMinutes  *int              `json:"runtime_minutes"`
+	Countries       []string          `json:"countries"`
+	SpokenLanguages []string          `json:"spoken_languages"`
+	AgeRating       *string           `json:"age_rating"`
+	Tagline         *string           `json:"tagline"`
+	GenreIDs        []int             `json:"genre_ids"`
+	Cast            []*SnapshotCredit `json:"cast"`
+}
+
+type SnapshotCredit struct {
+	StarID  int     `json:"star_id"`
+	Role    string  `json:"role"`
+	Details *string `json:"details,omitempty"`
+}
+
+type GetMovieVersionsRequest struct {
+	MovieID int `param:"movieID" validate:"nonzero"`
+}
+
+type GetMovieVersionRequest struct {
+	MovieID int `param:"movieID" validate:"nonzero"`
+	Version int `param:"version" validate:"min=0"`
+}
+
+type RollbackMovieRequest struct {
+	MovieID int `param:"movieID" validate:"nonzero"`
+	Version int `param:"version" validate:"min=0"`
+}
@@ -6,20 +6,61 @@ import (
 )
 
 type Movie struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	ReleaseDate time.Time  `json:"release_date"`
-	AvgRating   *float64   `json:"avg_rating,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	ID            int        `json:"id"`
+	Title         string     `json:"title"`
+	OriginalTitle *string    `json:"original_title,omitempty"`
+	ReleaseDate   time.Time  `json:"release_date"`
+	AvgRating     *float64   `json:"avg_rating,omitempty"`
+	Poster        *Image     `json:"poster,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
 }
 
 type MovieDetails struct {
 	Movie
-	Description string         `json:"description"`
-	Version     int            `json:"version"`
-	Genres      []*Genre       `json:"genres"`
-	Cast        []*MovieCredit `json:"cast"`
+	Description     string           `json:"description"`
+	Language        string           `json:"language"`
+	RuntimeMinutes  *int             `json:"runtime_minutes,omitempty"`
+	Countries       []string         `json:"countries,omitempty"`
+	SpokenLanguages []string         `json:"spoken_languages,omitempty"`
+	AgeRating       *string          `json:"age_rating,omitempty"`
+	Tagline         *string          `json:"tagline,omitempty"`
+	ExternalIDs     []*ExternalID    `json:"external_ids,omitempty"`
+	Version         int              `json:"version"`
+	Genres          []*Genre         `json:"genres"`
+	Cast            []*MovieCredit   `json:"cast"`
+	Collection      *MovieCollection `json:"collection,omitempty"`
+}
+
+// MoviesPaginatedResponse is a page of movies along with the facets of the whole result set,
+// which are returned only if requested.
+type MoviesPaginatedResponse struct {
+	PaginatedResponse[Movie]
+	Facets *MovieFacets `json:"facets,omitempty"`
+}
+
+type MovieFacets struct {
+	Genres  []*GenreFacet  `json:"genres"`
+	Decades []*DecadeFacet `json:"decades"`
+	Ratings []*RatingFacet `json:"ratings"`
+}
+
+type GenreFacet struct {
+	GenreID int    `json:"genre_id"`
+	Name    string `json:"name"`
+	Count   int    `json:"count"`
+}
+
+type DecadeFacet struct {
+	Decade int `json:"decade"`
+	Count  int `json:"count"`
+}
+
+// RatingFacet counts the rated movies with an average rating in [From, To). The last bucket includes 10.
+type RatingFacet struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
 }
 
 type MovieCredit struct {
@@ -34,49 +75,182 @@ type MovieCreditInfo struct {
 	Details *string `json:"details"`
 }
 
+// GetMovieRequest takes the locale from the Locale parameter if set, or from the Accept-Language header otherwise.
 type GetMovieRequest struct {
+	MovieID int    `param:"movieID" validate:"nonzero"`
+	Locale  string `query:"locale" validate:"locale"`
+}
+
+type MovieTranslation struct {
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GetMovieTranslationsRequest struct {
 	MovieID int `param:"movieID" validate:"nonzero"`
 }
 
+type PutMovieTranslationRequest struct {
+	MovieID     int     `param:"movieID" validate:"nonzero"`
+	Locale      string  `param:"locale" validate:"nonzero,locale"`
+	Title       string  `json:"title" validate:"nonzero"`
+	Description *string `json:"description"`
+}
+
+type DeleteMovieTranslationRequest struct {
+	MovieID int    `param:"movieID" validate:"nonzero"`
+	Locale  string `param:"locale" validate:"nonzero,locale"`
+}
+
 type GetMoviesPaginatedRequest struct {
 	PaginatedRequest
-	StarID       *int    `query:"starID"`
-	SearchTerm   *string `query:"q"`
-	SortByRating *string `json:"sortByRating" validate:"sort"`
+	StarID          *int       `query:"starID"`
+	StarRole        *string    `query:"role"`
+	SearchTerm      *string    `query:"q"`
+	Lang            string     `query:"lang" validate:"lang"`
+	Locale          string     `query:"locale" validate:"locale"`
+	GenreIDs        IntList    `query:"genreIDs"`
+	GenresMatch     *string    `query:"genresMatch" validate:"match"`
+	ReleasedFrom    *time.Time `query:"releasedFrom"`
+	ReleasedTo      *time.Time `query:"releasedTo"`
+	MinRating       *float64   `query:"minRating" validate:"min=0,max=10"`
+	MaxRating       *float64   `query:"maxRating" validate:"min=0,max=10"`
+	Country         *string    `query:"country" validate:"country"`
+	SpokenLanguage  *string    `query:"spokenLanguage" validate:"lang"`
+	AgeRatings      StringList `query:"ageRatings"`
+	MinRuntime      *int       `query:"minRuntime" validate:"min=1"`
+	MaxRuntime      *int       `query:"maxRuntime" validate:"min=1"`
+	ExcludeReviewed bool       `query:"excludeReviewed"`
+	Facets          bool       `query:"facets"`
+	Sort            *string    `query:"sort"`
+	// Deprecated: use Sort with "rating:asc" or "rating:desc" instead.
+	SortByRating *string `query:"sortByRating" validate:"sort"`
 }
 
 type CreateMovieRequest struct {
-	Title       string             `json:"title" validate:"nonzero"`
-	Description string             `json:"description" validate:"nonzero"`
-	ReleaseDate time.Time          `json:"release_date" validate:"nonzero"`
+	Title       string    `json:"title" validate:"nonzero"`
+	Description string    `json:"description" validate:"nonzero"`
+	ReleaseDate time.Time `json:"release_date" validate:"nonzero"`
+	Language    string    `json:"language" validate:"lang"`
+	MovieMetadata
 	GenresID    []int              `json:"genresID"`
 	Cast        []*MovieCreditInfo `json:"cast"`
+	ExternalIDs []*ExternalID      `json:"external_ids"`
 }
 
+// UpdateMovieRequest replaces the movie. A movie updated without a language keeps its current one.
 type UpdateMovieRequest struct {
-	MovieID     int                `param:"movieID" validate:"nonzero"`
-	Version     int                `json:"version" validate:"min=0"`
-	Title       string             `json:"title" validate:"nonzero"`
-	Description string             `json:"description" validate:"nonzero"`
-	ReleaseDate time.Time          `json:"release_date" validate:"nonzero"`
-	GenresID    []int              `json:"genresId"`
-	Cast        []*MovieCreditInfo `json:"cast"`
+	MovieID     int       `param:"movieID" validate:"nonzero"`
+	Version     int       `json:"version" validate:"min=0"`
+	Title       string    `json:"title" validate:"nonzero"`
+	Description string    `json:"description" validate:"nonzero"`
+	ReleaseDate time.Time `json:"release_date" validate:"nonzero"`
+	Language    string    `json:"language" validate:"lang"`
+	MovieMetadata
+	GenresID []int              `json:"genresId"`
+	Cast     []*MovieCreditInfo `json:"cast"`
+}
+
+// MovieMetadata holds the optional details of a movie. Countries are ISO 3166-1 alpha-2 codes,
+// spoken languages are ISO 639-1 codes.
+type MovieMetadata struct {
+	RuntimeMinutes  *int     `json:"runtime_minutes" validate:"min=1,max=1000"`
+	Countries       []string `json:"countries" validate:"country"`
+	SpokenLanguages []string `json:"spoken_languages" validate:"lang"`
+	AgeRating       *string  `json:"age_rating" validate:"max=16"`
+	Tagline         *string  `json:"tagline" validate:"max=255"`
 }
 
 type DeleteMovieRequest struct {
 	MovieID int `param:"movieID" validate:"nonzero"`
 }
 
+// MergeMoviesRequest merges the duplicate into the movie, the duplicate ID redirects to the movie afterwards.
+type MergeMoviesRequest struct {
+	MovieID     int `param:"movieID" validate:"nonzero"`
+	DuplicateID int `json:"duplicate_id" validate:"nonzero"`
+}
+
 func (r *GetMoviesPaginatedRequest) ToQueryParams() map[string]string {
 	param := r.PaginatedRequest.ToQueryParams()
 	if r.StarID != nil {
 		param["starID"] = strconv.Itoa(*r.StarID)
 	}
 
+	if r.StarRole != nil {
+		param["role"] = *r.StarRole
+	}
+
 	if r.SearchTerm != nil {
 		param["q"] = *r.SearchTerm
 	}
 
+	if r.Lang != "" {
+		param["lang"] = r.Lang
+	}
+
+	if r.Locale != "" {
+		param["locale"] = r.Locale
+	}
+
+	if len(r.GenreIDs) > 0 {
+		param["genreIDs"] = r.GenreIDs.String()
+	}
+
+	if r.GenresMatch != nil {
+		param["genresMatch"] = *r.GenresMatch
+	}
+
+	if r.ReleasedFrom != nil {
+		param["releasedFrom"] = r.ReleasedFrom.Format(time.RFC3339)
+	}
+
+	if r.ReleasedTo != nil {
+		param["releasedTo"] = r.ReleasedTo.Format(time.RFC3339)
+	}
+
+	if r.MinRating != nil {
+		param["minRating"] = strconv.FormatFloat(*r.MinRating, 'f', -1, 64)
+	}
+
+	if r.MaxRating != nil {
+		param["maxRating"] = strconv.FormatFloat(*r.MaxRating, 'f', -1, 64)
+	}
+
+	if r.Country != nil {
+		param["country"] = *r.Country
+	}
+
+	if r.SpokenLanguage != nil {
+		param["spokenLanguage"] = *r.SpokenLanguage
+	}
+
+	if len(r.AgeRatings) > 0 {
+		param["ageRatings"] = r.AgeRatings.String()
+	}
+
+	if r.MinRuntime != nil {
+		param["minRuntime"] = strconv.Itoa(*r.MinRuntime)
+	}
+
+	if r.MaxRuntime != nil {
+		param["maxRuntime"] = strconv.Itoa(*r.MaxRuntime)
+	}
+
+	if r.ExcludeReviewed {
+		param["excludeReviewed"] = strconv.FormatBool(r.ExcludeReviewed)
+	}
+
+	if r.Facets {
+		param["facets"] = strconv.FormatBool(r.Facets)
+	}
+
+	if r.Sort != nil {
+		param["sort"] = *r.Sort
+	}
+
 	if r.SortByRating != nil {
 		param["sortByRating"] = *r.SortByRating
 	}
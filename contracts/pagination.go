@@ -5,6 +5,7 @@ import "strconv"
 type PaginationSetter interface {
 	SetPage(page int)
 	SetSize(size int)
+	SetAfter(cursor string)
 }
 
 func (req *PaginatedRequest) SetPage(page int) {
@@ -15,25 +16,47 @@ func (req *PaginatedRequest) SetSize(size int) {
 	req.Size = size
 }
 
+func (req *PaginatedRequest) SetAfter(cursor string) {
+	req.After = &cursor
+	req.Before = nil
+}
+
+// PaginatedRequest selects a page either by its number or by a cursor taken from a previous response.
+// When a cursor is given the page number is ignored. The total count is returned by default only
+// when paginating by page number, WithTotal overrides that.
 type PaginatedRequest struct {
-	Page int `json:"page" query:"page"`
-	Size int `json:"size" query:"size"`
+	Page      int     `json:"page" query:"page"`
+	Size      int     `json:"size" query:"size"`
+	After     *string `json:"after,omitempty" query:"after"`
+	Before    *string `json:"before,omitempty" query:"before"`
+	WithTotal *bool   `json:"withTotal,omitempty" query:"withTotal"`
 }
 
 type PaginatedResponse[T any] struct {
-	Page  int  `json:"page" validate:"min=0"`
-	Size  int  `json:"size" validate:"min=0"`
-	Total int  `json:"total"`
-	Items []*T `json:"items"`
+	Page       int     `json:"page" validate:"min=0"`
+	Size       int     `json:"size" validate:"min=0"`
+	Total      *int    `json:"total,omitempty"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+	Items      []*T    `json:"items"`
 }
 
 func (req *PaginatedRequest) ToQueryParams() map[string]string {
-	params := make(map[string]string, 2)
+	params := make(map[string]string, 5)
 	if req.Page > 0 {
 		params["page"] = strconv.Itoa(req.Page)
 	}
 	if req.Size > 0 {
 		params["size"] = strconv.Itoa(req.Size)
 	}
+	if req.After != nil {
+		params["after"] = *req.After
+	}
+	if req.Before != nil {
+		params["before"] = *req.Before
+	}
+	if req.WithTotal != nil {
+		params["withTotal"] = strconv.FormatBool(*req.WithTotal)
+	}
 	return params
 }
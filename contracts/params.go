@@ -0,0 +1,61 @@
+package contracts
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IntList is a comma-separated list of integers bound from a single query parameter, e.g. ?genreIDs=1,2,3.
+type IntList []int
+
+func (l *IntList) UnmarshalParam(param string) error {
+	if param == "" {
+		*l = nil
+		return nil
+	}
+
+	parts := strings.Split(param, ",")
+	list := make(IntList, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+		list = append(list, v)
+	}
+
+	*l = list
+	return nil
+}
+
+func (l IntList) String() string {
+	parts := make([]string, len(l))
+	for i, v := range l {
+		parts[i] = strconv.Itoa(v)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// StringList is a comma-separated list of strings bound from a single query parameter, e.g. ?types=movies,stars.
+type StringList []string
+
+func (l *StringList) UnmarshalParam(param string) error {
+	if param == "" {
+		*l = nil
+		return nil
+	}
+
+	parts := strings.Split(param, ",")
+	list := make(StringList, 0, len(parts))
+	for _, part := range parts {
+		list = append(list, strings.TrimSpace(part))
+	}
+
+	*l = list
+	return nil
+}
+
+func (l StringList) String() string {
+	return strings.Join(l, ",")
+}
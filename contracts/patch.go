@@ -0,0 +1,104 @@
+package contracts
+
+import "encoding/json"
+
+// MIMEApplicationMergePatchJSON is the media type of JSON Merge Patch (RFC 7386) documents.
+const MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
+// PatchMovieRequest partially updates the movie. Fields is a JSON Merge Patch of the fields of
+// UpdateMovieRequest: members left out are kept, null clears a member. Genres and Cast add and remove
+// single entries. Version must be the current version of the movie.
+//
+// On the wire all of them are members of one object, e.g. {"version": 2, "tagline": null, "genres": {"add": [3]}}.
+type PatchMovieRequest struct {
+	MovieID int             `param:"movieID" validate:"nonzero"`
+	Version *int            `validate:"nonnil"`
+	Genres  *GenresPatch    `validate:"-"`
+	Cast    *CastPatch      `validate:"-"`
+	Fields  json.RawMessage `validate:"-"`
+}
+
+type GenresPatch struct {
+	Add    []int `json:"add,omitempty"`
+	Remove []int `json:"remove,omitempty"`
+}
+
+// CastPatch adds credits to the cast and removes them by star and role. Adding an existing credit updates its details.
+type CastPatch struct {
+	Add    []*MovieCreditInfo `json:"add,omitempty"`
+	Remove []*MovieCreditInfo `json:"remove,omitempty"`
+}
+
+func (r *PatchMovieRequest) UnmarshalJSON(data []byte) error {
+	var err error
+	r.Fields, err = splitMembers(data, map[string]any{"version": &r.Version, "genres": &r.Genres, "cast": &r.Cast})
+	return err
+}
+
+func (r *PatchMovieRequest) MarshalJSON() ([]byte, error) {
+	members := map[string]any{"version": r.Version}
+	if r.Genres != nil {
+		members["genres"] = r.Genres
+	}
+	if r.Cast != nil {
+		members["cast"] = r.Cast
+	}
+
+	return joinMembers(r.Fields, members)
+}
+
+// PatchStarRequest partially updates the star. Fields is a JSON Merge Patch of the fields of UpdateStarRequest:
+// members left out are kept, null clears a member. Version must be the current version of the star.
+//
+// On the wire both are members of one object, e.g. {"version": 1, "bio": "Actor", "middle_name": null}.
+type PatchStarRequest struct {
+	StarID  int             `param:"starID" validate:"nonzero"`
+	Version *int            `validate:"nonnil"`
+	Fields  json.RawMessage `validate:"-"`
+}
+
+func (r *PatchStarRequest) UnmarshalJSON(data []byte) error {
+	var err error
+	r.Fields, err = splitMembers(data, map[string]any{"version": &r.Version})
+	return err
+}
+
+func (r *PatchStarRequest) MarshalJSON() ([]byte, error) {
+	return joinMembers(r.Fields, map[string]any{"version": r.Version})
+}
+
+// splitMembers unmarshals the named members of the object into their destinations
+// and returns the object of the remaining members.
+func splitMembers(data []byte, named map[string]any) (json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	for name, dst := range named {
+		if raw, ok := members[name]; ok {
+			if err := json.Unmarshal(raw, dst); err != nil {
+				return nil, err
+			}
+			delete(members, name)
+		}
+	}
+
+	return json.Marshal(members)
+}
+
+// joinMembers adds the named members to the object of fields.
+func joinMembers(fields json.RawMessage, named map[string]any) ([]byte, error) {
+	members := map[string]any{}
+	if len(fields) > 0 {
+		if err := json.Unmarshal(fields, &members); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, value := range named {
+		members[name] = value
+	}
+
+	return json.Marshal(members)
+}
@@ -0,0 +1,11 @@
+package contracts
+
+type Recommendation struct {
+	Movie  Movie   `json:"movie"`
+	Score  float64 `json:"score"`
+	Source string  `json:"source"`
+}
+
+type GetRecommendationsRequest struct {
+	UserID int `param:"userID" validate:"nonzero"`
+}
@@ -18,8 +18,9 @@ type Review struct {
 
 type GetReviewsRequest struct {
 	PaginatedRequest
-	MovieID *int `query:"movieID"`
-	UserID  *int `query:"userID"`
+	MovieID *int    `query:"movieID"`
+	UserID  *int    `query:"userID"`
+	Sort    *string `query:"sort"`
 }
 
 func (r *GetReviewsRequest) ToQueryParams() map[string]string {
@@ -30,6 +31,9 @@ func (r *GetReviewsRequest) ToQueryParams() map[string]string {
 	if r.UserID != nil {
 		params["userID"] = strconv.Itoa(*r.UserID)
 	}
+	if r.Sort != nil {
+		params["sort"] = *r.Sort
+	}
 	return params
 }
 
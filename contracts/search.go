@@ -0,0 +1,49 @@
+package contracts
+
+import "time"
+
+type Suggestion struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+	Year *int   `json:"year,omitempty"`
+}
+
+type SuggestRequest struct {
+	SearchTerm string `query:"q" validate:"nonzero"`
+}
+
+// SearchRequest searches the entity types listed in Types, all of them if empty. Every type is paginated on its own,
+// so to fetch the next page of a single type, request just that type. Cursors require a single type.
+type SearchRequest struct {
+	PaginatedRequest
+	SearchTerm string     `query:"q" validate:"nonzero"`
+	Types      StringList `query:"types"`
+}
+
+func (r *SearchRequest) ToQueryParams() map[string]string {
+	params := r.PaginatedRequest.ToQueryParams()
+	params["q"] = r.SearchTerm
+	if len(r.Types) > 0 {
+		params["types"] = r.Types.String()
+	}
+
+	return params
+}
+
+// SearchResponse groups search results by entity type. Types that weren't searched are omitted.
+type SearchResponse struct {
+	Movies  *PaginatedResponse[Movie]      `json:"movies,omitempty"`
+	Stars   *PaginatedResponse[Star]       `json:"stars,omitempty"`
+	Reviews *PaginatedResponse[Review]     `json:"reviews,omitempty"`
+	Users   *PaginatedResponse[SearchUser] `json:"users,omitempty"`
+}
+
+// SearchUser is the public part of a user found by search.
+type SearchUser struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Bio       *string   `json:"bio,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -6,61 +6,167 @@ import (
 )
 
 type Star struct {
-	ID        int        `json:"id"`
-	FirstName string     `json:"first_name"`
-	LastName  string     `json:"last_name"`
-	BirthDate time.Time  `json:"birth_date"`
-	DeathDate *time.Time `json:"death_date,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID          int        `json:"id"`
+	DisplayName string     `json:"display_name"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	BirthDate   time.Time  `json:"birth_date"`
+	DeathDate   *time.Time `json:"death_date,omitempty"`
+	Photo       *Image     `json:"photo,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 type StarDetails struct {
 	Star
-	MiddleName *string `json:"middle_name,omitempty"`
-	BirthPlace *string `json:"birth_place,omitempty"`
-	Bio        *string `json:"bio,omitempty"`
+	SortName    string        `json:"sort_name"`
+	Aliases     []*StarAlias  `json:"aliases,omitempty"`
+	MiddleName  *string       `json:"middle_name,omitempty"`
+	BirthPlace  *string       `json:"birth_place,omitempty"`
+	Bio         *string       `json:"bio,omitempty"`
+	ExternalIDs []*ExternalID `json:"external_ids,omitempty"`
+	Version     int           `json:"version"`
 }
 
+// StarAlias is another name the star is known by. Kind is one of birth, stage, nickname or other.
+type StarAlias struct {
+	Name string `json:"name" validate:"nonzero,max=100"`
+	Kind string `json:"kind" validate:"aliasKind"`
+}
+
+// Filmography lists the credits of a star grouped by role, each group in the order the movies were released.
+type Filmography struct {
+	StarID int                `json:"star_id"`
+	Roles  []*FilmographyRole `json:"roles"`
+	Stats  CareerStats        `json:"stats"`
+}
+
+type FilmographyRole struct {
+	Role    string    `json:"role"`
+	Credits []*Credit `json:"credits"`
+}
+
+// Credit is a movie the star has a role in, Details describe the role, e.g. the character played.
+type Credit struct {
+	MovieID     int       `json:"movie_id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	AvgRating   *float64  `json:"avg_rating,omitempty"`
+	Poster      *Image    `json:"poster,omitempty"`
+	Details     *string   `json:"details,omitempty"`
+}
+
+// CareerStats count each movie of the star once, whatever the number of roles in it.
+// AvgRating is the average of the ratings of the rated movies.
+type CareerStats struct {
+	MoviesCount     int      `json:"movies_count"`
+	AvgRating       *float64 `json:"avg_rating,omitempty"`
+	FirstCreditYear *int     `json:"first_credit_year,omitempty"`
+	LastCreditYear  *int     `json:"last_credit_year,omitempty"`
+}
+
+// CreateStarRequest creates a star. DisplayName defaults to the first and last names, e.g. "George Lucas",
+// SortName to the last and first names, e.g. "Lucas, George", a mononym can be given in any of the names.
 type CreateStarRequest struct {
-	FirstName  string     `json:"first_name" validate:"max=50"`
-	MiddleName *string    `json:"middle_name,omitempty" validate:"max=50"`
-	LastName   string     `json:"last_name" validate:"max=50"`
-	BirthDate  time.Time  `json:"birth_date" validate:"nonzero"`
-	BirthPlace *string    `json:"birth_place,omitempty" validate:"max=100"`
-	DeathDate  *time.Time `json:"death_date,omitempty"`
-	Bio        *string    `json:"bio,omitempty"`
+	DisplayName *string       `json:"display_name,omitempty" validate:"max=100"`
+	SortName    *string       `json:"sort_name,omitempty" validate:"max=100"`
+	Aliases     []*StarAlias  `json:"aliases,omitempty"`
+	FirstName   string        `json:"first_name" validate:"max=50"`
+	MiddleName  *string       `json:"middle_name,omitempty" validate:"max=50"`
+	LastName    string        `json:"last_name" validate:"max=50"`
+	BirthDate   time.Time     `json:"birth_date" validate:"nonzero"`
+	BirthPlace  *string       `json:"birth_place,omitempty" validate:"max=100"`
+	DeathDate   *time.Time    `json:"death_date,omitempty"`
+	Bio         *string       `json:"bio,omitempty"`
+	ExternalIDs []*ExternalID `json:"external_ids"`
 }
 
+// GetStarsPaginatedRequest lists the stars. Name finds the stars by display name or alias regardless of case and accents,
+// tolerating typos, and orders them by relevance unless Sort is set. Role keeps the stars credited with it.
 type GetStarsPaginatedRequest struct {
 	PaginatedRequest
-	MovieID *int `query:"movieID"`
+	MovieID  *int       `query:"movieID"`
+	Role     *string    `query:"role"`
+	Name     *string    `query:"name"`
+	BornFrom *time.Time `query:"bornFrom"`
+	BornTo   *time.Time `query:"bornTo"`
+	DiedFrom *time.Time `query:"diedFrom"`
+	DiedTo   *time.Time `query:"diedTo"`
+	Alive    bool       `query:"alive"`
+	Sort     *string    `query:"sort"`
 }
 
 type GetStarRequest struct {
 	StarID int `param:"starID" validate:"nonzero"`
 }
 
+// UpdateStarRequest replaces the fields of the star, aliases included, which are validated and defaulted as on creation.
+// Version must be the current version of the star.
 type UpdateStarRequest struct {
-	StarID     int        `json:"star_id"`
-	FirstName  string     `json:"first_name"`
-	MiddleName *string    `json:"middle_name,omitempty"`
-	LastName   string     `json:"last_name"`
-	BirthDate  time.Time  `json:"birth_date"`
-	BirthPlace *string    `json:"birth_place,omitempty"`
-	DeathDate  *time.Time `json:"death_date,omitempty"`
-	Bio        *string    `json:"bio,omitempty"`
+	StarID      int          `json:"star_id" param:"starID" validate:"nonzero"`
+	Version     int          `json:"version" validate:"min=0"`
+	DisplayName *string      `json:"display_name,omitempty" validate:"max=100"`
+	SortName    *string      `json:"sort_name,omitempty" validate:"max=100"`
+	Aliases     []*StarAlias `json:"aliases,omitempty"`
+	FirstName   string       `json:"first_name" validate:"max=50"`
+	MiddleName  *string      `json:"middle_name,omitempty" validate:"max=50"`
+	LastName    string       `json:"last_name" validate:"max=50"`
+	BirthDate   time.Time    `json:"birth_date" validate:"nonzero"`
+	BirthPlace  *string      `json:"birth_place,omitempty" validate:"max=100"`
+	DeathDate   *time.Time   `json:"death_date,omitempty"`
+	Bio         *string      `json:"bio,omitempty"`
 }
 
 type DeleteStarRequest struct {
 	StarID int `param:"starID" validate:"nonzero"`
 }
 
+// MergeStarsRequest merges the duplicate into the star, the duplicate ID redirects to the star afterwards.
+// Version and DuplicateVersion are the versions the stars are expected to be at.
+type MergeStarsRequest struct {
+	StarID           int `param:"starID" validate:"nonzero"`
+	Version          int `json:"version" validate:"min=0"`
+	DuplicateID      int `json:"duplicate_id" validate:"nonzero"`
+	DuplicateVersion int `json:"duplicate_version" validate:"min=0"`
+}
+
 func (r *GetStarsPaginatedRequest) ToQueryParams() map[string]string {
 	param := r.PaginatedRequest.ToQueryParams()
 	if r.MovieID != nil {
 		param["movieID"] = strconv.Itoa(*r.MovieID)
 	}
 
+	if r.Role != nil {
+		param["role"] = *r.Role
+	}
+
+	if r.Name != nil {
+		param["name"] = *r.Name
+	}
+
+	if r.BornFrom != nil {
+		param["bornFrom"] = r.BornFrom.Format(time.RFC3339)
+	}
+
+	if r.BornTo != nil {
+		param["bornTo"] = r.BornTo.Format(time.RFC3339)
+	}
+
+	if r.DiedFrom != nil {
+		param["diedFrom"] = r.DiedFrom.Format(time.RFC3339)
+	}
+
+	if r.DiedTo != nil {
+		param["diedTo"] = r.DiedTo.Format(time.RFC3339)
+	}
+
+	if r.Alive {
+		param["alive"] = strconv.FormatBool(r.Alive)
+	}
+
+	if r.Sort != nil {
+		param["sort"] = *r.Sort
+	}
+
 	return param
 }
@@ -0,0 +1,32 @@
+package contracts
+
+import "time"
+
+// TrashItem is a soft-deleted movie, star, user or review, it is purged for good at PurgeAt.
+type TrashItem struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// GetTrashRequest lists the deleted entities of one kind: movies, stars, users or reviews.
+type GetTrashRequest struct {
+	PaginatedRequest
+	Entity string  `param:"entity" validate:"nonzero"`
+	Sort   *string `query:"sort"`
+}
+
+func (r *GetTrashRequest) ToQueryParams() map[string]string {
+	params := r.PaginatedRequest.ToQueryParams()
+	if r.Sort != nil {
+		params["sort"] = *r.Sort
+	}
+
+	return params
+}
+
+type RestoreFromTrashRequest struct {
+	Entity string `param:"entity" validate:"nonzero"`
+	ID     int    `param:"id" validate:"nonzero"`
+}
@@ -8,6 +8,7 @@ type User struct {
 	Email     string     `json:"email"`
 	Role      string     `json:"role"`
 	Bio       *string    `json:"bio,omitempty"`
+	Avatar    *Avatar    `json:"avatar,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
@@ -29,3 +30,23 @@ type UpdateUserRoleRequest struct {
 	UserID int    `param:"userID" validate:"nonzero"`
 	Role   string `param:"role" validate:"role"`
 }
+
+type Avatar struct {
+	Small   string `json:"small"`
+	Medium  string `json:"medium"`
+	Large   string `json:"large"`
+	Default bool   `json:"default"`
+}
+
+type UploadUserAvatarRequest struct {
+	UserID int `param:"userID" validate:"nonzero"`
+}
+
+type DeleteUserAvatarRequest struct {
+	UserID int `param:"userID" validate:"nonzero"`
+}
+
+type GetUserIdenticonRequest struct {
+	UserID int `param:"userID" validate:"nonzero"`
+	Size   int `query:"size" validate:"min=0,max=512"`
+}
@@ -18,6 +18,8 @@ const (
 	UnauthorizedCode
 	ForbiddenCode
 	VersionMismatchCode
+	MovedCode
+	PreconditionFailedCode
 )
 
 var _ error = (*Error)(nil)
@@ -26,6 +28,8 @@ type Error struct {
 	Code       Code
 	StackTrace string
 	IncidentID string
+	// Location is where a moved entity is found now
+	Location string
 
 	innerErr error
 	hideErr  bool
@@ -110,6 +114,18 @@ func VersionMismatch(subject, key string, value any, version int) *Error {
 	return newError(VersionMismatchCode, fmt.Sprintf("wrong version %d for %s %s : %v", version, subject, key, value))
 }
 
+// Moved reports an entity merged into another one, which is found at location.
+func Moved(subject, key string, value any, location string) *Error {
+	appErr := newError(MovedCode, fmt.Sprintf("%s %s:%v moved to %s", subject, key, value, location))
+	appErr.Location = location
+	return appErr
+}
+
+// PreconditionFailed reports an entity which no longer matches the representation the client based its request on.
+func PreconditionFailed(subject, key string, value any) *Error {
+	return newError(PreconditionFailedCode, fmt.Sprintf("%s %s:%v has changed", subject, key, value))
+}
+
 func Is(err error, code Code) bool {
 	var appErr *Error
 	return errors.As(err, &appErr) && appErr.Code == code
@@ -8,13 +8,21 @@ import (
 )
 
 type Config struct {
-	DBUrl      string           `env:"DB_URL"`
-	Port       int              `env:"PORT" envDefault:"8080"`
-	Local      bool             `env:"LOCAL" envDefault:"false"`
-	LogLevel   string           `env:"LOG_LEVEL" envDefault:"info"`
-	Jwt        JwtConfig        `envPrefix:"JWT_"`
-	Admin      AdminConfig      `envPrefix:"ADMIN_"`
-	Pagination PaginationConfig `envPrefix:"PAGINATION_"`
+	DBUrl           string                `env:"DB_URL"`
+	Port            int                   `env:"PORT" envDefault:"8080"`
+	Local           bool                  `env:"LOCAL" envDefault:"false"`
+	LogLevel        string                `env:"LOG_LEVEL" envDefault:"info"`
+	Jwt             JwtConfig             `envPrefix:"JWT_"`
+	Admin           AdminConfig           `envPrefix:"ADMIN_"`
+	Pagination      PaginationConfig      `envPrefix:"PAGINATION_"`
+	Recommendations RecommendationsConfig `envPrefix:"RECOMMENDATIONS_"`
+	Search          SearchConfig          `envPrefix:"SEARCH_"`
+	Storage         StorageConfig         `envPrefix:"STORAGE_"`
+	Images          ImagesConfig          `envPrefix:"IMAGES_"`
+	Trash           TrashConfig           `envPrefix:"TRASH_"`
+	// PublicURL is where clients reach the server. Links to the resources of the server start with it,
+	// and so do the URLs of locally stored files unless their public URL is absolute.
+	PublicURL string `env:"PUBLIC_URL" envDefault:"http://localhost:8080"`
 }
 
 type JwtConfig struct {
@@ -33,6 +41,49 @@ type PaginationConfig struct {
 	MaxSize     int `env:"MAX_SIZE" envDefault:"100"`
 }
 
+type RecommendationsConfig struct {
+	RefreshInterval  time.Duration `env:"REFRESH_INTERVAL" envDefault:"1h"`
+	Limit            int           `env:"LIMIT" envDefault:"20"`
+	MinRating        int           `env:"MIN_RATING" envDefault:"7"`
+	TopChartMinVotes int           `env:"TOP_CHART_MIN_VOTES" envDefault:"3"`
+}
+
+type SearchConfig struct {
+	SuggestLimit   int           `env:"SUGGEST_LIMIT" envDefault:"8"`
+	SuggestTimeout time.Duration `env:"SUGGEST_TIMEOUT" envDefault:"300ms"`
+}
+
+// StorageConfig selects where uploaded files are kept: "local" keeps them in LocalDir and serves them
+// at /images, "s3" keeps them in a bucket of an S3 compatible service. A relative PublicURL of local files
+// is resolved against the public URL of the server.
+type StorageConfig struct {
+	Driver    string   `env:"DRIVER" envDefault:"local"`
+	LocalDir  string   `env:"LOCAL_DIR" envDefault:"./data/images"`
+	PublicURL string   `env:"PUBLIC_URL" envDefault:"/images"`
+	S3        S3Config `envPrefix:"S3_"`
+}
+
+type S3Config struct {
+	Endpoint  string `env:"ENDPOINT"`
+	Region    string `env:"REGION" envDefault:"us-east-1"`
+	Bucket    string `env:"BUCKET"`
+	AccessKey string `env:"ACCESS_KEY"`
+	SecretKey string `env:"SECRET_KEY"`
+	// PublicURL is the prefix of the object URLs given to clients, Endpoint/Bucket by default
+	PublicURL string `env:"PUBLIC_URL"`
+}
+
+type ImagesConfig struct {
+	MaxSize        int64 `env:"MAX_SIZE" envDefault:"5242880"`
+	ThumbnailWidth int   `env:"THUMBNAIL_WIDTH" envDefault:"200"`
+}
+
+// TrashConfig sets how long soft-deleted entities are kept before they are purged for good.
+type TrashConfig struct {
+	Retention     time.Duration `env:"RETENTION" envDefault:"720h"`
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL" envDefault:"24h"`
+}
+
 func NewConfig() (*Config, error) {
 	var c Config
 	if err := env.Parse(&c); err != nil {
@@ -6,3 +6,13 @@ import (
 )
 
 var StatementBuilder = squirrel.StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(squirrel.Dollar)
+
+// Exists wraps a sub-query into an EXISTS predicate that can be passed to Where.
+func Exists(sb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return sb.Prefix("EXISTS (").Suffix(")")
+}
+
+// In wraps a sub-query into an IN predicate on the column that can be passed to Where.
+func In(column string, sb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return sb.Prefix(column + " IN (").Suffix(")")
+}
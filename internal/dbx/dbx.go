@@ -18,6 +18,15 @@ func IsUniqueViolation(err error, name string) bool {
 	return false
 }
 
+func IsForeignKeyViolation(err error, name string) bool {
+	var perr *pgconn.PgError
+	if errors.As(err, &perr) {
+		return perr.Code == pgerrcode.ForeignKeyViolation && strings.Contains(perr.ConstraintName, name)
+	}
+
+	return false
+}
+
 func IsNoRows(err error) bool {
 	return errors.Is(err, pgx.ErrNoRows)
 }
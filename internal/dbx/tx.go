@@ -23,7 +23,7 @@ func FromContext(ctx context.Context, def Queryable) Queryable {
 	return def
 }
 
-func InTransaction(ctx context.Context, db *pgxpool.Pool, fn func(ctx context.Context, tx pgx.Tx) error) error {
+func InTransaction(ctx context.Context, db *pgxpool.Pool, fn func(ctx context.Context, tx pgx.Tx) error) (err error) {
 	tx, err := db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction:  %w", err)
@@ -33,12 +33,11 @@ func InTransaction(ctx context.Context, db *pgxpool.Pool, fn func(ctx context.Co
 	defer func() {
 		if err != nil {
 			if txErr := tx.Rollback(ctx); txErr != nil {
-				err = errors.Join(err, fmt.Errorf("rollback transaction: %w", err))
+				err = errors.Join(err, fmt.Errorf("rollback transaction: %w", txErr))
 			}
 		} else {
-			cerr := tx.Commit(ctx)
-			if cerr != nil {
-				err = fmt.Errorf("commit transaction:  %w", err)
+			if cerr := tx.Commit(ctx); cerr != nil {
+				err = fmt.Errorf("commit transaction:  %w", cerr)
 			}
 		}
 	}()
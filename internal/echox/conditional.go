@@ -0,0 +1,94 @@
+package echox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/labstack/echo/v4"
+)
+
+// ETag returns the strong entity tag of v, the hash of its JSON representation.
+func ETag(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", apperrors.Internal(err)
+	}
+
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// JSONConditional sends v as JSON along with its ETag and Last-Modified, which is left out when lastModified is zero.
+// The body is left out too, with 304 Not Modified, when the If-None-Match of the request lists the ETag.
+func JSONConditional(c echo.Context, v any, lastModified time.Time) error {
+	etag, err := ETag(v)
+	if err != nil {
+		return err
+	}
+
+	header := c.Response().Header()
+	header.Set(contracts.HeaderETag, etag)
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if listsETag(c.Request().Header.Get(contracts.HeaderIfNoneMatch), etag, true) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(http.StatusOK, v)
+}
+
+// IfMatch checks the If-Match precondition of the request against the current representation returned by get,
+// which is called only when the request has one. It returns the representation the precondition holds for,
+// nil without a precondition. Entities with versions are then changed in the version returned, so that
+// concurrent changes still fail the precondition.
+func IfMatch[T any](c echo.Context, get func() (*T, error), subject, key string, value any) (*T, error) {
+	ifMatch := c.Request().Header.Get(contracts.HeaderIfMatch)
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	current, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	etag, err := ETag(current)
+	if err != nil {
+		return nil, err
+	}
+	if !listsETag(ifMatch, etag, false) {
+		return nil, apperrors.PreconditionFailed(subject, key, value)
+	}
+
+	return current, nil
+}
+
+// listsETag reports whether the header, a list of entity tags or *, lists the etag. If-None-Match compares
+// weakly, ignoring the W/ prefix of weak tags, If-Match compares strongly and so never matches them.
+func listsETag(header, etag string, weak bool) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
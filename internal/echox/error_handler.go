@@ -41,6 +41,10 @@ func ErrorHandler(err error, c echo.Context) {
 		)
 	}
 
+	if appError.Location != "" {
+		c.Response().Header().Set(echo.HeaderLocation, appError.Location)
+	}
+
 	if err = c.JSON(toHTTPStatus(appError.Code), httpError); err != nil {
 		logger.Error(
 			"server error",
@@ -63,6 +67,10 @@ func toHTTPStatus(code apperrors.Code) int {
 		return http.StatusUnauthorized
 	case apperrors.ForbiddenCode:
 		return http.StatusForbidden
+	case apperrors.MovedCode:
+		return http.StatusMovedPermanently
+	case apperrors.PreconditionFailedCode:
+		return http.StatusPreconditionFailed
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1,6 +1,13 @@
 package echox
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/boichique/movie-reviews/contracts"
 	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/labstack/echo/v4"
 	"gopkg.in/validator.v2"
@@ -18,3 +25,47 @@ func BindAndValidate[T any](c echo.Context) (*T, error) {
 
 	return req, nil
 }
+
+// BindPatchAndValidate is BindAndValidate accepting bodies sent as application/merge-patch+json, which are bound as JSON.
+func BindPatchAndValidate[T any](c echo.Context) (*T, error) {
+	header := c.Request().Header
+	if mime, _, _ := strings.Cut(header.Get(echo.HeaderContentType), ";"); strings.TrimSpace(mime) == contracts.MIMEApplicationMergePatchJSON {
+		header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+
+	return BindAndValidate[T](c)
+}
+
+// ReadFile reads the file uploaded as the field of a multipart form, files larger than maxSize bytes are rejected.
+func ReadFile(c echo.Context, field string, maxSize int64) ([]byte, error) {
+	// the rest of the form is small, the limit only keeps huge uploads from being read at all
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxSize+1<<20)
+
+	header, err := c.FormFile(field)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, apperrors.BadRequest(fmt.Errorf("%s is larger than %d bytes", field, maxSize))
+		}
+		return nil, apperrors.BadRequestHidden(err, fmt.Sprintf("%s file is required", field))
+	}
+	if header.Size > maxSize {
+		return nil, apperrors.BadRequest(fmt.Errorf("%s is larger than %d bytes", field, maxSize))
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, apperrors.BadRequest(fmt.Errorf("%s is larger than %d bytes", field, maxSize))
+	}
+
+	return data, nil
+}
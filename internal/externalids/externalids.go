@@ -0,0 +1,154 @@
+package externalids
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/dbx"
+)
+
+// ID identifies an entity in an outside catalog, e.g. imdb:tt0076759.
+type ID struct {
+	Source string `json:"source"`
+	ID     string `json:"id"`
+}
+
+// FromContracts normalizes the sources to lower case and rejects more than one ID of the same source.
+func FromContracts(ids []*contracts.ExternalID) ([]*ID, error) {
+	result := make([]*ID, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		source := NormalizeSource(id.Source)
+		if seen[source] {
+			return nil, apperrors.BadRequest(fmt.Errorf("more than one external id of source %q", source))
+		}
+		seen[source] = true
+		result = append(result, &ID{Source: source, ID: id.ID})
+	}
+
+	return result, nil
+}
+
+func NormalizeSource(source string) string {
+	return strings.ToLower(strings.TrimSpace(source))
+}
+
+// Table maps the entities of a table to their external IDs.
+type Table struct {
+	// Name of the table holding the external IDs, e.g. movie_external_ids
+	Name string
+	// Owners is the table of the entities, e.g. movies
+	Owners string
+	// OwnerColumn references the entity, e.g. movie_id
+	OwnerColumn string
+	// Subject names the entity in errors, e.g. movie
+	Subject string
+}
+
+// Insert adds the external IDs of the entity. An ID that belongs to a deleted entity is taken over, as deleted
+// entities don't hold their IDs, an ID that already belongs to another entity is reported as AlreadyExists.
+func (t *Table) Insert(ctx context.Context, q dbx.Queryable, ownerID int, ids []*ID) error {
+	for _, id := range ids {
+		if err := t.insert(ctx, q, ownerID, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Put sets the ID of the entity in the source of the ID, replacing the one it had. It has to run in a transaction.
+func (t *Table) Put(ctx context.Context, q dbx.Queryable, ownerID int, id *ID) error {
+	_, err := q.Exec(
+		ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND source = $2 AND external_id <> $3`, t.Name, t.OwnerColumn),
+		ownerID, id.Source, id.ID,
+	)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	return t.insert(ctx, q, ownerID, id)
+}
+
+func (t *Table) insert(ctx context.Context, q dbx.Queryable, ownerID int, id *ID) error {
+	n, err := q.Exec(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO %[1]s (%[3]s, source, external_id) VALUES ($1, $2, $3)
+			ON CONFLICT (source, external_id) DO UPDATE
+			SET %[3]s = excluded.%[3]s
+			WHERE %[1]s.%[3]s = excluded.%[3]s
+			OR EXISTS (SELECT 1 FROM %[2]s o WHERE o.id = %[1]s.%[3]s AND o.deleted_at IS NOT NULL)`,
+			t.Name, t.Owners, t.OwnerColumn,
+		),
+		ownerID, id.Source, id.ID,
+	)
+	switch {
+	case err != nil:
+		return apperrors.Internal(err)
+	case n.RowsAffected() == 0:
+		return apperrors.AlreadyExists(t.Subject, id.Source, id.ID)
+	}
+
+	return nil
+}
+
+// Get returns the external IDs of the entity ordered by source.
+func (t *Table) Get(ctx context.Context, q dbx.Queryable, ownerID int) ([]*ID, error) {
+	rows, err := q.Query(
+		ctx,
+		fmt.Sprintf(`SELECT source, external_id FROM %s WHERE %s = $1 ORDER BY source`, t.Name, t.OwnerColumn),
+		ownerID,
+	)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var ids []*ID
+	for rows.Next() {
+		var id ID
+		if err = rows.Scan(&id.Source, &id.ID); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		ids = append(ids, &id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return ids, nil
+}
+
+// Resolve returns the ID of the entity the external ID belongs to. Deleted entities are not found.
+func (t *Table) Resolve(ctx context.Context, q dbx.Queryable, source, externalID string) (int, error) {
+	var ownerID int
+	err := q.
+		QueryRow(
+			ctx,
+			fmt.Sprintf(
+				`SELECT e.%[3]s
+				FROM %[1]s e
+				INNER JOIN %[2]s o ON o.id = e.%[3]s
+				WHERE e.source = $1
+				AND e.external_id = $2
+				AND o.deleted_at IS NULL`,
+				t.Name, t.Owners, t.OwnerColumn,
+			),
+			source, externalID,
+		).
+		Scan(&ownerID)
+	switch {
+	case dbx.IsNoRows(err):
+		return 0, apperrors.NotFound(t.Subject, source, externalID)
+	case err != nil:
+		return 0, apperrors.Internal(err)
+	}
+
+	return ownerID, nil
+}
@@ -0,0 +1,61 @@
+package images
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+)
+
+// identiconCells is the number of cells in a row of an identicon, the image has a margin of half a cell
+const identiconCells = 5
+
+var identiconBackground = color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff}
+
+// Identicon generates a PNG of size x size pixels with a horizontally symmetric pattern of cells derived from the seed,
+// so the same seed always gets the same picture.
+func Identicon(seed string, size int) ([]byte, error) {
+	hash := sha256.Sum256([]byte(seed))
+	// the first bytes pick the color, kept away from white to contrast with the background
+	fg := color.RGBA{R: 0x20 + hash[0]%0xa0, G: 0x20 + hash[1]%0xa0, B: 0x20 + hash[2]%0xa0, A: 0xff}
+
+	// the pattern is mirrored, so only the cells of the left half and the middle column are picked
+	var filled [identiconCells][identiconCells]bool
+	half := (identiconCells + 1) / 2
+	for y := 0; y < identiconCells; y++ {
+		for x := 0; x < half; x++ {
+			bit := y*half + x
+			on := hash[3+bit/8]&(1<<(bit%8)) != 0
+			filled[y][x] = on
+			filled[y][identiconCells-1-x] = on
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	// cell coordinates of a pixel, shifted by the margin
+	cell := func(p int) (int, bool) {
+		c := float64(p)*float64(identiconCells+1)/float64(size) - 0.5
+		return int(c), c >= 0 && int(c) < identiconCells
+	}
+	for py := 0; py < size; py++ {
+		y, okY := cell(py)
+		for px := 0; px < size; px++ {
+			x, okX := cell(px)
+			if okX && okY && filled[y][x] {
+				img.SetRGBA(px, py, fg)
+			} else {
+				img.SetRGBA(px, py, identiconBackground)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("encode identicon: %w", err))
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,267 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+	"path"
+	"strings"
+
+	// decoders of the accepted formats
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/log"
+	"github.com/boichique/movie-reviews/internal/storage"
+	"github.com/google/uuid"
+)
+
+// Image is a stored picture along with its thumbnail.
+type Image struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// Stored is an image put into the storage, Key locates the original, the thumbnail key is derived from it.
+type Stored struct {
+	Image
+	Key string
+}
+
+const (
+	// maxPixels guards against images that are small files but huge bitmaps
+	maxPixels   = 40_000_000
+	jpegQuality = 85
+)
+
+// extensions of the accepted content types
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+type Service struct {
+	storage storage.Storage
+	cfg     config.ImagesConfig
+}
+
+func NewService(storage storage.Storage, cfg config.ImagesConfig) *Service {
+	return &Service{
+		storage: storage,
+		cfg:     cfg,
+	}
+}
+
+func (s *Service) MaxSize() int64 {
+	return s.cfg.MaxSize
+}
+
+// Store validates the image, generates its thumbnail and puts both into the storage under the prefix, e.g. movies/1.
+func (s *Service) Store(ctx context.Context, prefix string, data []byte) (*Stored, error) {
+	img, contentType, err := s.decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail, err := encodeJPEG(thumbnail(img, s.cfg.ThumbnailWidth))
+	if err != nil {
+		return nil, err
+	}
+
+	key := path.Join(prefix, uuid.New().String()+extensions[contentType])
+	if err = s.storage.Put(ctx, key, contentType, data); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	if err = s.storage.Put(ctx, thumbnailKey(key), "image/jpeg", thumbnail); err != nil {
+		s.Delete(ctx, key)
+		return nil, apperrors.Internal(err)
+	}
+
+	return &Stored{
+		Image: Image{
+			URL:          s.storage.URL(key),
+			ThumbnailURL: s.storage.URL(thumbnailKey(key)),
+		},
+		Key: key,
+	}, nil
+}
+
+// StoreSquare validates the image, crops its center to a square and puts a JPEG of every size into the storage
+// under the prefix. It returns the key the objects share, SquareURL locates them.
+func (s *Service) StoreSquare(ctx context.Context, prefix string, data []byte, sizes []int) (string, error) {
+	img, _, err := s.decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	key := path.Join(prefix, uuid.New().String())
+	for i, size := range sizes {
+		var encoded []byte
+		encoded, err = encodeJPEG(square(img, size))
+		if err == nil {
+			err = s.storage.Put(ctx, squareKey(key, size), "image/jpeg", encoded)
+		}
+		if err != nil {
+			s.DeleteSquare(ctx, key, sizes[:i])
+			return "", apperrors.EnsureInternal(err)
+		}
+	}
+
+	return key, nil
+}
+
+func (s *Service) SquareURL(key string, size int) string {
+	return s.storage.URL(squareKey(key, size))
+}
+
+// DeleteSquare removes the objects stored by StoreSquare. Failures are only logged.
+func (s *Service) DeleteSquare(ctx context.Context, key string, sizes []int) {
+	for _, size := range sizes {
+		if err := s.storage.Delete(ctx, squareKey(key, size)); err != nil {
+			log.FromContext(ctx).Error("failed to delete image", "key", squareKey(key, size), "error", err)
+		}
+	}
+}
+
+// decode checks the size and the sniffed content type of the image before decoding it.
+func (s *Service) decode(data []byte) (image.Image, string, error) {
+	if int64(len(data)) > s.cfg.MaxSize {
+		return nil, "", apperrors.BadRequest(fmt.Errorf("image is larger than %d bytes", s.cfg.MaxSize))
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := extensions[contentType]; !ok {
+		return nil, "", apperrors.BadRequest(fmt.Errorf("unsupported image type %q, expected JPEG, PNG or GIF", contentType))
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", apperrors.BadRequest(fmt.Errorf("invalid image: %w", err))
+	}
+	if cfg.Width == 0 || cfg.Height == 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, "", apperrors.BadRequest(fmt.Errorf("image of %dx%d pixels is too large", cfg.Width, cfg.Height))
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", apperrors.BadRequest(fmt.Errorf("invalid image: %w", err))
+	}
+
+	return img, contentType, nil
+}
+
+// Delete removes the image and its thumbnail. Failures are only logged, a leftover object does no harm.
+func (s *Service) Delete(ctx context.Context, key string) {
+	for _, k := range []string{key, thumbnailKey(key)} {
+		if err := s.storage.Delete(ctx, k); err != nil {
+			log.FromContext(ctx).Error("failed to delete image", "key", k, "error", err)
+		}
+	}
+}
+
+// Select builds the column expression of the image stored in the <name>_url and <name>_thumbnail_url columns,
+// which scans into *Image, nil when there is no image.
+func Select(name string) string {
+	return fmt.Sprintf(
+		"CASE WHEN %[1]s_url IS NULL THEN NULL ELSE json_build_object('url', %[1]s_url, 'thumbnail_url', %[1]s_thumbnail_url) END",
+		name,
+	)
+}
+
+func thumbnailKey(key string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "-thumb.jpg"
+}
+
+func squareKey(key string, size int) string {
+	return fmt.Sprintf("%s-%d.jpg", key, size)
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, apperrors.Internal(fmt.Errorf("encode image: %w", err))
+	}
+	return buf.Bytes(), nil
+}
+
+// thumbnail scales the image down to the width keeping its aspect ratio.
+// Images not wider than width are only flattened.
+func thumbnail(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	flat := flatten(src, b)
+	if b.Dx() <= width {
+		return flat
+	}
+
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	return scale(flat, width, height)
+}
+
+// square crops the largest centered square of the image and scales it to size.
+func square(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+
+	corner := b.Min.Add(image.Pt((b.Dx()-side)/2, (b.Dy()-side)/2))
+	return scale(flatten(src, image.Rectangle{Min: corner, Max: corner.Add(image.Pt(side, side))}), size, size)
+}
+
+// flatten copies the part of the image onto a white background, as JPEG has no alpha channel.
+func flatten(src image.Image, r image.Rectangle) *image.RGBA {
+	flat := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, r.Min, draw.Over)
+	return flat
+}
+
+// scale resizes the image averaging the source pixels each target pixel covers,
+// when enlarging every target pixel takes the nearest source pixel.
+func scale(src *image.RGBA, width, height int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*b.Dy()/height, (y+1)*b.Dy()/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*b.Dx()/width, (x+1)*b.Dx()/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					bl += uint32(src.Pix[i+2])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+
+	return dst
+}
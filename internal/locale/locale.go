@@ -0,0 +1,58 @@
+package locale
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Chain returns the locales to look translations up in, the most preferred first. An explicit locale takes
+// precedence over the Accept-Language header, and region-specific locales fall back to their language,
+// e.g. "pt-BR" gives "pt-br" and "pt". Locales are lowercase. An empty chain means the original is wanted.
+func Chain(explicit, acceptLanguage string) []string {
+	var tags []language.Tag
+	if explicit != "" {
+		tag, err := language.Parse(explicit)
+		if err == nil {
+			tags = append(tags, tag)
+		}
+	} else if acceptLanguage != "" {
+		// a malformed header is ignored rather than failing the request
+		tags, _, _ = language.ParseAcceptLanguage(acceptLanguage)
+	}
+
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(locale string) {
+		if locale == "" || locale == "und" || seen[locale] {
+			return
+		}
+		seen[locale] = true
+		chain = append(chain, locale)
+	}
+
+	for _, tag := range tags {
+		add(canonical(tag))
+		base, confidence := tag.Base()
+		if confidence != language.No {
+			add(strings.ToLower(base.String()))
+		}
+	}
+
+	return chain
+}
+
+// Canonical returns the locale as translations are stored and looked up, e.g. "PT_br" gives "pt-br"
+// and the deprecated "iw" gives "he". The locale must be a valid BCP 47 tag.
+func Canonical(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return strings.ToLower(locale)
+	}
+
+	return canonical(tag)
+}
+
+func canonical(tag language.Tag) string {
+	return strings.ToLower(tag.String())
+}
@@ -0,0 +1,72 @@
+// Package mergepatch applies JSON Merge Patch documents (RFC 7386).
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// ApplyTo patches the JSON form of v, which must be a pointer to a struct, in place.
+// The patch must be an object and members unknown to v are rejected.
+func ApplyTo(v any, patch []byte) error {
+	var p any
+	if err := unmarshal(patch, &p); err != nil {
+		return err
+	}
+	if _, ok := p.(map[string]any); !ok {
+		return errors.New("merge patch must be a JSON object")
+	}
+
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var target any
+	if err = unmarshal(doc, &target); err != nil {
+		return err
+	}
+	patched, err := json.Marshal(merge(target, p))
+	if err != nil {
+		return err
+	}
+
+	// removed members must not keep their values
+	elem := reflect.ValueOf(v).Elem()
+	elem.Set(reflect.Zero(elem.Type()))
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// merge merges the patch into the target recursively, null removes a member of an object
+// and any other value, arrays included, replaces it.
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = merge(t[key], value)
+	}
+
+	return t
+}
+
+// unmarshal keeps numbers as they are, so that large integers survive the round trip.
+func unmarshal(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,111 @@
+package collections
+
+import (
+	"net/http"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/sorting"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.CreateCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+
+	collection := &Collection{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err = h.service.Create(c.Request().Context(), collection); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, collection)
+}
+
+func (h *Handler) GetCollectionsPaginated(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetCollectionsPaginatedRequest](c)
+	if err != nil {
+		return err
+	}
+
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+
+	orders, err := sorting.Parse(req.Sort, sortFields)
+	if err != nil {
+		return err
+	}
+	page, err := pagination.NewPage(&req.PaginatedRequest, sorting.WithTiebreaker(orders, sortTiebreaker))
+	if err != nil {
+		return err
+	}
+
+	collections, err := h.service.GetCollectionsPaginated(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, collections))
+}
+
+func (h *Handler) GetByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+
+	collection, err := h.service.GetByID(c.Request().Context(), req.CollectionID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, collection)
+}
+
+func (h *Handler) Update(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+
+	return h.service.Update(c.Request().Context(), &Collection{
+		ID:          req.CollectionID,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteCollectionRequest](c)
+	if err != nil {
+		return err
+	}
+
+	return h.service.Delete(c.Request().Context(), req.CollectionID)
+}
+
+func (h *Handler) SetMovies(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.SetCollectionMoviesRequest](c)
+	if err != nil {
+		return err
+	}
+
+	return h.service.SetMovies(c.Request().Context(), req.CollectionID, req.MovieIDs)
+}
@@ -0,0 +1,64 @@
+package collections
+
+import (
+	"time"
+
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/sorting"
+)
+
+var (
+	sortFields = sorting.Fields{
+		"id":         "collections.id",
+		"name":       "collections.name",
+		"created_at": "collections.created_at",
+	}
+	sortTiebreaker = sorting.Order{Field: "id", Expr: "collections.id"}
+)
+
+type Collection struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CollectionDetails holds the movies of the collection in their order, and the rating over all of their reviews.
+type CollectionDetails struct {
+	Collection
+	AvgRating    *float64           `json:"avg_rating,omitempty"`
+	RatingsCount int                `json:"ratings_count"`
+	Movies       []*CollectionMovie `json:"movies"`
+}
+
+type CollectionMovie struct {
+	ID          int           `json:"id"`
+	Title       string        `json:"title"`
+	ReleaseDate time.Time     `json:"release_date"`
+	AvgRating   *float64      `json:"avg_rating,omitempty"`
+	Poster      *images.Image `json:"poster,omitempty"`
+}
+
+// MovieCollection refers to the collection of a movie, Position is the 1-based place of the movie in it.
+type MovieCollection struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+type CollectionMovieRelation struct {
+	CollectionID int
+	MovieID      int
+	OrderNo      int
+}
+
+func (m CollectionMovieRelation) Key() any {
+	type CollectionMovieRelationKey struct {
+		CollectionID, MovieID int
+	}
+
+	return CollectionMovieRelationKey{
+		CollectionID: m.CollectionID,
+		MovieID:      m.MovieID,
+	}
+}
@@ -0,0 +1,397 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/dbx"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/slices"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, collection *Collection) error {
+	err := r.db.
+		QueryRow(
+			ctx,
+			`INSERT INTO collections (name, description)
+			VALUES ($1, $2)
+			RETURNING id, created_at`,
+			collection.Name, collection.Description,
+		).
+		Scan(
+			&collection.ID,
+			&collection.CreatedAt,
+		)
+
+	switch {
+	case dbx.IsUniqueViolation(err, "name"):
+		return apperrors.AlreadyExists("collection", "name", collection.Name)
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetCollectionsPaginated(ctx context.Context, page *pagination.Page) (*pagination.Result[Collection], error) {
+	b := &pgx.Batch{}
+	selectQuery := dbx.StatementBuilder.
+		Select("id, name, description, created_at").
+		From("collections")
+
+	if err := dbx.QueueBatchSelect(b, page.Apply(selectQuery)); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	if page.WithTotal {
+		countQuery := dbx.StatementBuilder.
+			Select("COUNT(*)").
+			From("collections")
+		if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+	}
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	res, err := pagination.Collect(rows, page, func(collection *Collection) []any {
+		return []any{
+			&collection.ID,
+			&collection.Name,
+			&collection.Description,
+			&collection.CreatedAt,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if page.WithTotal {
+		var total int
+		if err = br.QueryRow().Scan(&total); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		res.Total = &total
+	}
+
+	return res, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, collectionID int) (*CollectionDetails, error) {
+	var collection CollectionDetails
+
+	err := r.db.
+		QueryRow(
+			ctx,
+			`SELECT id, name, description, created_at
+			FROM collections
+			WHERE id = $1`,
+			collectionID,
+		).
+		Scan(
+			&collection.ID,
+			&collection.Name,
+			&collection.Description,
+			&collection.CreatedAt,
+		)
+
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, errCollectionWithNotFound(collectionID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	err = r.db.
+		QueryRow(
+			ctx,
+			`SELECT AVG(r.rating)::float8, COUNT(r.id)
+			FROM reviews r
+			INNER JOIN collection_movies cm ON cm.movie_id = r.movie_id
+			INNER JOIN movies m ON m.id = r.movie_id
+			WHERE cm.collection_id = $1
+			AND r.deleted_at IS NULL
+			AND m.deleted_at IS NULL`,
+			collectionID,
+		).
+		Scan(
+			&collection.AvgRating,
+			&collection.RatingsCount,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT m.id, m.title, m.release_date, m.avg_rating, `+images.Select("poster")+`
+			FROM collection_movies cm
+			INNER JOIN movies m ON m.id = cm.movie_id
+			WHERE cm.collection_id = $1
+			AND m.deleted_at IS NULL
+			ORDER BY cm.order_no`,
+			collectionID,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	collection.Movies = []*CollectionMovie{}
+	for rows.Next() {
+		var movie CollectionMovie
+		if err = rows.Scan(&movie.ID, &movie.Title, &movie.ReleaseDate, &movie.AvgRating, &movie.Poster); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+
+		collection.Movies = append(collection.Movies, &movie)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return &collection, nil
+}
+
+// GetByMovieID returns the collection the movie belongs to, nil when there is none.
+// The position counts only the movies which are not deleted.
+func (r *Repository) GetByMovieID(ctx context.Context, movieID int) (*MovieCollection, error) {
+	var collection MovieCollection
+
+	err := r.db.
+		QueryRow(
+			ctx,
+			`SELECT c.id, c.name, (SELECT COUNT(*)
+				FROM collection_movies prev
+				INNER JOIN movies m ON m.id = prev.movie_id
+				WHERE prev.collection_id = c.id
+				AND prev.order_no <= cm.order_no
+				AND m.deleted_at IS NULL)
+			FROM collection_movies cm
+			INNER JOIN collections c ON c.id = cm.collection_id
+			WHERE cm.movie_id = $1`,
+			movieID,
+		).
+		Scan(
+			&collection.ID,
+			&collection.Name,
+			&collection.Position,
+		)
+
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, nil
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	return &collection, nil
+}
+
+func (r *Repository) Update(ctx context.Context, collection *Collection) error {
+	n, err := r.db.
+		Exec(
+			ctx,
+			`UPDATE collections
+			SET name = $1,
+			description = $2
+			WHERE id = $3`,
+			collection.Name,
+			collection.Description,
+			collection.ID,
+		)
+
+	switch {
+	case dbx.IsUniqueViolation(err, "name"):
+		return apperrors.AlreadyExists("collection", "name", collection.Name)
+	case err != nil:
+		return apperrors.Internal(err)
+	case n.RowsAffected() == 0:
+		return errCollectionWithNotFound(collection.ID)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, collectionID int) error {
+	n, err := r.db.
+		Exec(
+			ctx,
+			`DELETE FROM collections
+			WHERE id = $1`,
+			collectionID,
+		)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	if n.RowsAffected() == 0 {
+		return errCollectionWithNotFound(collectionID)
+	}
+
+	return nil
+}
+
+// SetMovies replaces the movies of the collection keeping the order of movieIDs.
+// The movies must exist and must not belong to another collection.
+func (r *Repository) SetMovies(ctx context.Context, collectionID int, movieIDs []int) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.
+			Exec(
+				ctx,
+				`SELECT 1
+				FROM collections
+				WHERE id = $1 FOR UPDATE`,
+				collectionID,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return errCollectionWithNotFound(collectionID)
+		}
+
+		if err = r.checkMovies(ctx, tx, collectionID, movieIDs); err != nil {
+			return err
+		}
+
+		current, err := r.getRelations(ctx, tx, collectionID)
+		if err != nil {
+			return err
+		}
+
+		next := slices.MapIndex(movieIDs, func(i int, movieID int) CollectionMovieRelation {
+			return CollectionMovieRelation{
+				CollectionID: collectionID,
+				MovieID:      movieID,
+				OrderNo:      i,
+			}
+		})
+
+		return r.updateMovies(ctx, tx, current, next)
+	})
+
+	switch {
+	case dbx.IsUniqueViolation(err, "movie_id"):
+		// a concurrent request added one of the movies to another collection
+		return apperrors.BadRequest(fmt.Errorf("movies already belong to another collection: %w", err))
+	case err != nil:
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+func (r *Repository) checkMovies(ctx context.Context, tx pgx.Tx, collectionID int, movieIDs []int) error {
+	rows, err := tx.
+		Query(
+			ctx,
+			`SELECT id
+			FROM movies
+			WHERE id = ANY($1)
+			AND deleted_at IS NULL`,
+			movieIDs,
+		)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	for _, movieID := range movieIDs {
+		if !slices.Contains(existing, movieID) {
+			return apperrors.NotFound("movie", "id", movieID)
+		}
+	}
+
+	var movieID, otherID int
+	err = tx.
+		QueryRow(
+			ctx,
+			`SELECT movie_id, collection_id
+			FROM collection_movies
+			WHERE movie_id = ANY($1)
+			AND collection_id <> $2
+			LIMIT 1`,
+			movieIDs, collectionID,
+		).
+		Scan(&movieID, &otherID)
+
+	switch {
+	case dbx.IsNoRows(err):
+		return nil
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+
+	return apperrors.BadRequest(fmt.Errorf("movie %d already belongs to collection %d", movieID, otherID))
+}
+
+func (r *Repository) getRelations(ctx context.Context, tx pgx.Tx, collectionID int) ([]CollectionMovieRelation, error) {
+	rows, err := tx.
+		Query(
+			ctx,
+			`SELECT collection_id, movie_id, order_no
+			FROM collection_movies
+			WHERE collection_id = $1`,
+			collectionID,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	relations, err := pgx.CollectRows(rows, pgx.RowToStructByPos[CollectionMovieRelation])
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return relations, nil
+}
+
+func (r *Repository) updateMovies(ctx context.Context, tx pgx.Tx, current, next []CollectionMovieRelation) error {
+	addFunc := func(cm CollectionMovieRelation) error {
+		_, err := tx.Exec(
+			ctx,
+			`INSERT INTO collection_movies (collection_id, movie_id, order_no)
+			VALUES ($1, $2, $3)`,
+			cm.CollectionID, cm.MovieID, cm.OrderNo)
+		return err
+	}
+
+	removeFn := func(cm CollectionMovieRelation) error {
+		_, err := tx.Exec(
+			ctx,
+			`DELETE FROM collection_movies
+			WHERE collection_id = $1
+			AND movie_id = $2`,
+			cm.CollectionID, cm.MovieID)
+		return err
+	}
+
+	return dbx.AdjustRelations(current, next, addFunc, removeFn)
+}
+
+func errCollectionWithNotFound(collectionID int) error {
+	return apperrors.NotFound("collection", "id", collectionID)
+}
@@ -0,0 +1,93 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/log"
+	"github.com/boichique/movie-reviews/internal/pagination"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) Create(ctx context.Context, collection *Collection) error {
+	if err := s.repo.Create(ctx, collection); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"collection created",
+		"collectionID", collection.ID,
+		"collectionName", collection.Name,
+	)
+
+	return nil
+}
+
+func (s *Service) GetCollectionsPaginated(ctx context.Context, page *pagination.Page) (*pagination.Result[Collection], error) {
+	return s.repo.GetCollectionsPaginated(ctx, page)
+}
+
+func (s *Service) GetByID(ctx context.Context, collectionID int) (*CollectionDetails, error) {
+	return s.repo.GetByID(ctx, collectionID)
+}
+
+func (s *Service) GetByMovieID(ctx context.Context, movieID int) (*MovieCollection, error) {
+	return s.repo.GetByMovieID(ctx, movieID)
+}
+
+func (s *Service) Update(ctx context.Context, collection *Collection) error {
+	if err := s.repo.Update(ctx, collection); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"collection updated",
+		"collectionID", collection.ID,
+		"collectionName", collection.Name,
+	)
+
+	return nil
+}
+
+func (s *Service) Delete(ctx context.Context, collectionID int) error {
+	if err := s.repo.Delete(ctx, collectionID); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"collection deleted",
+		"collectionID", collectionID,
+	)
+
+	return nil
+}
+
+func (s *Service) SetMovies(ctx context.Context, collectionID int, movieIDs []int) error {
+	seen := make(map[int]bool, len(movieIDs))
+	for _, movieID := range movieIDs {
+		if seen[movieID] {
+			return apperrors.BadRequest(fmt.Errorf("movie %d is listed more than once", movieID))
+		}
+		seen[movieID] = true
+	}
+
+	if err := s.repo.SetMovies(ctx, collectionID, movieIDs); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"collection movies updated",
+		"collectionID", collectionID,
+		"movieIDs", movieIDs,
+	)
+
+	return nil
+}
@@ -50,7 +50,7 @@ func (h *Handler) GetByID(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, genre)
+	return echox.JSONConditional(c, genre, genre.UpdatedAt)
 }
 
 func (h *Handler) UpdateName(c echo.Context) error {
@@ -59,6 +59,9 @@ func (h *Handler) UpdateName(c echo.Context) error {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.GenreID), "genre", "id", req.GenreID); err != nil {
+		return err
+	}
 	return h.service.Update(c.Request().Context(), req.GenreID, req.Name)
 }
 
@@ -68,5 +71,15 @@ func (h *Handler) Delete(c echo.Context) error {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.GenreID), "genre", "id", req.GenreID); err != nil {
+		return err
+	}
 	return h.service.Delete(c.Request().Context(), req.GenreID)
 }
+
+// current returns the getter of the genre as GET sends it to the client, which If-Match is checked against.
+func (h *Handler) current(c echo.Context, genreID int) func() (*Genre, error) {
+	return func() (*Genre, error) {
+		return h.service.GetByID(c.Request().Context(), genreID)
+	}
+}
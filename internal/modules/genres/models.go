@@ -1,8 +1,11 @@
 package genres
 
+import "time"
+
 type Genre struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"-"`
 }
 
 type MovieGenreRelation struct {
@@ -47,7 +47,7 @@ func (r *Repository) GetAll(ctx context.Context) ([]*Genre, error) {
 	rows, err := r.db.
 		Query(
 			ctx,
-			`SELECT id, name
+			`SELECT id, name, updated_at
 			FROM genres;`,
 		)
 	if err != nil {
@@ -64,7 +64,7 @@ func (r *Repository) GetByID(ctx context.Context, genreID int) (*Genre, error) {
 	err := r.db.
 		QueryRow(
 			ctx,
-			`SELECT id, name
+			`SELECT id, name, updated_at
 			FROM genres
 			WHERE id = $1;`,
 			genreID,
@@ -72,6 +72,7 @@ func (r *Repository) GetByID(ctx context.Context, genreID int) (*Genre, error) {
 		Scan(
 			&genre.ID,
 			&genre.Name,
+			&genre.UpdatedAt,
 		)
 
 	switch {
@@ -88,7 +89,7 @@ func (r *Repository) GetByMovieID(ctx context.Context, movieID int) ([]*Genre, e
 	rows, err := r.db.
 		Query(
 			ctx,
-			`SELECT g.id, g.name 
+			`SELECT g.id, g.name, g.updated_at
 			FROM genres g
 			INNER JOIN movie_genres mg ON mg.genre_id = g.id
 			WHERE mg.movie_id = $1
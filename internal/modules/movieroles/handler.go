@@ -0,0 +1,122 @@
+package movieroles
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.CreateMovieRoleRequest](c)
+	if err != nil {
+		return err
+	}
+
+	name, err := normalizeName(req.Name)
+	if err != nil {
+		return err
+	}
+
+	role := &Role{
+		Name:     name,
+		Category: req.Category,
+	}
+	if err = h.service.Create(c.Request().Context(), role); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, role)
+}
+
+func (h *Handler) GetRoles(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetMovieRolesRequest](c)
+	if err != nil {
+		return err
+	}
+
+	roles, err := h.service.GetAll(c.Request().Context(), req.Category)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, roles)
+}
+
+func (h *Handler) GetByID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteMovieRoleRequest](c)
+	if err != nil {
+		return err
+	}
+
+	role, err := h.service.GetByID(c.Request().Context(), req.RoleID)
+	if err != nil {
+		return err
+	}
+
+	return echox.JSONConditional(c, role, role.UpdatedAt)
+}
+
+func (h *Handler) Update(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.UpdateMovieRoleRequest](c)
+	if err != nil {
+		return err
+	}
+
+	name, err := normalizeName(req.Name)
+	if err != nil {
+		return err
+	}
+
+	if _, err = echox.IfMatch(c, h.current(c, req.RoleID), "role", "id", req.RoleID); err != nil {
+		return err
+	}
+	return h.service.Update(c.Request().Context(), &Role{
+		ID:       req.RoleID,
+		Name:     name,
+		Category: req.Category,
+	})
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetOrDeleteMovieRoleRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if _, err = echox.IfMatch(c, h.current(c, req.RoleID), "role", "id", req.RoleID); err != nil {
+		return err
+	}
+	return h.service.Delete(c.Request().Context(), req.RoleID)
+}
+
+// current returns the getter of the role as GET sends it to the client, which If-Match is checked against.
+func (h *Handler) current(c echo.Context, roleID int) func() (*Role, error) {
+	return func() (*Role, error) {
+		return h.service.GetByID(c.Request().Context(), roleID)
+	}
+}
+
+// normalizeName lower cases the name, as the names of the roles are, e.g. "voice actor".
+// The length is checked again, as the spaces collapsed may leave the name too short.
+func normalizeName(name string) (string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if utf8.RuneCountInString(name) < 2 {
+		return "", apperrors.BadRequest(errors.New("name should have at least 2 characters besides spaces"))
+	}
+
+	return name, nil
+}
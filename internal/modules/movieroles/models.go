@@ -0,0 +1,18 @@
+package movieroles
+
+import "time"
+
+// Categories of roles
+const (
+	CastCategory = "cast"
+	CrewCategory = "crew"
+)
+
+// Role is a role stars are credited with in movies, e.g. actor or cinematographer.
+// Credits refer to roles by name, so a renamed role keeps its credits.
+type Role struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category"`
+	UpdatedAt time.Time `json:"-"`
+}
@@ -0,0 +1,23 @@
+package movieroles
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+func NewModule(db *pgxpool.Pool) *Module {
+	repository := NewRepository(db)
+	service := NewService(repository)
+	handler := NewHandler(service)
+
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repository,
+	}
+}
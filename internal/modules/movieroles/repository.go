@@ -0,0 +1,201 @@
+package movieroles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/dbx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, role *Role) error {
+	err := r.db.
+		QueryRow(
+			ctx,
+			`INSERT INTO movie_roles (name, category)
+			VALUES ($1, $2)
+			RETURNING id, updated_at;`,
+			role.Name,
+			role.Category,
+		).
+		Scan(
+			&role.ID,
+			&role.UpdatedAt,
+		)
+
+	switch {
+	case dbx.IsUniqueViolation(err, "name"):
+		return apperrors.AlreadyExists("role", "name", role.Name)
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+
+	return nil
+}
+
+// GetAll returns the roles of the category, all of them if the category is nil, the cast before the crew.
+func (r *Repository) GetAll(ctx context.Context, category *string) ([]*Role, error) {
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT id, name, category, updated_at
+			FROM movie_roles
+			WHERE $1::text IS NULL OR category = $1
+			ORDER BY category, id;`,
+			category,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	roles := []*Role{}
+	for rows.Next() {
+		var role Role
+		if err = rows.Scan(&role.ID, &role.Name, &role.Category, &role.UpdatedAt); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		roles = append(roles, &role)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return roles, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, roleID int) (*Role, error) {
+	var role Role
+
+	err := r.db.
+		QueryRow(
+			ctx,
+			`SELECT id, name, category, updated_at
+			FROM movie_roles
+			WHERE id = $1;`,
+			roleID,
+		).
+		Scan(
+			&role.ID,
+			&role.Name,
+			&role.Category,
+			&role.UpdatedAt,
+		)
+
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, errRoleWithNotFound(roleID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	return &role, nil
+}
+
+// Update renames the role or moves it to another category, the credits with the role follow the new name
+// and so do the credits in the versions of the movies, to keep them restorable.
+func (r *Repository) Update(ctx context.Context, role *Role) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		var oldName string
+		err := tx.
+			QueryRow(
+				ctx,
+				`SELECT name
+				FROM movie_roles
+				WHERE id = $1
+				FOR UPDATE;`,
+				role.ID,
+			).
+			Scan(&oldName)
+
+		switch {
+		case dbx.IsNoRows(err):
+			return errRoleWithNotFound(role.ID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		_, err = tx.
+			Exec(
+				ctx,
+				`UPDATE movie_roles
+				SET name = $1,
+				category = $2
+				WHERE id = $3;`,
+				role.Name,
+				role.Category,
+				role.ID,
+			)
+
+		switch {
+		case dbx.IsUniqueViolation(err, "name"):
+			return apperrors.AlreadyExists("role", "name", role.Name)
+		case err != nil:
+			return apperrors.Internal(err)
+		case oldName == role.Name:
+			return nil
+		}
+
+		_, err = tx.
+			Exec(
+				ctx,
+				`UPDATE movie_versions
+				SET snapshot = jsonb_set(snapshot, '{cast}', (
+					SELECT jsonb_agg(
+						CASE WHEN c->>'role' = $1 THEN jsonb_set(c, '{role}', to_jsonb($2::text)) ELSE c END
+						ORDER BY n
+					)
+					FROM jsonb_array_elements(snapshot->'cast') WITH ORDINALITY AS e(c, n)
+				))
+				WHERE snapshot->'cast' @> jsonb_build_array(jsonb_build_object('role', $1::text));`,
+				oldName,
+				role.Name,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+// Delete deletes the role unless stars are credited with it.
+func (r *Repository) Delete(ctx context.Context, roleID int) error {
+	n, err := r.db.
+		Exec(
+			ctx,
+			`DELETE FROM movie_roles
+			WHERE id = $1;`,
+			roleID,
+		)
+
+	switch {
+	case dbx.IsForeignKeyViolation(err, "movie_stars_role_fkey"):
+		return apperrors.BadRequest(fmt.Errorf("role %d is credited in movies", roleID))
+	case err != nil:
+		return apperrors.Internal(err)
+	case n.RowsAffected() == 0:
+		return errRoleWithNotFound(roleID)
+	}
+
+	return nil
+}
+
+func errRoleWithNotFound(roleID int) error {
+	return apperrors.NotFound("role", "id", roleID)
+}
@@ -0,0 +1,66 @@
+package movieroles
+
+import (
+	"context"
+
+	"github.com/boichique/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) Create(ctx context.Context, role *Role) error {
+	if err := s.repo.Create(ctx, role); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"role created",
+		"roleID", role.ID,
+		"roleName", role.Name,
+		"roleCategory", role.Category,
+	)
+
+	return nil
+}
+
+func (s *Service) GetAll(ctx context.Context, category *string) ([]*Role, error) {
+	return s.repo.GetAll(ctx, category)
+}
+
+func (s *Service) GetByID(ctx context.Context, roleID int) (*Role, error) {
+	return s.repo.GetByID(ctx, roleID)
+}
+
+func (s *Service) Update(ctx context.Context, role *Role) error {
+	if err := s.repo.Update(ctx, role); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"role updated",
+		"roleID", role.ID,
+		"roleName", role.Name,
+		"roleCategory", role.Category,
+	)
+
+	return nil
+}
+
+func (s *Service) Delete(ctx context.Context, roleID int) error {
+	if err := s.repo.Delete(ctx, roleID); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"role deleted",
+		"roleID", roleID,
+	)
+
+	return nil
+}
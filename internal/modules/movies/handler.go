@@ -4,25 +4,37 @@ import (
 	"net/http"
 
 	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/boichique/movie-reviews/internal/config"
 	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/jwt"
+	"github.com/boichique/movie-reviews/internal/locale"
+	"github.com/boichique/movie-reviews/internal/mergepatch"
 	"github.com/boichique/movie-reviews/internal/modules/genres"
 	"github.com/boichique/movie-reviews/internal/modules/stars"
 	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/slices"
+	"github.com/boichique/movie-reviews/internal/sorting"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/sync/singleflight"
+	"gopkg.in/validator.v2"
 )
 
+const acceptLanguageHeader = "Accept-Language"
+
 type Handler struct {
 	service          *Service
 	paginationConfig config.PaginationConfig
+	maxImageSize     int64
 	reqGroup         singleflight.Group
 }
 
-func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+func NewHandler(service *Service, paginationConfig config.PaginationConfig, maxImageSize int64) *Handler {
 	return &Handler{
 		service:          service,
 		paginationConfig: paginationConfig,
+		maxImageSize:     maxImageSize,
 	}
 }
 
@@ -37,6 +49,12 @@ func (h *Handler) Create(c echo.Context) error {
 			ReleaseDate: req.ReleaseDate,
 		},
 		Description: req.Description,
+		Language:    languageOrDefault(req.Language),
+	}
+	movie.setMetadata(&req.MovieMetadata)
+	movie.ExternalIDs, err = externalids.FromContracts(req.ExternalIDs)
+	if err != nil {
+		return err
 	}
 	for _, genreID := range req.GenresID {
 		movie.Genres = append(movie.Genres, &genres.Genre{ID: genreID})
@@ -54,7 +72,7 @@ func (h *Handler) Create(c echo.Context) error {
 		)
 	}
 
-	err = h.service.Create(c.Request().Context(), movie)
+	err = h.service.Create(c.Request().Context(), movie, jwt.GetClaims(c).UserID)
 	if err != nil {
 		return err
 	}
@@ -63,19 +81,66 @@ func (h *Handler) Create(c echo.Context) error {
 }
 
 func (h *Handler) GetMoviesPaginated(c echo.Context) error {
-	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+	// results of excludeReviewed depend on the caller, so they must not be shared between callers,
+	// and so do translations chosen by Accept-Language
+	key := c.Request().RequestURI + "|" + c.Request().Header.Get(acceptLanguageHeader)
+	if claims := jwt.GetClaims(c); claims != nil {
+		key += "|" + claims.Subject
+	}
+
+	res, err, _ := h.reqGroup.Do(key, func() (any, error) {
 		req, err := echox.BindAndValidate[contracts.GetMoviesPaginatedRequest](c)
 		if err != nil {
 			return nil, err
 		}
 		pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
-		offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
 
-		movies, total, err := h.service.GetMoviesPaginated(c.Request().Context(), req.SearchTerm, req.StarID, req.SortByRating, offset, limit)
+		filter := &MovieFilter{
+			SearchTerm:     req.SearchTerm,
+			Language:       languageOrDefault(req.Lang),
+			StarID:         req.StarID,
+			StarRole:       req.StarRole,
+			GenreIDs:       req.GenreIDs,
+			MatchAllGenres: req.GenresMatch != nil && *req.GenresMatch == "all",
+			ReleasedFrom:   req.ReleasedFrom,
+			ReleasedTo:     req.ReleasedTo,
+			MinRating:      req.MinRating,
+			MaxRating:      req.MaxRating,
+			Country:        req.Country,
+			SpokenLanguage: req.SpokenLanguage,
+			AgeRatings:     req.AgeRatings,
+			MinRuntime:     req.MinRuntime,
+			MaxRuntime:     req.MaxRuntime,
+		}
+		if req.ExcludeReviewed {
+			claims := jwt.GetClaims(c)
+			if claims == nil {
+				return nil, apperrors.Unauthorized("excludeReviewed requires authentication")
+			}
+			filter.ExcludeReviewedBy = &claims.UserID
+		}
+
+		if req.SortByRating != nil && req.Sort == nil {
+			req.Sort = ptr("rating:" + *req.SortByRating)
+		}
+		explicit, err := sorting.Parse(req.Sort, sortFields)
 		if err != nil {
 			return nil, err
 		}
-		return pagination.Response(&req.PaginatedRequest, total, movies), nil
+		page, err := pagination.NewPage(&req.PaginatedRequest, orders(filter, explicit))
+		if err != nil {
+			return nil, err
+		}
+
+		locales := locale.Chain(req.Locale, c.Request().Header.Get(acceptLanguageHeader))
+		movies, facets, err := h.service.GetMoviesPaginated(c.Request().Context(), filter, page, req.Facets, locales)
+		if err != nil {
+			return nil, err
+		}
+		return &MoviesPaginatedResponse{
+			PaginatedResponse: pagination.Response(&req.PaginatedRequest, movies),
+			Facets:            facets,
+		}, nil
 	})
 	if err != nil {
 		return err
@@ -85,18 +150,58 @@ func (h *Handler) GetMoviesPaginated(c echo.Context) error {
 }
 
 func (h *Handler) GetByID(c echo.Context) error {
-	res, err, _ := h.reqGroup.Do(c.Request().RequestURI, func() (any, error) {
+	acceptLanguage := c.Request().Header.Get(acceptLanguageHeader)
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI+"|"+acceptLanguage, func() (any, error) {
 		req, err := echox.BindAndValidate[contracts.GetMovieRequest](c)
 		if err != nil {
-			return err, nil
+			return nil, err
 		}
-		return h.service.GetByID(c.Request().Context(), req.MovieID)
+		return h.service.GetByID(c.Request().Context(), req.MovieID, locale.Chain(req.Locale, acceptLanguage))
 	})
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, res)
+	movie := res.(*MovieDetails)
+	c.Response().Header().Set(echo.HeaderVary, acceptLanguageHeader)
+	return echox.JSONConditional(c, movie, movie.UpdatedAt)
+}
+
+func (h *Handler) GetByExternalID(c echo.Context) error {
+	acceptLanguage := c.Request().Header.Get(acceptLanguageHeader)
+	res, err, _ := h.reqGroup.Do(c.Request().RequestURI+"|"+acceptLanguage, func() (any, error) {
+		req, err := echox.BindAndValidate[contracts.GetMovieByExternalIDRequest](c)
+		if err != nil {
+			return nil, err
+		}
+		return h.service.GetByExternalID(
+			c.Request().Context(),
+			externalids.NormalizeSource(req.Source),
+			req.ExternalID,
+			locale.Chain(req.Locale, acceptLanguage),
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	movie := res.(*MovieDetails)
+	c.Response().Header().Set(echo.HeaderVary, acceptLanguageHeader)
+	return echox.JSONConditional(c, movie, movie.UpdatedAt)
+}
+
+func (h *Handler) PutExternalID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.PutMovieExternalIDRequest](c)
+	if err != nil {
+		return err
+	}
+
+	id := &externalids.ID{Source: externalids.NormalizeSource(req.Source), ID: req.ExternalID}
+	if err = h.service.PutExternalID(c.Request().Context(), req.MovieID, id); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, id)
 }
 
 func (h *Handler) Update(c echo.Context) error {
@@ -104,6 +209,216 @@ func (h *Handler) Update(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+
+	current, err := echox.IfMatch(c, h.current(c, req.MovieID), "movie", "id", req.MovieID)
+	if err != nil {
+		return err
+	}
+
+	movie := movieFromUpdateRequest(req)
+	if current != nil {
+		// the movie must still be in the version the precondition held for
+		movie.Version = current.Version
+	}
+	if err = h.service.Update(c.Request().Context(), movie, jwt.GetClaims(c).UserID); err != nil {
+		if current != nil && apperrors.Is(err, apperrors.VersionMismatchCode) {
+			return apperrors.PreconditionFailed("movie", "id", req.MovieID)
+		}
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// Patch applies the patch to the current state of the movie and updates the movie with the result,
+// which is validated as a full update would be.
+func (h *Handler) Patch(c echo.Context) error {
+	req, err := echox.BindPatchAndValidate[contracts.PatchMovieRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	current, err := h.service.GetByID(ctx, req.MovieID, nil)
+	if err != nil {
+		return err
+	}
+
+	update := updateRequestFromMovie(current)
+	if err = mergepatch.ApplyTo(update, req.Fields); err != nil {
+		return apperrors.BadRequest(err)
+	}
+	update.MovieID, update.Version = req.MovieID, *req.Version
+	patchGenres(update, req.Genres)
+	patchCast(update, req.Cast)
+	if err = validator.Validate(update); err != nil {
+		return apperrors.BadRequest(err)
+	}
+
+	if err = h.service.Update(ctx, movieFromUpdateRequest(update), jwt.GetClaims(c).UserID); err != nil {
+		return err
+	}
+
+	movie, err := h.service.GetByID(ctx, req.MovieID, nil)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, movie)
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteMovieRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if _, err = echox.IfMatch(c, h.current(c, req.MovieID), "movie", "id", req.MovieID); err != nil {
+		return err
+	}
+	if err = h.service.Delete(c.Request().Context(), req.MovieID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) GetVersions(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetMovieVersionsRequest](c)
+	if err != nil {
+		return err
+	}
+
+	versions, err := h.service.GetVersions(c.Request().Context(), req.MovieID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, versions)
+}
+
+func (h *Handler) GetVersion(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetMovieVersionRequest](c)
+	if err != nil {
+		return err
+	}
+
+	version, err := h.service.GetVersion(c.Request().Context(), req.MovieID, req.Version)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, version)
+}
+
+func (h *Handler) Rollback(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.RollbackMovieRequest](c)
+	if err != nil {
+		return err
+	}
+
+	movie, err := h.service.Rollback(c.Request().Context(), req.MovieID, req.Version, jwt.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, movie)
+}
+
+func (h *Handler) Merge(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.MergeMoviesRequest](c)
+	if err != nil {
+		return err
+	}
+
+	movie, err := h.service.Merge(c.Request().Context(), req.MovieID, req.DuplicateID, jwt.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, movie)
+}
+
+func (h *Handler) UploadPoster(c echo.Context) error {
+	data, err := echox.ReadFile(c, "image", h.maxImageSize)
+	if err != nil {
+		return err
+	}
+	req, err := echox.BindAndValidate[contracts.UploadMoviePosterRequest](c)
+	if err != nil {
+		return err
+	}
+
+	image, err := h.service.SetPoster(c.Request().Context(), req.MovieID, data)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, image)
+}
+
+func (h *Handler) DeletePoster(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteMoviePosterRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.DeletePoster(c.Request().Context(), req.MovieID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) GetTranslations(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetMovieTranslationsRequest](c)
+	if err != nil {
+		return err
+	}
+
+	translations, err := h.service.GetTranslations(c.Request().Context(), req.MovieID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, translations)
+}
+
+func (h *Handler) PutTranslation(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.PutMovieTranslationRequest](c)
+	if err != nil {
+		return err
+	}
+
+	translation := &Translation{
+		MovieID:     req.MovieID,
+		Locale:      locale.Canonical(req.Locale),
+		Title:       req.Title,
+		Description: req.Description,
+	}
+	if err = h.service.PutTranslation(c.Request().Context(), translation); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, translation)
+}
+
+func (h *Handler) DeleteTranslation(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteMovieTranslationRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.DeleteTranslation(c.Request().Context(), req.MovieID, locale.Canonical(req.Locale)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// current returns the getter of the movie as GET sends it to the client, which If-Match is checked against.
+func (h *Handler) current(c echo.Context, movieID int) func() (*MovieDetails, error) {
+	return func() (*MovieDetails, error) {
+		return h.service.GetByID(c.Request().Context(), movieID, locale.Chain("", c.Request().Header.Get(acceptLanguageHeader)))
+	}
+}
+
+func movieFromUpdateRequest(req *contracts.UpdateMovieRequest) *MovieDetails {
 	movie := &MovieDetails{
 		Movie: Movie{
 			ID:          req.MovieID,
@@ -111,7 +426,10 @@ func (h *Handler) Update(c echo.Context) error {
 			ReleaseDate: req.ReleaseDate,
 		},
 		Description: req.Description,
+		Language:    req.Language,
+		Version:     req.Version,
 	}
+	movie.setMetadata(&req.MovieMetadata)
 	for _, genreID := range req.GenresID {
 		movie.Genres = append(movie.Genres, &genres.Genre{ID: genreID})
 	}
@@ -128,20 +446,94 @@ func (h *Handler) Update(c echo.Context) error {
 		)
 	}
 
-	if err = h.service.Update(c.Request().Context(), movie); err != nil {
-		return err
+	return movie
+}
+
+// updateRequestFromMovie returns the full update request which would leave the movie as it is.
+func updateRequestFromMovie(movie *MovieDetails) *contracts.UpdateMovieRequest {
+	req := &contracts.UpdateMovieRequest{
+		MovieID:     movie.ID,
+		Version:     movie.Version,
+		Title:       movie.Title,
+		Description: movie.Description,
+		ReleaseDate: movie.ReleaseDate,
+		Language:    movie.Language,
+		MovieMetadata: contracts.MovieMetadata{
+			RuntimeMinutes:  movie.RuntimeMinutes,
+			Countries:       movie.Countries,
+			SpokenLanguages: movie.SpokenLanguages,
+			AgeRating:       movie.AgeRating,
+			Tagline:         movie.Tagline,
+		},
+		GenresID: make([]int, 0, len(movie.Genres)),
+		Cast:     make([]*contracts.MovieCreditInfo, 0, len(movie.Cast)),
 	}
-	return c.NoContent(http.StatusOK)
+	for _, g := range movie.Genres {
+		req.GenresID = append(req.GenresID, g.ID)
+	}
+	for _, c := range movie.Cast {
+		req.Cast = append(req.Cast, &contracts.MovieCreditInfo{
+			StarID:  c.Star.ID,
+			Role:    c.Role,
+			Details: c.Details,
+		})
+	}
+
+	return req
 }
 
-func (h *Handler) Delete(c echo.Context) error {
-	req, err := echox.BindAndValidate[contracts.DeleteMovieRequest](c)
-	if err != nil {
-		return err
+// patchGenres removes and then adds the genres of the patch, genres already added are kept in place.
+func patchGenres(req *contracts.UpdateMovieRequest, patch *contracts.GenresPatch) {
+	if patch == nil {
+		return
 	}
 
-	if err = h.service.Delete(c.Request().Context(), req.MovieID); err != nil {
-		return err
+	req.GenresID = slices.Filter(req.GenresID, func(genreID int) bool { return !slices.Contains(patch.Remove, genreID) })
+	for _, genreID := range patch.Add {
+		if !slices.Contains(req.GenresID, genreID) {
+			req.GenresID = append(req.GenresID, genreID)
+		}
 	}
-	return c.NoContent(http.StatusOK)
+}
+
+// patchCast removes and then adds the credits of the patch, credits already added get the new details in place.
+func patchCast(req *contracts.UpdateMovieRequest, patch *contracts.CastPatch) {
+	if patch == nil {
+		return
+	}
+
+	sameCredit := func(a, b *contracts.MovieCreditInfo) bool {
+		return a.StarID == b.StarID && a.Role == b.Role
+	}
+
+	req.Cast = slices.Filter(req.Cast, func(credit *contracts.MovieCreditInfo) bool {
+		for _, removed := range patch.Remove {
+			if sameCredit(credit, removed) {
+				return false
+			}
+		}
+		return true
+	})
+
+next:
+	for _, added := range patch.Add {
+		for _, credit := range req.Cast {
+			if sameCredit(credit, added) {
+				credit.Details = added.Details
+				continue next
+			}
+		}
+		req.Cast = append(req.Cast, added)
+	}
+}
+
+func languageOrDefault(lang string) string {
+	if lang == "" {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+func ptr[T any](v T) *T {
+	return &v
 }
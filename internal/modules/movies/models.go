@@ -1,25 +1,305 @@
 package movies
 
 import (
+	"bytes"
+	"encoding/json"
 	"time"
 
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/modules/collections"
 	"github.com/boichique/movie-reviews/internal/modules/genres"
 	"github.com/boichique/movie-reviews/internal/modules/stars"
+	"github.com/boichique/movie-reviews/internal/sorting"
 )
 
+var (
+	sortFields = sorting.Fields{
+		"id":           "movies.id",
+		"title":        "movies.title",
+		"release_date": "movies.release_date",
+		"rating":       "COALESCE(movies.avg_rating, 0)",
+		"created_at":   "movies.created_at",
+	}
+	sortTiebreaker = sorting.Order{Field: "id", Expr: "movies.id"}
+)
+
+// Movie is shown in the locale requested by the client, OriginalTitle is set when Title is a translation.
 type Movie struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	ReleaseDate time.Time  `json:"release_date"`
-	AvgRating   *float64   `json:"avg_rating,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	ID            int           `json:"id"`
+	Title         string        `json:"title"`
+	OriginalTitle *string       `json:"original_title,omitempty"`
+	ReleaseDate   time.Time     `json:"release_date"`
+	AvgRating     *float64      `json:"avg_rating,omitempty"`
+	Poster        *images.Image `json:"poster,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	DeletedAt     *time.Time    `json:"deleted_at,omitempty"`
 }
 
+// DefaultLanguage is the language of movies created without one, and of search terms without a language hint.
+const DefaultLanguage = "en"
+
 type MovieDetails struct {
 	Movie
-	Description string               `json:"description"`
-	Version     int                  `json:"version"`
-	Genres      []*genres.Genre      `json:"genres"`
-	Cast        []*stars.MovieCredit `json:"cast"`
+	Description     string                       `json:"description"`
+	Language        string                       `json:"language"`
+	RuntimeMinutes  *int                         `json:"runtime_minutes,omitempty"`
+	Countries       []string                     `json:"countries,omitempty"`
+	SpokenLanguages []string                     `json:"spoken_languages,omitempty"`
+	AgeRating       *string                      `json:"age_rating,omitempty"`
+	Tagline         *string                      `json:"tagline,omitempty"`
+	ExternalIDs     []*externalids.ID            `json:"external_ids,omitempty"`
+	Version         int                          `json:"version"`
+	UpdatedAt       time.Time                    `json:"-"`
+	Genres          []*genres.Genre              `json:"genres"`
+	Cast            []*stars.MovieCredit         `json:"cast"`
+	Collection      *collections.MovieCollection `json:"collection,omitempty"`
+}
+
+// MoviesPaginatedResponse is a page of movies along with the facets of the whole result set.
+type MoviesPaginatedResponse struct {
+	*contracts.PaginatedResponse[Movie]
+	Facets *MovieFacets `json:"facets,omitempty"`
+}
+
+type MovieFacets struct {
+	Genres  []*GenreFacet  `json:"genres"`
+	Decades []*DecadeFacet `json:"decades"`
+	Ratings []*RatingFacet `json:"ratings"`
+}
+
+type GenreFacet struct {
+	GenreID int    `json:"genre_id"`
+	Name    string `json:"name"`
+	Count   int    `json:"count"`
+}
+
+type DecadeFacet struct {
+	Decade int `json:"decade"`
+	Count  int `json:"count"`
+}
+
+type RatingFacet struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+type Translation struct {
+	MovieID     int       `json:"-"`
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// localized holds the most preferred translated fields of a movie, nil for fields without a translation.
+type localized struct {
+	Title       *string
+	Description *string
+}
+
+func (m *Movie) localize(l *localized) {
+	if l == nil || l.Title == nil {
+		return
+	}
+
+	original := m.Title
+	m.Title = *l.Title
+	m.OriginalTitle = &original
+}
+
+func (m *MovieDetails) localize(l *localized) {
+	m.Movie.localize(l)
+	if l != nil && l.Description != nil {
+		m.Description = *l.Description
+	}
+}
+
+type MovieFilter struct {
+	SearchTerm        *string
+	Language          string
+	StarID            *int
+	StarRole          *string
+	GenreIDs          []int
+	MatchAllGenres    bool
+	ReleasedFrom      *time.Time
+	ReleasedTo        *time.Time
+	MinRating         *float64
+	MaxRating         *float64
+	Country           *string
+	SpokenLanguage    *string
+	AgeRatings        []string
+	MinRuntime        *int
+	MaxRuntime        *int
+	ExcludeReviewedBy *int
+}
+
+// setMetadata copies the optional details of a create or update request. Missing lists are stored empty.
+func (m *MovieDetails) setMetadata(md *contracts.MovieMetadata) {
+	m.RuntimeMinutes = md.RuntimeMinutes
+	m.Countries = orEmpty(md.Countries)
+	m.SpokenLanguages = orEmpty(md.SpokenLanguages)
+	m.AgeRating = md.AgeRating
+	m.Tagline = md.Tagline
+}
+
+func orEmpty(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// Snapshot is the state of a movie stored for each of its versions.
+// Translations, the poster and external ids are not versioned.
+type Snapshot struct {
+	Title           string            `json:"title"`
+	Description     string            `json:"description"`
+	ReleaseDate     time.Time         `json:"release_date"`
+	Language        string            `json:"language"`
+	RuntimeMinutes  *int              `json:"runtime_minutes"`
+	Countries       []string          `json:"countries"`
+	SpokenLanguages []string          `json:"spoken_languages"`
+	AgeRating       *string           `json:"age_rating"`
+	Tagline         *string           `json:"tagline"`
+	GenreIDs        []int             `json:"genre_ids"`
+	Cast            []*SnapshotCredit `json:"cast"`
+}
+
+type SnapshotCredit struct {
+	StarID  int     `json:"star_id"`
+	Role    string  `json:"role"`
+	Details *string `json:"details,omitempty"`
+}
+
+// Revision describes who made a change, RollbackOf is set when the change restores an earlier version.
+type Revision struct {
+	ChangedBy  int
+	RollbackOf *int
+}
+
+type Version struct {
+	Version    int            `json:"version"`
+	ChangedBy  *int           `json:"changed_by,omitempty"`
+	RollbackOf *int           `json:"rollback_of,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Changes    []*FieldChange `json:"changes"`
+}
+
+// VersionDetails is a version along with the full state of the movie in it.
+type VersionDetails struct {
+	*Version
+	Snapshot *Snapshot `json:"snapshot"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// snapshot captures the versioned fields of the movie, the release date is kept as the date stored in the database.
+func (m *MovieDetails) snapshot() *Snapshot {
+	y, mon, d := m.ReleaseDate.Date()
+	s := &Snapshot{
+		Title:           m.Title,
+		Description:     m.Description,
+		ReleaseDate:     time.Date(y, mon, d, 0, 0, 0, 0, time.UTC),
+		Language:        m.Language,
+		RuntimeMinutes:  m.RuntimeMinutes,
+		Countries:       orEmpty(m.Countries),
+		SpokenLanguages: orEmpty(m.SpokenLanguages),
+		AgeRating:       m.AgeRating,
+		Tagline:         m.Tagline,
+		GenreIDs:        make([]int, 0, len(m.Genres)),
+		Cast:            make([]*SnapshotCredit, 0, len(m.Cast)),
+	}
+	for _, g := range m.Genres {
+		s.GenreIDs = append(s.GenreIDs, g.ID)
+	}
+	for _, c := range m.Cast {
+		s.Cast = append(s.Cast, &SnapshotCredit{
+			StarID:  c.Star.ID,
+			Role:    c.Role,
+			Details: c.Details,
+		})
+	}
+
+	return s
+}
+
+// restore sets the versioned fields of the movie from the snapshot.
+func (m *MovieDetails) restore(s *Snapshot) {
+	m.Title = s.Title
+	m.Description = s.Description
+	m.ReleaseDate = s.ReleaseDate
+	m.Language = s.Language
+	m.RuntimeMinutes = s.RuntimeMinutes
+	m.Countries = orEmpty(s.Countries)
+	m.SpokenLanguages = orEmpty(s.SpokenLanguages)
+	m.AgeRating = s.AgeRating
+	m.Tagline = s.Tagline
+	m.Genres = make([]*genres.Genre, 0, len(s.GenreIDs))
+	for _, genreID := range s.GenreIDs {
+		m.Genres = append(m.Genres, &genres.Genre{ID: genreID})
+	}
+	m.Cast = make([]*stars.MovieCredit, 0, len(s.Cast))
+	for _, c := range s.Cast {
+		m.Cast = append(m.Cast, &stars.MovieCredit{
+			Star:    stars.Star{ID: c.StarID},
+			Role:    c.Role,
+			Details: c.Details,
+		})
+	}
+}
+
+// absorb appends the genres and credits of the duplicate the movie lacks, keeping their order.
+func (m *MovieDetails) absorb(duplicate *MovieDetails) {
+	genreIDs := make(map[int]bool, len(m.Genres))
+	for _, g := range m.Genres {
+		genreIDs[g.ID] = true
+	}
+	for _, g := range duplicate.Genres {
+		if !genreIDs[g.ID] {
+			m.Genres = append(m.Genres, g)
+		}
+	}
+
+	credits := make(map[stars.MovieStarRelation]bool, len(m.Cast))
+	for _, c := range m.Cast {
+		credits[stars.MovieStarRelation{StarID: c.Star.ID, Role: c.Role}] = true
+	}
+	for _, c := range duplicate.Cast {
+		if !credits[stars.MovieStarRelation{StarID: c.Star.ID, Role: c.Role}] {
+			m.Cast = append(m.Cast, c)
+		}
+	}
+}
+
+// diff lists the fields changed from prev to next, in the order of the snapshot fields.
+func diff(prev, next *Snapshot) []*FieldChange {
+	changes := []*FieldChange{}
+	compare := func(field string, old, new any) {
+		o, _ := json.Marshal(old)
+		n, _ := json.Marshal(new)
+		if !bytes.Equal(o, n) {
+			changes = append(changes, &FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	compare("title", prev.Title, next.Title)
+	compare("description", prev.Description, next.Description)
+	compare("release_date", prev.ReleaseDate, next.ReleaseDate)
+	compare("language", prev.Language, next.Language)
+	compare("runtime_minutes", prev.RuntimeMinutes, next.RuntimeMinutes)
+	compare("countries", prev.Countries, next.Countries)
+	compare("spoken_languages", prev.SpokenLanguages, next.SpokenLanguages)
+	compare("age_rating", prev.AgeRating, next.AgeRating)
+	compare("tagline", prev.Tagline, next.Tagline)
+	compare("genre_ids", prev.GenreIDs, next.GenreIDs)
+	compare("cast", prev.Cast, next.Cast)
+
+	return changes
 }
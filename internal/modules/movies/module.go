@@ -2,7 +2,10 @@ package movies
 
 import (
 	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/modules/collections"
 	"github.com/boichique/movie-reviews/internal/modules/genres"
+	"github.com/boichique/movie-reviews/internal/modules/movieroles"
 	"github.com/boichique/movie-reviews/internal/modules/stars"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -13,10 +16,10 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool, genresModule *genres.Module, starsModule *stars.Module, paginationConfig config.PaginationConfig) *Module {
+func NewModule(db *pgxpool.Pool, genresModule *genres.Module, rolesModule *movieroles.Module, starsModule *stars.Module, collectionsModule *collections.Module, imagesService *images.Service, paginationConfig config.PaginationConfig) *Module {
 	repo := NewRepository(db, genresModule.Repository, starsModule.Repository)
-	service := NewService(repo, genresModule.Service, starsModule.Service)
-	handler := NewHandler(service, paginationConfig)
+	service := NewService(repo, genresModule.Service, rolesModule.Service, starsModule.Service, collectionsModule.Service, imagesService)
+	handler := NewHandler(service, paginationConfig, imagesService.MaxSize())
 
 	return &Module{
 		Handler:    handler,
@@ -2,13 +2,20 @@ package movies
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/boichique/movie-reviews/internal/dbx"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/images"
 	"github.com/boichique/movie-reviews/internal/modules/genres"
 	"github.com/boichique/movie-reviews/internal/modules/stars"
+	"github.com/boichique/movie-reviews/internal/pagination"
 	"github.com/boichique/movie-reviews/internal/slices"
+	"github.com/boichique/movie-reviews/internal/sorting"
+	"github.com/boichique/movie-reviews/internal/tsquery"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -27,15 +34,23 @@ func NewRepository(db *pgxpool.Pool, genresRepo *genres.Repository, starRepo *st
 	}
 }
 
-func (r *Repository) Create(ctx context.Context, movie *MovieDetails) error {
+var externalIDs = &externalids.Table{
+	Name:        "movie_external_ids",
+	Owners:      "movies",
+	OwnerColumn: "movie_id",
+	Subject:     "movie",
+}
+
+func (r *Repository) Create(ctx context.Context, movie *MovieDetails, rev *Revision) error {
 	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
 		err := tx.
 			QueryRow(
 				ctx,
-				`INSERT INTO movies (title, description, release_date)
-				VALUES ($1, $2, $3) 
+				`INSERT INTO movies (title, description, release_date, language, runtime_minutes, countries, spoken_languages, age_rating, tagline)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
 				RETURNING id, created_at`,
-				movie.Title, movie.Description, movie.ReleaseDate).
+				movie.Title, movie.Description, movie.ReleaseDate, movie.Language,
+				movie.RuntimeMinutes, movie.Countries, movie.SpokenLanguages, movie.AgeRating, movie.Tagline).
 			Scan(
 				&movie.ID,
 				&movie.CreatedAt,
@@ -51,6 +66,10 @@ func (r *Repository) Create(ctx context.Context, movie *MovieDetails) error {
 				OrderNo: i,
 			}
 		})
+		if err = externalIDs.Insert(ctx, tx, movie.ID, movie.ExternalIDs); err != nil {
+			return err
+		}
+
 		if err = r.updateGenres(ctx, nil, nextGenres); err != nil {
 			return err
 		}
@@ -64,95 +83,198 @@ func (r *Repository) Create(ctx context.Context, movie *MovieDetails) error {
 				OrderNo: i,
 			}
 		})
+		if err = r.updateCast(ctx, nil, nextCast); err != nil {
+			return err
+		}
 
-		return r.updateCast(ctx, nil, nextCast)
+		return r.insertVersion(ctx, tx, movie, rev)
 	})
 	if err != nil {
-		return apperrors.Internal(err)
+		return apperrors.EnsureInternal(err)
 	}
 
 	return nil
 }
 
-func (r *Repository) GetMoviesPaginated(ctx context.Context, searchTerm *string, starID *int, sortByRating *string, offset int, limit int) ([]*Movie, int, error) {
+func (r *Repository) GetMoviesPaginated(ctx context.Context, filter *MovieFilter, page *pagination.Page, withFacets bool) (*pagination.Result[Movie], *MovieFacets, error) {
 	b := &pgx.Batch{}
 	selectQuery := dbx.StatementBuilder.
-		Select("id, title,  release_date, avg_rating, created_at").
-		From("movies").
-		Where("deleted_at IS NULL").
-		Limit(uint64(limit)).
-		Offset(uint64(offset))
-	countQuery := dbx.StatementBuilder.
-		Select("count(*)").
+		Select("id, title, release_date, avg_rating, created_at, " + images.Select("poster")).
 		From("movies").
 		Where("deleted_at IS NULL")
+	selectQuery = page.Apply(applyMovieFilter(selectQuery, filter))
 
-	if sortByRating != nil {
-		selectQuery = selectQuery.OrderByClause("avg_rating" + *sortByRating)
+	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
+		return nil, nil, apperrors.Internal(err)
 	}
 
-	if starID != nil {
-		selectQuery = selectQuery.
-			Join("movie_stars on movies.id = movie_stars.movie_id").
-			Where("movie_stars.star_id = ?", starID)
+	if page.WithTotal {
+		countQuery := dbx.StatementBuilder.
+			Select("count(*)").
+			From("movies").
+			Where("deleted_at IS NULL")
+		countQuery = applyMovieFilter(countQuery, filter)
 
-		countQuery = countQuery.
-			Join("movie_stars on movies.id = movie_stars.movie_id").
-			Where("movie_stars.star_id = ?", starID)
+		if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+			return nil, nil, apperrors.Internal(err)
+		}
 	}
 
-	if searchTerm != nil {
-		selectQuery = selectQuery.
-			Where("search_vector @@ to_tsquery('english', ?)", *searchTerm).
-			OrderByClause("ts_rank_cd(search_vector, to_tsquery('english', ?)) DESC", *searchTerm)
+	if withFacets {
+		if err := queueFacets(b, filter); err != nil {
+			return nil, nil, err
+		}
+	}
 
-		countQuery = countQuery.
-			Where("search_vector @@ to_tsquery('english', ?)", *searchTerm)
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
 
+	rows, err := br.Query()
+	if err != nil {
+		return nil, nil, apperrors.Internal(err)
 	}
+	defer rows.Close()
 
-	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	res, err := pagination.Collect(rows, page, func(movie *Movie) []any {
+		return []any{
+			&movie.ID,
+			&movie.Title,
+			&movie.ReleaseDate,
+			&movie.AvgRating,
+			&movie.CreatedAt,
+			&movie.Poster,
+		}
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	if page.WithTotal {
+		var total int
+		if err = br.QueryRow().Scan(&total); err != nil {
+			return nil, nil, apperrors.Internal(err)
+		}
+		res.Total = &total
 	}
 
-	br := r.db.SendBatch(ctx, b)
-	defer br.Close()
+	if !withFacets {
+		return res, nil, nil
+	}
 
-	rows, err := br.Query()
+	facets, err := scanFacets(br)
 	if err != nil {
-		return nil, 0, apperrors.Internal(err)
+		return nil, nil, err
 	}
-	defer rows.Close()
 
-	var movies []*Movie
-	for rows.Next() {
-		var movie Movie
-		if err = rows.
-			Scan(&movie.ID,
-				&movie.Title,
-				&movie.ReleaseDate,
-				&movie.AvgRating,
-				&movie.CreatedAt,
-			); err != nil {
-			return nil, 0, apperrors.Internal(err)
+	return res, facets, nil
+}
+
+// queueFacets queues the aggregations of the whole filtered result set, which scanFacets reads in the same order.
+func queueFacets(b *pgx.Batch, filter *MovieFilter) error {
+	filtered := func(columns ...string) squirrel.SelectBuilder {
+		return applyMovieFilter(
+			dbx.StatementBuilder.
+				Select(columns...).
+				From("movies").
+				Where("deleted_at IS NULL"),
+			filter,
+		)
+	}
+
+	genresQuery := dbx.StatementBuilder.
+		Select("genres.id, genres.name, COUNT(*)").
+		From("movie_genres").
+		Join("genres ON genres.id = movie_genres.genre_id").
+		Where(dbx.In("movie_genres.movie_id", filtered("movies.id"))).
+		GroupBy("genres.id, genres.name").
+		OrderBy("COUNT(*) DESC, genres.name")
+
+	decadesQuery := filtered("date_part('year', release_date)::int / 10 * 10 AS decade", "COUNT(*)").
+		GroupBy("decade").
+		OrderBy("decade")
+
+	ratingsQuery := filtered("LEAST(FLOOR(avg_rating), 9)::int AS bucket", "COUNT(*)").
+		Where("avg_rating IS NOT NULL").
+		GroupBy("bucket").
+		OrderBy("bucket")
+
+	for _, query := range []squirrel.SelectBuilder{genresQuery, decadesQuery, ratingsQuery} {
+		if err := dbx.QueueBatchSelect(b, query); err != nil {
+			return apperrors.Internal(err)
 		}
-		movies = append(movies, &movie)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	return nil
+}
+
+func scanFacets(br pgx.BatchResults) (*MovieFacets, error) {
+	var facets MovieFacets
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	facets.Genres, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (*GenreFacet, error) {
+		var facet GenreFacet
+		err := row.Scan(&facet.GenreID, &facet.Name, &facet.Count)
+		return &facet, err
+	})
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	rows, err = br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	facets.Decades, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (*DecadeFacet, error) {
+		var facet DecadeFacet
+		err := row.Scan(&facet.Decade, &facet.Count)
+		return &facet, err
+	})
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	rows, err = br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
 	}
+	facets.Ratings, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (*RatingFacet, error) {
+		var facet RatingFacet
+		err := row.Scan(&facet.From, &facet.Count)
+		facet.To = facet.From + 1
+		return &facet, err
+	})
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return &facets, nil
+}
 
-	var total int
-	if err = br.QueryRow().Scan(&total); err != nil {
-		return nil, 0, apperrors.Internal(err)
+// orders returns the orders of a movie list. Without an explicit sort search results are ordered by relevance,
+// with exact title matches first.
+func orders(filter *MovieFilter, explicit []sorting.Order) []sorting.Order {
+	if len(explicit) == 0 && filter.SearchTerm != nil {
+		term := *filter.SearchTerm
+		explicit = []sorting.Order{
+			{
+				Field: "exact_title",
+				Expr:  "lower(title) = lower(?)",
+				Args:  []any{term},
+				Desc:  true,
+			},
+			{
+				Field: "relevance",
+				Expr:  "ts_rank_cd(search_vector, " + tsquery.MatchIn + ") + word_similarity(lower(?), lower(title))",
+				Args:  append(tsquery.ArgsIn(filter.Language, term), term),
+				Desc:  true,
+			},
+		}
 	}
 
-	return movies, total, err
+	return sorting.WithTiebreaker(explicit, sortTiebreaker)
 }
 
 func (r *Repository) GetByID(ctx context.Context, id int) (*MovieDetails, error) {
@@ -161,7 +283,8 @@ func (r *Repository) GetByID(ctx context.Context, id int) (*MovieDetails, error)
 	err := r.db.
 		QueryRow(
 			ctx,
-			`SELECT id, version ,title, description, release_date, avg_rating, created_at 
+			`SELECT id, version ,title, description, release_date, language, 
+			runtime_minutes, countries, spoken_languages, age_rating, tagline, avg_rating, created_at, updated_at, `+images.Select("poster")+`
 			FROM movies 
 			WHERE id = $1 
 			AND deleted_at IS NULL;`,
@@ -173,8 +296,16 @@ func (r *Repository) GetByID(ctx context.Context, id int) (*MovieDetails, error)
 			&movie.Title,
 			&movie.Description,
 			&movie.ReleaseDate,
+			&movie.Language,
+			&movie.RuntimeMinutes,
+			&movie.Countries,
+			&movie.SpokenLanguages,
+			&movie.AgeRating,
+			&movie.Tagline,
 			&movie.AvgRating,
 			&movie.CreatedAt,
+			&movie.UpdatedAt,
+			&movie.Poster,
 		)
 	switch {
 	case dbx.IsNoRows(err):
@@ -183,79 +314,312 @@ func (r *Repository) GetByID(ctx context.Context, id int) (*MovieDetails, error)
 		return nil, apperrors.Internal(err)
 	}
 
+	movie.ExternalIDs, err = externalIDs.Get(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+
 	return &movie, nil
 }
 
-func (r *Repository) Update(ctx context.Context, movie *MovieDetails) error {
+func (r *Repository) GetIDByExternalID(ctx context.Context, source, externalID string) (int, error) {
+	return externalIDs.Resolve(ctx, r.db, source, externalID)
+}
+
+// PutExternalID sets the ID of the movie in the source of the ID, replacing the one it had. It leaves the movie
+// itself, including its version, untouched.
+func (r *Repository) PutExternalID(ctx context.Context, movieID int, id *externalids.ID) error {
+	if _, err := r.GetByID(ctx, movieID); err != nil {
+		return err
+	}
+
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		return externalIDs.Put(ctx, tx, movieID, id)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Update(ctx context.Context, movie *MovieDetails, rev *Revision) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		return r.update(ctx, tx, movie, rev)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+// Merge merges the duplicate into the movie, which holds the genres and cast of both by now. The reviews of the
+// duplicate move to the movie, of two live reviews by the same user the older one is deleted. Translations,
+// external IDs and the collection membership move unless the movie has them already. The duplicate is deleted and
+// its ID redirects to the movie from now on.
+func (r *Repository) Merge(ctx context.Context, movie *MovieDetails, duplicate *MovieDetails, rev *Revision) error {
+	now := time.Now()
 	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
 		n, err := tx.
 			Exec(
 				ctx,
-				`UPDATE movies 
-			SET version = version + 1, 
-			title = $1,
-			description = $2, 
-			release_date = $3 
-			WHERE id = $4 
-			AND version = $5;`,
-				movie.Title,
-				movie.Description,
-				movie.ReleaseDate,
-				movie.ID,
-				movie.Version,
+				`UPDATE movies
+				SET deleted_at = $1
+				WHERE id = $2
+				AND version = $3
+				AND deleted_at IS NULL`,
+				now, duplicate.ID, duplicate.Version,
 			)
 		if err != nil {
 			return apperrors.Internal(err)
 		}
 
 		if n.RowsAffected() == 0 {
-			_, err = r.GetByID(ctx, movie.ID)
-			if err != nil {
+			if _, err = r.GetByID(ctx, duplicate.ID); err != nil {
 				return err
 			}
 
-			return apperrors.VersionMismatch("movie", "id", movie.ID, movie.Version)
+			return apperrors.VersionMismatch("movie", "id", duplicate.ID, duplicate.Version)
 		}
 
-		currentGenres, err := r.genresRepo.GetRelationByMovieID(ctx, movie.ID)
-		if err != nil {
+		if err = r.update(ctx, tx, movie, rev); err != nil {
 			return err
 		}
 
-		nextGenres := slices.MapIndex(movie.Genres, func(i int, g *genres.Genre) *genres.MovieGenreRelation {
-			return &genres.MovieGenreRelation{
-				GenreID: g.ID,
-				MovieID: movie.ID,
-				OrderNo: i,
-			}
-		})
+		b := &pgx.Batch{}
+		b.Queue(
+			`UPDATE reviews r
+			SET deleted_at = $3
+			FROM reviews other
+			WHERE r.movie_id IN ($1, $2)
+			AND other.movie_id IN ($1, $2)
+			AND r.movie_id <> other.movie_id
+			AND r.user_id = other.user_id
+			AND r.deleted_at IS NULL
+			AND other.deleted_at IS NULL
+			AND (r.created_at, r.id) < (other.created_at, other.id)`,
+			movie.ID, duplicate.ID, now,
+		)
+		b.Queue(`UPDATE reviews SET movie_id = $1 WHERE movie_id = $2`, movie.ID, duplicate.ID)
+		b.Queue(`DELETE FROM user_recommendations WHERE movie_id = $1`, duplicate.ID)
+		b.Queue(
+			`UPDATE movie_translations t
+			SET movie_id = $1
+			WHERE t.movie_id = $2
+			AND NOT EXISTS (SELECT 1 FROM movie_translations WHERE movie_id = $1 AND locale = t.locale)`,
+			movie.ID, duplicate.ID,
+		)
+		b.Queue(
+			`UPDATE movie_external_ids e
+			SET movie_id = $1
+			WHERE e.movie_id = $2
+			AND NOT EXISTS (SELECT 1 FROM movie_external_ids WHERE movie_id = $1 AND source = e.source)`,
+			movie.ID, duplicate.ID,
+		)
+		b.Queue(
+			`UPDATE collection_movies
+			SET movie_id = $1
+			WHERE movie_id = $2
+			AND NOT EXISTS (SELECT 1 FROM collection_movies WHERE movie_id = $1)`,
+			movie.ID, duplicate.ID,
+		)
+		b.Queue(`DELETE FROM collection_movies WHERE movie_id = $1`, duplicate.ID)
+		b.Queue(`DELETE FROM movie_genres WHERE movie_id = $1`, duplicate.ID)
+		b.Queue(`DELETE FROM movie_stars WHERE movie_id = $1`, duplicate.ID)
+		b.Queue(`UPDATE movie_redirects SET new_id = $1 WHERE new_id = $2`, movie.ID, duplicate.ID)
+		b.Queue(`INSERT INTO movie_redirects (old_id, new_id) VALUES ($1, $2)`, duplicate.ID, movie.ID)
+		if err = tx.SendBatch(ctx, b).Close(); err != nil {
+			return apperrors.Internal(err)
+		}
+
+		return r.RecalculateRating(ctx, movie.ID)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+// GetMergedInto returns the ID of the movie the movie was merged into, nil when it was not merged.
+func (r *Repository) GetMergedInto(ctx context.Context, movieID int) (*int, error) {
+	var newID int
+	err := r.db.
+		QueryRow(
+			ctx,
+			`SELECT new_id
+			FROM movie_redirects
+			WHERE old_id = $1`,
+			movieID,
+		).
+		Scan(&newID)
+
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, nil
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
 
-		if err = r.updateGenres(ctx, currentGenres, nextGenres); err != nil {
+	return &newID, nil
+}
+
+func (r *Repository) update(ctx context.Context, tx pgx.Tx, movie *MovieDetails, rev *Revision) error {
+	// an empty language keeps the current one
+	err := tx.
+		QueryRow(
+			ctx,
+			`UPDATE movies 
+		SET version = version + 1, 
+		title = $1,
+		description = $2, 
+		release_date = $3,
+		language = COALESCE(NULLIF($4, ''), language),
+		runtime_minutes = $5,
+		countries = $6,
+		spoken_languages = $7,
+		age_rating = $8,
+		tagline = $9
+		WHERE id = $10 
+		AND version = $11
+		RETURNING language;`,
+			movie.Title,
+			movie.Description,
+			movie.ReleaseDate,
+			movie.Language,
+			movie.RuntimeMinutes,
+			movie.Countries,
+			movie.SpokenLanguages,
+			movie.AgeRating,
+			movie.Tagline,
+			movie.ID,
+			movie.Version,
+		).
+		Scan(&movie.Language)
+	switch {
+	case dbx.IsNoRows(err):
+		if _, err = r.GetByID(ctx, movie.ID); err != nil {
 			return err
 		}
 
-		currentCast, err := r.starRepo.GetRelationByMovieID(ctx, movie.ID)
-		if err != nil {
-			return err
+		return apperrors.VersionMismatch("movie", "id", movie.ID, movie.Version)
+	case err != nil:
+		return apperrors.Internal(err)
+	}
+
+	currentGenres, err := r.genresRepo.GetRelationByMovieID(ctx, movie.ID)
+	if err != nil {
+		return err
+	}
+
+	nextGenres := slices.MapIndex(movie.Genres, func(i int, g *genres.Genre) *genres.MovieGenreRelation {
+		return &genres.MovieGenreRelation{
+			GenreID: g.ID,
+			MovieID: movie.ID,
+			OrderNo: i,
 		}
+	})
 
-		nextCast := slices.MapIndex(movie.Cast, func(i int, c *stars.MovieCredit) *stars.MovieStarRelation {
-			return &stars.MovieStarRelation{
-				MovieID: movie.ID,
-				StarID:  c.Star.ID,
-				Role:    c.Role,
-				Details: c.Details,
-				OrderNo: i,
-			}
-		})
+	if err = r.updateGenres(ctx, currentGenres, nextGenres); err != nil {
+		return err
+	}
 
-		return r.updateCast(ctx, currentCast, nextCast)
+	currentCast, err := r.starRepo.GetRelationByMovieID(ctx, movie.ID)
+	if err != nil {
+		return err
+	}
+
+	nextCast := slices.MapIndex(movie.Cast, func(i int, c *stars.MovieCredit) *stars.MovieStarRelation {
+		return &stars.MovieStarRelation{
+			MovieID: movie.ID,
+			StarID:  c.Star.ID,
+			Role:    c.Role,
+			Details: c.Details,
+			OrderNo: i,
+		}
 	})
+	if err = r.updateCast(ctx, currentCast, nextCast); err != nil {
+		return err
+	}
+
+	movie.Version++
+	return r.insertVersion(ctx, tx, movie, rev)
+}
+
+// GetVersions returns the stored versions of the movie from the oldest one.
+func (r *Repository) GetVersions(ctx context.Context, movieID int) ([]*VersionDetails, error) {
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT v.version, v.changed_by, v.rollback_of, v.created_at, v.snapshot
+			FROM movie_versions v
+			INNER JOIN movies m ON m.id = v.movie_id
+			WHERE v.movie_id = $1
+			AND m.deleted_at IS NULL
+			ORDER BY v.version`,
+			movieID,
+		)
 	if err != nil {
-		return apperrors.EnsureInternal(err)
+		return nil, apperrors.Internal(err)
 	}
+	defer rows.Close()
 
-	return nil
+	var versions []*VersionDetails
+	for rows.Next() {
+		version := &VersionDetails{Version: &Version{}}
+		if err = rows.Scan(
+			&version.Version.Version,
+			&version.ChangedBy,
+			&version.RollbackOf,
+			&version.CreatedAt,
+			&version.Snapshot,
+		); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	if len(versions) == 0 {
+		return nil, errMovieWithNotFound(movieID)
+	}
+
+	return versions, nil
+}
+
+func (r *Repository) GetVersion(ctx context.Context, movieID, version int) (*VersionDetails, error) {
+	v := &VersionDetails{Version: &Version{}}
+
+	err := r.db.
+		QueryRow(
+			ctx,
+			`SELECT v.version, v.changed_by, v.rollback_of, v.created_at, v.snapshot
+			FROM movie_versions v
+			INNER JOIN movies m ON m.id = v.movie_id
+			WHERE v.movie_id = $1
+			AND v.version = $2
+			AND m.deleted_at IS NULL`,
+			movieID, version,
+		).
+		Scan(
+			&v.Version.Version,
+			&v.ChangedBy,
+			&v.RollbackOf,
+			&v.CreatedAt,
+			&v.Snapshot,
+		)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("movie version", "version", version)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	return v, nil
 }
 
 func (r *Repository) Delete(ctx context.Context, movieID int) error {
@@ -279,6 +643,291 @@ func (r *Repository) Delete(ctx context.Context, movieID int) error {
 	return nil
 }
 
+// SetPoster replaces the poster of the movie, nil removes it. It returns the key of the previous poster, if any.
+func (r *Repository) SetPoster(ctx context.Context, movieID int, poster *images.Stored) (*string, error) {
+	var key, url, thumbnailURL *string
+	if poster != nil {
+		key, url, thumbnailURL = &poster.Key, &poster.URL, &poster.ThumbnailURL
+	}
+
+	var previous *string
+	err := r.db.
+		QueryRow(
+			ctx,
+			`UPDATE movies t
+			SET poster_key = $2,
+			poster_url = $3,
+			poster_thumbnail_url = $4
+			FROM (SELECT id, poster_key
+				FROM movies
+				WHERE id = $1
+				AND deleted_at IS NULL
+				FOR UPDATE) previous
+			WHERE t.id = previous.id
+			RETURNING previous.poster_key`,
+			movieID, key, url, thumbnailURL,
+		).
+		Scan(&previous)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, errMovieWithNotFound(movieID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	return previous, nil
+}
+
+// GetLocalized returns the most preferred translated fields of the movies for the locales, ordered by preference.
+// Every field falls back along the locales on its own, so a translation without a description doesn't hide
+// the description of a less preferred one.
+func (r *Repository) GetLocalized(ctx context.Context, movieIDs []int, locales []string) (map[int]*localized, error) {
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT m.id,
+				(SELECT t.title
+				FROM movie_translations t
+				WHERE t.movie_id = m.id
+				AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale::text)
+				LIMIT 1),
+				(SELECT t.description
+				FROM movie_translations t
+				WHERE t.movie_id = m.id
+				AND t.locale = ANY($2::text[])
+				AND t.description IS NOT NULL
+				ORDER BY array_position($2::text[], t.locale::text)
+				LIMIT 1)
+			FROM unnest($1::int[]) AS m(id)`,
+			movieIDs,
+			locales,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	result := make(map[int]*localized, len(movieIDs))
+	for rows.Next() {
+		var (
+			movieID int
+			l       localized
+		)
+		if err = rows.Scan(&movieID, &l.Title, &l.Description); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		result[movieID] = &l
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetTranslations(ctx context.Context, movieID int) ([]*Translation, error) {
+	if _, err := r.GetByID(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT movie_id, locale, title, description, created_at
+			FROM movie_translations
+			WHERE movie_id = $1
+			ORDER BY locale`,
+			movieID,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	translations := []*Translation{}
+	for rows.Next() {
+		var translation Translation
+		if err = rows.Scan(
+			&translation.MovieID,
+			&translation.Locale,
+			&translation.Title,
+			&translation.Description,
+			&translation.CreatedAt,
+		); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		translations = append(translations, &translation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return translations, nil
+}
+
+// PutTranslation creates or replaces the translation of the movie to the locale. It leaves the movie itself,
+// including its version, untouched.
+func (r *Repository) PutTranslation(ctx context.Context, translation *Translation) error {
+	if _, err := r.GetByID(ctx, translation.MovieID); err != nil {
+		return err
+	}
+
+	err := r.db.
+		QueryRow(
+			ctx,
+			`INSERT INTO movie_translations (movie_id, locale, title, description)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (movie_id, locale) DO UPDATE
+			SET title = excluded.title,
+			description = excluded.description
+			RETURNING created_at`,
+			translation.MovieID,
+			translation.Locale,
+			translation.Title,
+			translation.Description,
+		).
+		Scan(&translation.CreatedAt)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteTranslation(ctx context.Context, movieID int, locale string) error {
+	n, err := r.db.
+		Exec(
+			ctx,
+			`DELETE FROM movie_translations
+			WHERE movie_id = $1
+			AND locale = $2`,
+			movieID,
+			locale,
+		)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	if n.RowsAffected() == 0 {
+		return apperrors.NotFound("movie translation", "locale", locale)
+	}
+
+	return nil
+}
+
+func applyMovieFilter(sb squirrel.SelectBuilder, filter *MovieFilter) squirrel.SelectBuilder {
+	if filter.StarID != nil || filter.StarRole != nil {
+		credits := dbx.StatementBuilder.
+			Select("1").
+			From("movie_stars").
+			Where("movie_stars.movie_id = movies.id")
+		if filter.StarID != nil {
+			credits = credits.Where("movie_stars.star_id = ?", *filter.StarID)
+		}
+		if filter.StarRole != nil {
+			credits = credits.Where("movie_stars.role = ?", *filter.StarRole)
+		}
+
+		sb = sb.Where(dbx.Exists(credits))
+	}
+
+	if len(filter.GenreIDs) > 0 {
+		genreIDs := distinct(filter.GenreIDs)
+		if filter.MatchAllGenres {
+			sb = sb.Where(
+				`(SELECT COUNT(*)
+				FROM movie_genres
+				WHERE movie_genres.movie_id = movies.id
+				AND movie_genres.genre_id = ANY(?)) = ?`,
+				genreIDs, len(genreIDs),
+			)
+		} else {
+			sb = sb.Where(
+				`EXISTS (SELECT 1
+				FROM movie_genres
+				WHERE movie_genres.movie_id = movies.id
+				AND movie_genres.genre_id = ANY(?))`,
+				genreIDs,
+			)
+		}
+	}
+
+	if filter.ReleasedFrom != nil {
+		sb = sb.Where("release_date >= ?", *filter.ReleasedFrom)
+	}
+
+	if filter.ReleasedTo != nil {
+		sb = sb.Where("release_date <= ?", *filter.ReleasedTo)
+	}
+
+	if filter.MinRating != nil {
+		sb = sb.Where("avg_rating >= ?", *filter.MinRating)
+	}
+
+	if filter.MaxRating != nil {
+		sb = sb.Where("avg_rating <= ?", *filter.MaxRating)
+	}
+
+	// containment rather than ANY, so that the GIN indexes of the arrays are used
+	if filter.Country != nil {
+		sb = sb.Where("countries @> ARRAY[?]::varchar[]", *filter.Country)
+	}
+
+	if filter.SpokenLanguage != nil {
+		sb = sb.Where("spoken_languages @> ARRAY[?]::varchar[]", *filter.SpokenLanguage)
+	}
+
+	if len(filter.AgeRatings) > 0 {
+		sb = sb.Where("age_rating = ANY(?)", filter.AgeRatings)
+	}
+
+	if filter.MinRuntime != nil {
+		sb = sb.Where("runtime_minutes >= ?", *filter.MinRuntime)
+	}
+
+	if filter.MaxRuntime != nil {
+		sb = sb.Where("runtime_minutes <= ?", *filter.MaxRuntime)
+	}
+
+	if filter.ExcludeReviewedBy != nil {
+		sb = sb.Where(
+			`NOT EXISTS (SELECT 1
+			FROM reviews
+			WHERE reviews.movie_id = movies.id
+			AND reviews.user_id = ?
+			AND reviews.deleted_at IS NULL)`,
+			*filter.ExcludeReviewedBy,
+		)
+	}
+
+	if filter.SearchTerm != nil {
+		term := *filter.SearchTerm
+		// trigram similarity of the title tolerates typos that full text search can't match
+		sb = sb.Where(
+			"(search_vector @@ "+tsquery.MatchIn+" OR lower(?) <% lower(title))",
+			append(tsquery.ArgsIn(filter.Language, term), term)...,
+		)
+	}
+
+	return sb
+}
+
+func distinct(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
 func errMovieWithNotFound(movieID int) error {
 	return apperrors.NotFound("movie", "id", movieID)
 }
@@ -323,6 +972,9 @@ func (r *Repository) updateCast(ctx context.Context, current, next []*stars.Movi
 				mgo.Details,
 				mgo.OrderNo,
 			)
+		if dbx.IsForeignKeyViolation(err, "movie_stars_role_fkey") {
+			return apperrors.BadRequest(fmt.Errorf("unknown role %q", mgo.Role))
+		}
 		return err
 	}
 
@@ -344,6 +996,45 @@ func (r *Repository) updateCast(ctx context.Context, current, next []*stars.Movi
 	return dbx.AdjustRelations(current, next, addFunc, removeFn)
 }
 
+func (r *Repository) insertVersion(ctx context.Context, tx pgx.Tx, movie *MovieDetails, rev *Revision) error {
+	_, err := tx.
+		Exec(
+			ctx,
+			`INSERT INTO movie_versions (movie_id, version, snapshot, changed_by, rollback_of)
+			VALUES ($1, $2, $3, $4, $5)`,
+			movie.ID, movie.Version, movie.snapshot(), rev.ChangedBy, rev.RollbackOf,
+		)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	return nil
+}
+
+// RecalculateRating sets the average rating of the movie from its reviews, within the transaction of the context if any.
+func (r *Repository) RecalculateRating(ctx context.Context, movieID int) error {
+	q := dbx.FromContext(ctx, r.db)
+
+	n, err := q.
+		Exec(
+			ctx,
+			`UPDATE movies
+			SET avg_rating = (SELECT AVG(rating) 
+								FROM reviews 
+								WHERE deleted_at IS NULL 
+								AND movie_id = $1) 
+			WHERE id = $1;`,
+			movieID)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	if n.RowsAffected() == 0 {
+		return errMovieWithNotFound(movieID)
+	}
+	return nil
+}
+
 func (r *Repository) Lock(ctx context.Context, tx pgx.Tx, movieID int) error {
 	n, err := tx.
 		Exec(
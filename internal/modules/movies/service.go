@@ -2,29 +2,43 @@ package movies
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/images"
 	"github.com/boichique/movie-reviews/internal/log"
+	"github.com/boichique/movie-reviews/internal/modules/collections"
 	"github.com/boichique/movie-reviews/internal/modules/genres"
+	"github.com/boichique/movie-reviews/internal/modules/movieroles"
 	"github.com/boichique/movie-reviews/internal/modules/stars"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/slices"
 	"golang.org/x/sync/errgroup"
 )
 
 type Service struct {
-	repo          *Repository
-	genresService *genres.Service
-	starsService  *stars.Service
+	repo               *Repository
+	genresService      *genres.Service
+	rolesService       *movieroles.Service
+	starsService       *stars.Service
+	collectionsService *collections.Service
+	images             *images.Service
 }
 
-func NewService(repo *Repository, genresService *genres.Service, starsService *stars.Service) *Service {
+func NewService(repo *Repository, genresService *genres.Service, rolesService *movieroles.Service, starsService *stars.Service, collectionsService *collections.Service, imagesService *images.Service) *Service {
 	return &Service{
-		repo:          repo,
-		genresService: genresService,
-		starsService:  starsService,
+		repo:               repo,
+		genresService:      genresService,
+		rolesService:       rolesService,
+		starsService:       starsService,
+		collectionsService: collectionsService,
+		images:             imagesService,
 	}
 }
 
-func (s *Service) Create(ctx context.Context, movie *MovieDetails) error {
-	if err := s.repo.Create(ctx, movie); err != nil {
+func (s *Service) Create(ctx context.Context, movie *MovieDetails, changedBy int) error {
+	if err := s.repo.Create(ctx, movie, &Revision{ChangedBy: changedBy}); err != nil {
 		return err
 	}
 
@@ -36,22 +50,146 @@ func (s *Service) Create(ctx context.Context, movie *MovieDetails) error {
 	return s.assemble(ctx, movie)
 }
 
-func (s *Service) GetMoviesPaginated(ctx context.Context, searchTerm *string, starID *int, sortByRating *string, offset int, limit int) ([]*Movie, int, error) {
-	return s.repo.GetMoviesPaginated(ctx, searchTerm, starID, sortByRating, offset, limit)
+func (s *Service) GetMoviesPaginated(ctx context.Context, filter *MovieFilter, page *pagination.Page, withFacets bool, locales []string) (*pagination.Result[Movie], *MovieFacets, error) {
+	res, facets, err := s.repo.GetMoviesPaginated(ctx, filter, page, withFacets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(locales) == 0 || len(res.Items) == 0 {
+		return res, facets, nil
+	}
+
+	movieIDs := slices.MapIndex(res.Items, func(_ int, m *Movie) int { return m.ID })
+	translations, err := s.repo.GetLocalized(ctx, movieIDs, locales)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, movie := range res.Items {
+		movie.localize(translations[movie.ID])
+	}
+
+	return res, facets, nil
 }
 
-func (s *Service) GetByID(ctx context.Context, movieID int) (movie *MovieDetails, err error) {
+// GetByID returns the movie translated to the most preferred of the locales, falling back to the original.
+// A movie merged into another one is reported as moved there.
+func (s *Service) GetByID(ctx context.Context, movieID int, locales []string) (movie *MovieDetails, err error) {
 	m, err := s.repo.GetByID(ctx, movieID)
+	if apperrors.Is(err, apperrors.NotFoundCode) {
+		return nil, s.movedOr(ctx, movieID, err)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if len(locales) > 0 {
+		translations, err := s.repo.GetLocalized(ctx, []int{movieID}, locales)
+		if err != nil {
+			return nil, err
+		}
+		m.localize(translations[movieID])
+	}
+
 	err = s.assemble(ctx, m)
 	return m, err
 }
 
-func (s *Service) Update(ctx context.Context, movie *MovieDetails) error {
-	if err := s.repo.Update(ctx, movie); err != nil {
+func (s *Service) GetByExternalID(ctx context.Context, source, externalID string, locales []string) (*MovieDetails, error) {
+	movieID, err := s.repo.GetIDByExternalID(ctx, source, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, movieID, locales)
+}
+
+func (s *Service) PutExternalID(ctx context.Context, movieID int, id *externalids.ID) error {
+	if err := s.repo.PutExternalID(ctx, movieID, id); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"movie external id saved",
+		"movieID", movieID,
+		"source", id.Source,
+		"externalID", id.ID,
+	)
+	return nil
+}
+
+func (s *Service) GetTranslations(ctx context.Context, movieID int) ([]*Translation, error) {
+	return s.repo.GetTranslations(ctx, movieID)
+}
+
+func (s *Service) PutTranslation(ctx context.Context, translation *Translation) error {
+	if err := s.repo.PutTranslation(ctx, translation); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"movie translation saved",
+		"movieID", translation.MovieID,
+		"locale", translation.Locale,
+	)
+	return nil
+}
+
+func (s *Service) DeleteTranslation(ctx context.Context, movieID int, locale string) error {
+	if err := s.repo.DeleteTranslation(ctx, movieID, locale); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"movie translation deleted",
+		"movieID", movieID,
+		"locale", locale,
+	)
+	return nil
+}
+
+// SetPoster stores the image and makes it the poster of the movie, replacing the previous one.
+func (s *Service) SetPoster(ctx context.Context, movieID int, data []byte) (*images.Image, error) {
+	stored, err := s.images.Store(ctx, fmt.Sprintf("movies/%d", movieID), data)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.SetPoster(ctx, movieID, stored)
+	if err != nil {
+		s.images.Delete(ctx, stored.Key)
+		return nil, err
+	}
+	if previous != nil {
+		s.images.Delete(ctx, *previous)
+	}
+
+	log.FromContext(ctx).Info(
+		"movie poster uploaded",
+		"movieID", movieID,
+		"key", stored.Key,
+	)
+	return &stored.Image, nil
+}
+
+func (s *Service) DeletePoster(ctx context.Context, movieID int) error {
+	previous, err := s.repo.SetPoster(ctx, movieID, nil)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		s.images.Delete(ctx, *previous)
+	}
+
+	log.FromContext(ctx).Info(
+		"movie poster deleted",
+		"movieID", movieID,
+	)
+	return nil
+}
+
+func (s *Service) Update(ctx context.Context, movie *MovieDetails, changedBy int) error {
+	if err := s.repo.Update(ctx, movie, &Revision{ChangedBy: changedBy}); err != nil {
 		return err
 	}
 
@@ -60,6 +198,114 @@ func (s *Service) Update(ctx context.Context, movie *MovieDetails) error {
 	return nil
 }
 
+// Merge merges the duplicate into the movie, which gets the genres, cast and reviews of both.
+// The duplicate is deleted and redirects to the movie.
+func (s *Service) Merge(ctx context.Context, movieID, duplicateID, changedBy int) (*MovieDetails, error) {
+	if movieID == duplicateID {
+		return nil, apperrors.BadRequest(fmt.Errorf("movie %d cannot be merged into itself", movieID))
+	}
+
+	movie, err := s.getAssembled(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.getAssembled(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	movie.absorb(duplicate)
+	if err = s.repo.Merge(ctx, movie, duplicate, &Revision{ChangedBy: changedBy}); err != nil {
+		return nil, err
+	}
+
+	log.FromContext(ctx).Info(
+		"movie merged",
+		"movieID", movieID,
+		"duplicateID", duplicateID,
+	)
+	return s.GetByID(ctx, movieID, nil)
+}
+
+// GetVersions returns the versions of the movie from the newest one, each with the changes made by it.
+func (s *Service) GetVersions(ctx context.Context, movieID int) ([]*Version, error) {
+	stored, err := s.repo.GetVersions(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, len(stored))
+	for i, v := range stored {
+		if i == 0 {
+			v.Changes = []*FieldChange{}
+		} else {
+			v.Changes = diff(stored[i-1].Snapshot, v.Snapshot)
+		}
+		versions[len(stored)-1-i] = v.Version
+	}
+
+	return versions, nil
+}
+
+func (s *Service) GetVersion(ctx context.Context, movieID, version int) (*VersionDetails, error) {
+	return s.repo.GetVersion(ctx, movieID, version)
+}
+
+// Rollback restores the movie to the earlier version by storing its state as a new version.
+// Genres, stars and roles deleted since then are left out.
+func (s *Service) Rollback(ctx context.Context, movieID, version, changedBy int) (*MovieDetails, error) {
+	target, err := s.repo.GetVersion(ctx, movieID, version)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetByID(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version == version {
+		return nil, apperrors.BadRequest(fmt.Errorf("version %d is the current version of the movie", version))
+	}
+
+	existing, err := s.genresService.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	genreIDs := slices.MapIndex(existing, func(_ int, g *genres.Genre) int { return g.ID })
+
+	movie := &MovieDetails{
+		Movie:   Movie{ID: movieID},
+		Version: current.Version,
+	}
+	movie.restore(target.Snapshot)
+	movie.Genres = slices.Filter(movie.Genres, func(g *genres.Genre) bool { return slices.Contains(genreIDs, g.ID) })
+
+	starIDs, err := s.starsService.GetExistingIDs(ctx, slices.MapIndex(movie.Cast, func(_ int, c *stars.MovieCredit) int { return c.Star.ID }))
+	if err != nil {
+		return nil, err
+	}
+	movie.Cast = slices.Filter(movie.Cast, func(c *stars.MovieCredit) bool { return slices.Contains(starIDs, c.Star.ID) })
+
+	roles, err := s.rolesService.GetAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	roleNames := slices.MapIndex(roles, func(_ int, r *movieroles.Role) string { return r.Name })
+	movie.Cast = slices.Filter(movie.Cast, func(c *stars.MovieCredit) bool { return slices.Contains(roleNames, c.Role) })
+
+	if err = s.repo.Update(ctx, movie, &Revision{ChangedBy: changedBy, RollbackOf: &version}); err != nil {
+		return nil, err
+	}
+
+	log.FromContext(ctx).Info(
+		"movie rolled back",
+		"movieID", movieID,
+		"version", version,
+		"newVersion", movie.Version,
+	)
+
+	return s.GetByID(ctx, movieID, nil)
+}
+
 func (s *Service) Delete(ctx context.Context, movieID int) error {
 	if err := s.repo.Delete(ctx, movieID); err != nil {
 		return err
@@ -70,6 +316,28 @@ func (s *Service) Delete(ctx context.Context, movieID int) error {
 	return nil
 }
 
+func (s *Service) getAssembled(ctx context.Context, movieID int) (*MovieDetails, error) {
+	movie, err := s.repo.GetByID(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	return movie, s.assemble(ctx, movie)
+}
+
+// movedOr returns the moved error of a merged movie, the not found error otherwise.
+func (s *Service) movedOr(ctx context.Context, movieID int, notFound error) error {
+	newID, err := s.repo.GetMergedInto(ctx, movieID)
+	switch {
+	case err != nil:
+		return err
+	case newID == nil:
+		return notFound
+	}
+
+	return apperrors.Moved("movie", "id", movieID, fmt.Sprintf("/api/movies/%d", *newID))
+}
+
 func (s *Service) assemble(ctx context.Context, movie *MovieDetails) error {
 	group, groupCtx := errgroup.WithContext(ctx)
 
@@ -83,6 +351,11 @@ func (s *Service) assemble(ctx context.Context, movie *MovieDetails) error {
 		movie.Cast, err = s.starsService.GetByMovieID(groupCtx, movie.ID)
 		return err
 	})
+	group.Go(func() error {
+		var err error
+		movie.Collection, err = s.collectionsService.GetByMovieID(groupCtx, movie.ID)
+		return err
+	})
 
 	return group.Wait()
 }
@@ -0,0 +1,31 @@
+package recommendations
+
+import (
+	"net/http"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) GetByUserID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetRecommendationsRequest](c)
+	if err != nil {
+		return err
+	}
+
+	recommendations, err := h.service.GetByUserID(c.Request().Context(), req.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, recommendations)
+}
@@ -0,0 +1,14 @@
+package recommendations
+
+import "github.com/boichique/movie-reviews/internal/modules/movies"
+
+const (
+	SimilarMoviesSource = "similar_movies"
+	TopChartSource      = "top_chart"
+)
+
+type Recommendation struct {
+	Movie  movies.Movie `json:"movie"`
+	Score  float64      `json:"score"`
+	Source string       `json:"source"`
+}
@@ -0,0 +1,218 @@
+package recommendations
+
+import (
+	"context"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/dbx"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// topChartCTE computes the weighted top chart (IMDb formula) inputs. $1 is the number of
+// votes a movie needs to be trusted on its own average.
+const topChartCTE = `WITH stats AS (
+	SELECT movie_id, COUNT(*)::float8 AS votes, AVG(rating)::float8 AS rating
+	FROM reviews
+	WHERE deleted_at IS NULL
+	GROUP BY movie_id
+), mean AS (
+	SELECT COALESCE(AVG(rating), 0)::float8 AS rating
+	FROM reviews
+	WHERE deleted_at IS NULL
+)`
+
+const topChartScore = `(s.votes / (s.votes + $1)) * s.rating + ($1 / (s.votes + $1)) * mean.rating`
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetByUserID(ctx context.Context, userID, limit int) ([]*Recommendation, error) {
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT m.id, m.title, m.release_date, m.avg_rating, m.created_at, `+images.Select("m.poster")+`, ur.score, ur.source
+			FROM user_recommendations ur
+			INNER JOIN movies m ON m.id = ur.movie_id
+			WHERE ur.user_id = $1
+			AND m.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1
+							FROM reviews r
+							WHERE r.user_id = ur.user_id
+							AND r.movie_id = ur.movie_id
+							AND r.deleted_at IS NULL)
+			ORDER BY ur.score DESC, m.id
+			LIMIT $2`,
+			userID,
+			limit,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	return scanRecommendations(rows)
+}
+
+func (r *Repository) GetTopChart(ctx context.Context, userID, minVotes, limit int) ([]*Recommendation, error) {
+	rows, err := r.db.
+		Query(
+			ctx,
+			topChartCTE+`
+			SELECT m.id, m.title, m.release_date, m.avg_rating, m.created_at, `+images.Select("m.poster")+`, `+topChartScore+` AS score, $4
+			FROM movies m
+			INNER JOIN stats s ON s.movie_id = m.id
+			CROSS JOIN mean
+			WHERE m.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1
+							FROM reviews r
+							WHERE r.user_id = $2
+							AND r.movie_id = m.id
+							AND r.deleted_at IS NULL)
+			ORDER BY score DESC, m.id
+			LIMIT $3`,
+			float64(minVotes),
+			userID,
+			limit,
+			TopChartSource,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	return scanRecommendations(rows)
+}
+
+// Refresh replaces all stored recommendations. Users who liked something get item-item
+// collaborative filtering results (movies liked by the same people, weighted by cosine
+// similarity); everybody else gets the weighted top chart.
+func (r *Repository) Refresh(ctx context.Context, minRating, minVotes, limit int) (int64, error) {
+	var total int64
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_recommendations`); err != nil {
+			return apperrors.Internal(err)
+		}
+
+		n, err := tx.
+			Exec(
+				ctx,
+				`INSERT INTO user_recommendations (user_id, movie_id, score, source)
+				WITH likes AS (
+					SELECT r.user_id, r.movie_id
+					FROM reviews r
+					INNER JOIN movies m ON m.id = r.movie_id
+					WHERE r.deleted_at IS NULL
+					AND m.deleted_at IS NULL
+					AND r.rating >= $1
+				), popularity AS (
+					SELECT movie_id, COUNT(*)::float8 AS likes
+					FROM likes
+					GROUP BY movie_id
+				), similarity AS (
+					SELECT a.movie_id, b.movie_id AS similar_movie_id,
+						COUNT(*)::float8 / SQRT(MAX(pa.likes) * MAX(pb.likes)) AS similarity
+					FROM likes a
+					INNER JOIN likes b ON b.user_id = a.user_id AND b.movie_id <> a.movie_id
+					INNER JOIN popularity pa ON pa.movie_id = a.movie_id
+					INNER JOIN popularity pb ON pb.movie_id = b.movie_id
+					GROUP BY a.movie_id, b.movie_id
+				), scores AS (
+					SELECT l.user_id, s.similar_movie_id AS movie_id, SUM(s.similarity) AS score
+					FROM likes l
+					INNER JOIN users u ON u.id = l.user_id
+					INNER JOIN similarity s ON s.movie_id = l.movie_id
+					WHERE u.deleted_at IS NULL
+					AND NOT EXISTS (SELECT 1
+									FROM reviews seen
+									WHERE seen.user_id = l.user_id
+									AND seen.movie_id = s.similar_movie_id
+									AND seen.deleted_at IS NULL)
+					GROUP BY l.user_id, s.similar_movie_id
+				)
+				SELECT user_id, movie_id, score, $3
+				FROM (SELECT user_id, movie_id, score,
+						ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY score DESC, movie_id) AS position
+					FROM scores) ranked
+				WHERE position <= $2`,
+				minRating,
+				limit,
+				SimilarMoviesSource,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		total += n.RowsAffected()
+
+		n, err = tx.
+			Exec(
+				ctx,
+				`INSERT INTO user_recommendations (user_id, movie_id, score, source)
+				`+topChartCTE+`
+				SELECT u.id, chart.movie_id, chart.score, $3
+				FROM users u
+				CROSS JOIN LATERAL (SELECT m.id AS movie_id, `+topChartScore+` AS score
+									FROM movies m
+									INNER JOIN stats s ON s.movie_id = m.id
+									CROSS JOIN mean
+									WHERE m.deleted_at IS NULL
+									AND NOT EXISTS (SELECT 1
+													FROM reviews seen
+													WHERE seen.user_id = u.id
+													AND seen.movie_id = m.id
+													AND seen.deleted_at IS NULL)
+									ORDER BY score DESC, m.id
+									LIMIT $2) chart
+				WHERE u.deleted_at IS NULL
+				AND NOT EXISTS (SELECT 1
+								FROM user_recommendations ur
+								WHERE ur.user_id = u.id)`,
+				float64(minVotes),
+				limit,
+				TopChartSource,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		total += n.RowsAffected()
+
+		return nil
+	})
+	if err != nil {
+		return 0, apperrors.EnsureInternal(err)
+	}
+
+	return total, nil
+}
+
+func scanRecommendations(rows pgx.Rows) ([]*Recommendation, error) {
+	var recommendations []*Recommendation
+	for rows.Next() {
+		var recommendation Recommendation
+		if err := rows.Scan(
+			&recommendation.Movie.ID,
+			&recommendation.Movie.Title,
+			&recommendation.Movie.ReleaseDate,
+			&recommendation.Movie.AvgRating,
+			&recommendation.Movie.CreatedAt,
+			&recommendation.Movie.Poster,
+			&recommendation.Score,
+			&recommendation.Source,
+		); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		recommendations = append(recommendations, &recommendation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return recommendations, nil
+}
@@ -0,0 +1,50 @@
+package recommendations
+
+import (
+	"context"
+
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/log"
+)
+
+type Service struct {
+	repo *Repository
+	cfg  config.RecommendationsConfig
+}
+
+func NewService(repo *Repository, cfg config.RecommendationsConfig) *Service {
+	return &Service{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// GetByUserID returns the stored recommendations of the user. Users the background job
+// has not seen yet get the weighted top chart computed on the fly.
+func (s *Service) GetByUserID(ctx context.Context, userID int) ([]*Recommendation, error) {
+	recommendations, err := s.repo.GetByUserID(ctx, userID, s.cfg.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recommendations) > 0 {
+		return recommendations, nil
+	}
+
+	return s.repo.GetTopChart(ctx, userID, s.cfg.TopChartMinVotes, s.cfg.Limit)
+}
+
+// Refresh recomputes the recommendations of every user. It is meant to be run by the scheduler.
+func (s *Service) Refresh(ctx context.Context) error {
+	n, err := s.repo.Refresh(ctx, s.cfg.MinRating, s.cfg.TopChartMinVotes, s.cfg.Limit)
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"recommendations refreshed",
+		"count", n,
+	)
+
+	return nil
+}
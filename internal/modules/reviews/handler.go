@@ -9,6 +9,7 @@ import (
 	"github.com/boichique/movie-reviews/internal/config"
 	"github.com/boichique/movie-reviews/internal/echox"
 	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/sorting"
 	"github.com/labstack/echo/v4"
 )
 
@@ -35,14 +36,22 @@ func (h *Handler) GetReviewsPaginated(c echo.Context) error {
 	}
 
 	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
-	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
 
-	reviews, total, err := h.service.GetReviewsPaginated(c.Request().Context(), req.MovieID, req.UserID, offset, limit)
+	orders, err := sorting.Parse(req.Sort, sortFields)
+	if err != nil {
+		return err
+	}
+	page, err := pagination.NewPage(&req.PaginatedRequest, sorting.WithTiebreaker(orders, sortTiebreaker))
+	if err != nil {
+		return err
+	}
+
+	reviews, err := h.service.GetReviewsPaginated(c.Request().Context(), req.MovieID, req.UserID, page)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, reviews))
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, reviews))
 }
 
 func (h *Handler) GetByID(c echo.Context) error {
@@ -56,7 +65,7 @@ func (h *Handler) GetByID(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, review)
+	return echox.JSONConditional(c, review, review.UpdatedAt)
 }
 
 func (h *Handler) Create(c echo.Context) error {
@@ -87,6 +96,9 @@ func (h *Handler) Update(c echo.Context) error {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.ReviewID), "review", "id", req.ReviewID); err != nil {
+		return err
+	}
 	if err = h.service.Update(c.Request().Context(), req.ReviewID, req.UserID, req.Title, req.Content, req.Rating); err != nil {
 		return err
 	}
@@ -100,9 +112,19 @@ func (h *Handler) Delete(c echo.Context) error {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.ReviewID), "review", "id", req.ReviewID); err != nil {
+		return err
+	}
 	if err = h.service.Delete(c.Request().Context(), req.ReviewID, req.UserID); err != nil {
 		return err
 	}
 
 	return c.NoContent(http.StatusOK)
 }
+
+// current returns the getter of the review as GET sends it to the client, which If-Match is checked against.
+func (h *Handler) current(c echo.Context, reviewID int) func() (*Review, error) {
+	return func() (*Review, error) {
+		return h.service.GetByID(c.Request().Context(), reviewID)
+	}
+}
@@ -1,6 +1,19 @@
 package reviews
 
-import "time"
+import (
+	"time"
+
+	"github.com/boichique/movie-reviews/internal/sorting"
+)
+
+var (
+	sortFields = sorting.Fields{
+		"id":         "reviews.id",
+		"rating":     "reviews.rating",
+		"created_at": "reviews.created_at",
+	}
+	sortTiebreaker = sorting.Order{Field: "id", Expr: "reviews.id"}
+)
 
 type Review struct {
 	ID        int        `json:"id"`
@@ -10,5 +23,6 @@ type Review struct {
 	Title     string     `json:"title"`
 	Content   string     `json:"content"`
 	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"-"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
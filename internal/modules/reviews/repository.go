@@ -7,6 +7,7 @@ import (
 	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/boichique/movie-reviews/internal/dbx"
 	"github.com/boichique/movie-reviews/internal/modules/movies"
+	"github.com/boichique/movie-reviews/internal/pagination"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -53,7 +54,7 @@ func (r *Repository) Create(ctx context.Context, review *Review) error {
 			return apperrors.Internal(err)
 		}
 
-		return r.recalculateMovieRating(ctx, review.MovieID)
+		return r.moviesRepository.RecalculateRating(ctx, review.MovieID)
 	})
 	if err != nil {
 		return apperrors.EnsureInternal(err)
@@ -68,7 +69,7 @@ func (r *Repository) GetByID(ctx context.Context, reviewID int) (*Review, error)
 	err := r.db.
 		QueryRow(
 			ctx,
-			`SELECT id, movie_id, user_id, title, content, rating, created_at
+			`SELECT id, movie_id, user_id, title, content, rating, created_at, updated_at
 			FROM reviews
 			WHERE deleted_at IS NULL 
 			AND id = $1;`,
@@ -82,6 +83,7 @@ func (r *Repository) GetByID(ctx context.Context, reviewID int) (*Review, error)
 			&review.Content,
 			&review.Rating,
 			&review.CreatedAt,
+			&review.UpdatedAt,
 		)
 	switch {
 	case dbx.IsNoRows(err):
@@ -93,13 +95,11 @@ func (r *Repository) GetByID(ctx context.Context, reviewID int) (*Review, error)
 	return &review, nil
 }
 
-func (r *Repository) GetReviewsPaginated(ctx context.Context, movieID, userID *int, offset int, limit int) ([]*Review, int, error) {
+func (r *Repository) GetReviewsPaginated(ctx context.Context, movieID, userID *int, page *pagination.Page) (*pagination.Result[Review], error) {
 	selectQuery := dbx.StatementBuilder.
 		Select("id", "movie_id", "user_id", "title", "content", "rating", "created_at").
 		From("reviews").
-		Where("deleted_at is null").
-		Limit(uint64(limit)).
-		Offset(uint64(offset))
+		Where("deleted_at is null")
 
 	countQuery := dbx.StatementBuilder.
 		Select("count(*)").
@@ -117,11 +117,13 @@ func (r *Repository) GetReviewsPaginated(ctx context.Context, movieID, userID *i
 	}
 
 	b := &pgx.Batch{}
-	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	if err := dbx.QueueBatchSelect(b, page.Apply(selectQuery)); err != nil {
+		return nil, apperrors.Internal(err)
 	}
-	if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	if page.WithTotal {
+		if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+			return nil, apperrors.Internal(err)
+		}
 	}
 
 	br := r.db.SendBatch(ctx, b)
@@ -129,14 +131,12 @@ func (r *Repository) GetReviewsPaginated(ctx context.Context, movieID, userID *i
 
 	rows, err := br.Query()
 	if err != nil {
-		return nil, 0, apperrors.Internal(err)
+		return nil, apperrors.Internal(err)
 	}
 	defer rows.Close()
 
-	var reviews []*Review
-	for rows.Next() {
-		var review Review
-		if err = rows.Scan(
+	res, err := pagination.Collect(rows, page, func(review *Review) []any {
+		return []any{
 			&review.ID,
 			&review.MovieID,
 			&review.UserID,
@@ -144,22 +144,21 @@ func (r *Repository) GetReviewsPaginated(ctx context.Context, movieID, userID *i
 			&review.Content,
 			&review.Rating,
 			&review.CreatedAt,
-		); err != nil {
-			return nil, 0, apperrors.Internal(err)
 		}
-		reviews = append(reviews, &review)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var total int
-	if err = br.QueryRow().Scan(&total); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	if page.WithTotal {
+		var total int
+		if err = br.QueryRow().Scan(&total); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		res.Total = &total
 	}
 
-	return reviews, total, nil
+	return res, nil
 }
 
 func (r *Repository) Update(ctx context.Context, reviewID, userID int, title, content string, rating int) error {
@@ -195,7 +194,7 @@ func (r *Repository) Update(ctx context.Context, reviewID, userID int, title, co
 			return r.specifyModificationError(ctx, reviewID, userID)
 		}
 
-		return r.recalculateMovieRating(ctx, review.MovieID)
+		return r.moviesRepository.RecalculateRating(ctx, review.MovieID)
 	})
 	if err != nil {
 		return apperrors.Internal(err)
@@ -229,7 +228,7 @@ func (r *Repository) Delete(ctx context.Context, reviewID, userID int) error {
 			return r.specifyModificationError(ctx, reviewID, userID)
 		}
 
-		return r.recalculateMovieRating(ctx, review.MovieID)
+		return r.moviesRepository.RecalculateRating(ctx, review.MovieID)
 	})
 	if err != nil {
 		return apperrors.EnsureInternal(err)
@@ -250,26 +249,3 @@ func (r *Repository) specifyModificationError(ctx context.Context, reviewID, use
 
 	return apperrors.Internal(fmt.Errorf("unexpected error creating/updating review with id %d", reviewID))
 }
-
-func (r *Repository) recalculateMovieRating(ctx context.Context, movieID int) error {
-	q := dbx.FromContext(ctx, r.db)
-
-	n, err := q.
-		Exec(
-			ctx,
-			`UPDATE movies
-			SET avg_rating = (SELECT AVG(rating) 
-								FROM reviews 
-								WHERE deleted_at IS NULL 
-								AND movie_id = $1) 
-			WHERE id = $1;`,
-			movieID)
-	if err != nil {
-		return apperrors.Internal(err)
-	}
-
-	if n.RowsAffected() == 0 {
-		return apperrors.NotFound("movie", "id", movieID)
-	}
-	return nil
-}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/boichique/movie-reviews/internal/log"
+	"github.com/boichique/movie-reviews/internal/pagination"
 )
 
 type Service struct {
@@ -31,8 +32,8 @@ func (s *Service) GetByID(ctx context.Context, reviewID int) (*Review, error) {
 	return s.repo.GetByID(ctx, reviewID)
 }
 
-func (s *Service) GetReviewsPaginated(ctx context.Context, movieID, userID *int, offset int, limit int) ([]*Review, int, error) {
-	return s.repo.GetReviewsPaginated(ctx, movieID, userID, offset, limit)
+func (s *Service) GetReviewsPaginated(ctx context.Context, movieID, userID *int, page *pagination.Page) (*pagination.Result[Review], error) {
+	return s.repo.GetReviewsPaginated(ctx, movieID, userID, page)
 }
 
 func (s *Service) Update(ctx context.Context, reviewID, userID int, title, content string, rating int) error {
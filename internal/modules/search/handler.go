@@ -0,0 +1,101 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/slices"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) Suggest(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.SuggestRequest](c)
+	if err != nil {
+		return err
+	}
+
+	suggestions, err := h.service.Suggest(c.Request().Context(), req.SearchTerm)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, suggestions)
+}
+
+func (h *Handler) Search(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.SearchRequest](c)
+	if err != nil {
+		return err
+	}
+
+	types := []string(req.Types)
+	if len(types) == 0 {
+		types = allTypes
+	}
+	for _, entityType := range types {
+		if !slices.Contains(allTypes, entityType) {
+			return apperrors.BadRequest(fmt.Errorf("unknown search type %q", entityType))
+		}
+	}
+	if (req.After != nil || req.Before != nil) && len(types) != 1 {
+		return apperrors.BadRequest(errors.New("cursors require a single type"))
+	}
+
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+	ctx := c.Request().Context()
+
+	res := &SearchResponse{}
+	for _, entityType := range types {
+		page, err := pagination.NewPage(&req.PaginatedRequest, relevance(entityType, req.SearchTerm))
+		if err != nil {
+			return err
+		}
+
+		switch entityType {
+		case MoviesType:
+			movies, err := h.service.SearchMovies(ctx, req.SearchTerm, page)
+			if err != nil {
+				return err
+			}
+			res.Movies = pagination.Response(&req.PaginatedRequest, movies)
+		case StarsType:
+			stars, err := h.service.SearchStars(ctx, req.SearchTerm, page)
+			if err != nil {
+				return err
+			}
+			res.Stars = pagination.Response(&req.PaginatedRequest, stars)
+		case ReviewsType:
+			reviews, err := h.service.SearchReviews(ctx, req.SearchTerm, page)
+			if err != nil {
+				return err
+			}
+			res.Reviews = pagination.Response(&req.PaginatedRequest, reviews)
+		case UsersType:
+			users, err := h.service.SearchUsers(ctx, req.SearchTerm, page)
+			if err != nil {
+				return err
+			}
+			res.Users = pagination.Response(&req.PaginatedRequest, users)
+		}
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"time"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/modules/movies"
+	"github.com/boichique/movie-reviews/internal/modules/reviews"
+	"github.com/boichique/movie-reviews/internal/modules/stars"
+	"github.com/boichique/movie-reviews/internal/sorting"
+	"github.com/boichique/movie-reviews/internal/tsquery"
+)
+
+const (
+	MovieSuggestion = "movie"
+	StarSuggestion  = "star"
+)
+
+// Searchable entity types. Each of them is named after its table.
+const (
+	MoviesType  = "movies"
+	StarsType   = "stars"
+	ReviewsType = "reviews"
+	UsersType   = "users"
+)
+
+var allTypes = []string{MoviesType, StarsType, ReviewsType, UsersType}
+
+type Suggestion struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+	Year *int   `json:"year,omitempty"`
+}
+
+type SearchResponse struct {
+	Movies  *contracts.PaginatedResponse[movies.Movie]   `json:"movies,omitempty"`
+	Stars   *contracts.PaginatedResponse[stars.Star]     `json:"stars,omitempty"`
+	Reviews *contracts.PaginatedResponse[reviews.Review] `json:"reviews,omitempty"`
+	Users   *contracts.PaginatedResponse[User]           `json:"users,omitempty"`
+}
+
+// User is the public part of a user found by search.
+type User struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Bio       *string   `json:"bio,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// relevance orders the results of the entity type by their rank for the term. The field name includes the type,
+// so that a cursor of one type can't be used for another.
+func relevance(entityType, term string) []sorting.Order {
+	return []sorting.Order{
+		{
+			Field: entityType + "_relevance",
+			Expr:  "ts_rank_cd(" + entityType + ".search_vector, " + match(entityType) + ")",
+			Args:  tsquery.Args(term),
+			Desc:  true,
+		},
+		{Field: "id", Expr: entityType + ".id"},
+	}
+}
+
+// matches is the predicate matching the term against the search vectors of the entity type, along with its arguments.
+func matches(entityType, term string) (string, []any) {
+	if entityType == MoviesType {
+		return tsquery.MatchesInColumn(MoviesType+".search_vector", MoviesType+".language", term)
+	}
+
+	return entityType + ".search_vector @@ " + tsquery.Match, tsquery.Args(term)
+}
+
+// match is the tsquery matching a term against the search vectors of the entity type. Movies are stemmed
+// in their own language, everything else in english.
+func match(entityType string) string {
+	if entityType == MoviesType {
+		return tsquery.MatchInColumn(MoviesType + ".language")
+	}
+
+	return tsquery.Match
+}
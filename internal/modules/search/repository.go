@@ -0,0 +1,212 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/dbx"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/modules/movies"
+	"github.com/boichique/movie-reviews/internal/modules/reviews"
+	"github.com/boichique/movie-reviews/internal/modules/stars"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// Suggest finds movie titles and star names starting with the term, or similar to it to tolerate typos.
+// Stars are found by their display names or aliases, regardless of accents, and suggested by their display names.
+// Prefix matches go first, both kinds of matches are served by the trigram indexes.
+func (r *Repository) Suggest(ctx context.Context, term string, limit int) ([]*Suggestion, error) {
+	term = strings.ToLower(strings.TrimSpace(term))
+	prefix := likeEscaper.Replace(term) + "%"
+
+	rows, err := r.db.
+		Query(
+			ctx,
+			`(SELECT $4::text AS type, id, title AS text, date_part('year', release_date)::int AS year,
+				lower(title) LIKE $2 AS prefix, word_similarity($1, lower(title)) AS score
+			FROM movies
+			WHERE deleted_at IS NULL
+			AND (lower(title) LIKE $2 OR $1 <% lower(title))
+			ORDER BY prefix DESC, score DESC, id
+			LIMIT $3)
+			UNION ALL
+			(SELECT $5::text AS type, id, text, NULL AS year, prefix, score
+			FROM (
+				SELECT DISTINCT ON (s.id) s.id, s.display_name AS text, m.prefix, m.score
+				FROM (
+					SELECT id AS star_id,
+						immutable_unaccent(lower(display_name)) LIKE immutable_unaccent($2) AS prefix,
+						word_similarity(immutable_unaccent($1), immutable_unaccent(lower(display_name))) AS score
+					FROM stars
+					WHERE immutable_unaccent(lower(display_name)) LIKE immutable_unaccent($2)
+					OR immutable_unaccent($1) <% immutable_unaccent(lower(display_name))
+					UNION ALL
+					SELECT star_id,
+						immutable_unaccent(lower(name)) LIKE immutable_unaccent($2) AS prefix,
+						word_similarity(immutable_unaccent($1), immutable_unaccent(lower(name))) AS score
+					FROM star_aliases
+					WHERE immutable_unaccent(lower(name)) LIKE immutable_unaccent($2)
+					OR immutable_unaccent($1) <% immutable_unaccent(lower(name))
+				) m
+				JOIN stars s ON s.id = m.star_id
+				WHERE s.deleted_at IS NULL
+				ORDER BY s.id, m.prefix DESC, m.score DESC
+			) star_matches
+			ORDER BY prefix DESC, score DESC, id
+			LIMIT $3)
+			ORDER BY prefix DESC, score DESC, type, id
+			LIMIT $3`,
+			term,
+			prefix,
+			limit,
+			MovieSuggestion,
+			StarSuggestion,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	suggestions := []*Suggestion{}
+	for rows.Next() {
+		var suggestion Suggestion
+		if err = rows.Scan(
+			&suggestion.Type,
+			&suggestion.ID,
+			&suggestion.Text,
+			&suggestion.Year,
+			nil,
+			nil,
+		); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return suggestions, nil
+}
+
+func (r *Repository) SearchMovies(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[movies.Movie], error) {
+	return queryPage(ctx, r.db, searchQuery(MoviesType, term, "id, title, release_date, avg_rating, created_at, "+images.Select("poster")), page,
+		func(movie *movies.Movie) []any {
+			return []any{
+				&movie.ID,
+				&movie.Title,
+				&movie.ReleaseDate,
+				&movie.AvgRating,
+				&movie.CreatedAt,
+				&movie.Poster,
+			}
+		})
+}
+
+func (r *Repository) SearchStars(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[stars.Star], error) {
+	return queryPage(ctx, r.db, searchQuery(StarsType, term, "id, display_name, first_name, last_name, birth_date, death_date, created_at, "+images.Select("photo")), page,
+		func(star *stars.Star) []any {
+			return []any{
+				&star.ID,
+				&star.DisplayName,
+				&star.FirstName,
+				&star.LastName,
+				&star.BirthDate,
+				&star.DeathDate,
+				&star.CreatedAt,
+				&star.Photo,
+			}
+		})
+}
+
+func (r *Repository) SearchReviews(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[reviews.Review], error) {
+	return queryPage(ctx, r.db, searchQuery(ReviewsType, term, "id, movie_id, user_id, title, content, rating, created_at"), page,
+		func(review *reviews.Review) []any {
+			return []any{
+				&review.ID,
+				&review.MovieID,
+				&review.UserID,
+				&review.Title,
+				&review.Content,
+				&review.Rating,
+				&review.CreatedAt,
+			}
+		})
+}
+
+func (r *Repository) SearchUsers(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[User], error) {
+	return queryPage(ctx, r.db, searchQuery(UsersType, term, "id, username, role, bio, created_at"), page,
+		func(user *User) []any {
+			return []any{
+				&user.ID,
+				&user.Username,
+				&user.Role,
+				&user.Bio,
+				&user.CreatedAt,
+			}
+		})
+}
+
+// searchQuery selects the columns of the entities of the type matching the term.
+func searchQuery(entityType, term, columns string) squirrel.SelectBuilder {
+	predicate, args := matches(entityType, term)
+	return dbx.StatementBuilder.
+		Select(columns).
+		From(entityType).
+		Where("deleted_at IS NULL").
+		Where(predicate, args...)
+}
+
+// queryPage runs the query prepared for the page along with its count, if needed, in one batch.
+func queryPage[T any](ctx context.Context, db *pgxpool.Pool, query squirrel.SelectBuilder, page *pagination.Page, scan func(*T) []any) (*pagination.Result[T], error) {
+	b := &pgx.Batch{}
+	if err := dbx.QueueBatchSelect(b, page.Apply(query)); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	if page.WithTotal {
+		countQuery := query.RemoveColumns().Column("COUNT(*)")
+		if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+	}
+
+	br := db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	res, err := pagination.Collect(rows, page, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.WithTotal {
+		var total int
+		if err = br.QueryRow().Scan(&total); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		res.Total = &total
+	}
+
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package search
+
+import (
+	"context"
+	"errors"
+
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/log"
+	"github.com/boichique/movie-reviews/internal/modules/movies"
+	"github.com/boichique/movie-reviews/internal/modules/reviews"
+	"github.com/boichique/movie-reviews/internal/modules/stars"
+	"github.com/boichique/movie-reviews/internal/pagination"
+)
+
+type Service struct {
+	repo *Repository
+	cfg  config.SearchConfig
+}
+
+func NewService(repo *Repository, cfg config.SearchConfig) *Service {
+	return &Service{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Suggest returns movies and stars matching the term while it is being typed. Suggestions are not worth waiting for,
+// so when the query doesn't fit into the configured timeout no suggestions are returned instead of an error.
+func (s *Service) Suggest(ctx context.Context, term string) ([]*Suggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.SuggestTimeout)
+	defer cancel()
+
+	suggestions, err := s.repo.Suggest(ctx, term, s.cfg.SuggestLimit)
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.FromContext(ctx).Warn(
+			"suggestions timed out",
+			"term", term,
+		)
+		return []*Suggestion{}, nil
+	}
+
+	return suggestions, err
+}
+
+func (s *Service) SearchMovies(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[movies.Movie], error) {
+	return s.repo.SearchMovies(ctx, term, page)
+}
+
+func (s *Service) SearchStars(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[stars.Star], error) {
+	return s.repo.SearchStars(ctx, term, page)
+}
+
+func (s *Service) SearchReviews(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[reviews.Review], error) {
+	return s.repo.SearchReviews(ctx, term, page)
+}
+
+func (s *Service) SearchUsers(ctx context.Context, term string, page *pagination.Page) (*pagination.Result[User], error) {
+	return s.repo.SearchUsers(ctx, term, page)
+}
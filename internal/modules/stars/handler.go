@@ -2,23 +2,31 @@ package stars
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/boichique/movie-reviews/internal/config"
 	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/mergepatch"
 	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/sorting"
 	"github.com/labstack/echo/v4"
+	"gopkg.in/validator.v2"
 )
 
 type Handler struct {
 	service          *Service
 	paginationConfig config.PaginationConfig
+	maxImageSize     int64
 }
 
-func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+func NewHandler(service *Service, paginationConfig config.PaginationConfig, maxImageSize int64) *Handler {
 	return &Handler{
 		service:          service,
 		paginationConfig: paginationConfig,
+		maxImageSize:     maxImageSize,
 	}
 }
 
@@ -30,15 +38,28 @@ func (h *Handler) Create(c echo.Context) error {
 
 	star := &StarDetails{
 		Star: Star{
-			FirstName: req.FirstName,
-			LastName:  req.LastName,
-			BirthDate: req.BirthDate,
-			DeathDate: req.DeathDate,
+			DisplayName: deref(req.DisplayName),
+			FirstName:   req.FirstName,
+			LastName:    req.LastName,
+			BirthDate:   req.BirthDate,
+			DeathDate:   req.DeathDate,
 		},
+		SortName:   deref(req.SortName),
 		MiddleName: req.MiddleName,
 		BirthPlace: req.BirthPlace,
 		Bio:        req.Bio,
 	}
+	if err = star.setNames(); err != nil {
+		return err
+	}
+	star.Aliases, err = aliasesFromContracts(req.Aliases)
+	if err != nil {
+		return err
+	}
+	star.ExternalIDs, err = externalids.FromContracts(req.ExternalIDs)
+	if err != nil {
+		return err
+	}
 
 	err = h.service.Create(c.Request().Context(), star)
 	if err != nil {
@@ -55,14 +76,32 @@ func (h *Handler) GetStarsPaginated(c echo.Context) error {
 	}
 
 	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
-	offset, limit := pagination.OffsetLimit(&req.PaginatedRequest)
 
-	stars, total, err := h.service.GetStarsPaginated(c.Request().Context(), req.MovieID, offset, limit)
+	filter := &StarFilter{
+		MovieID:   req.MovieID,
+		Role:      req.Role,
+		Name:      req.Name,
+		BornFrom:  req.BornFrom,
+		BornTo:    req.BornTo,
+		DiedFrom:  req.DiedFrom,
+		DiedTo:    req.DiedTo,
+		AliveOnly: req.Alive,
+	}
+	explicit, err := sorting.Parse(req.Sort, sortFields)
+	if err != nil {
+		return err
+	}
+	page, err := pagination.NewPage(&req.PaginatedRequest, orders(filter, explicit))
+	if err != nil {
+		return err
+	}
+
+	stars, err := h.service.GetStarsPaginated(c.Request().Context(), filter, page)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, total, stars))
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, stars))
 }
 
 func (h *Handler) GetByID(c echo.Context) error {
@@ -76,7 +115,49 @@ func (h *Handler) GetByID(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, star)
+	return echox.JSONConditional(c, star, star.UpdatedAt)
+}
+
+func (h *Handler) GetFilmography(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetStarRequest](c)
+	if err != nil {
+		return err
+	}
+
+	filmography, err := h.service.GetFilmography(c.Request().Context(), req.StarID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, filmography)
+}
+
+func (h *Handler) GetByExternalID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetByExternalIDRequest](c)
+	if err != nil {
+		return err
+	}
+
+	star, err := h.service.GetByExternalID(c.Request().Context(), externalids.NormalizeSource(req.Source), req.ExternalID)
+	if err != nil {
+		return err
+	}
+
+	return echox.JSONConditional(c, star, star.UpdatedAt)
+}
+
+func (h *Handler) PutExternalID(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.PutStarExternalIDRequest](c)
+	if err != nil {
+		return err
+	}
+
+	id := &externalids.ID{Source: externalids.NormalizeSource(req.Source), ID: req.ExternalID}
+	if err = h.service.PutExternalID(c.Request().Context(), req.StarID, id); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, id)
 }
 
 func (h *Handler) Update(c echo.Context) error {
@@ -84,34 +165,185 @@ func (h *Handler) Update(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	star := &StarDetails{
-		Star: Star{
-			ID:        req.StarID,
-			FirstName: req.FirstName,
-			LastName:  req.LastName,
-			BirthDate: req.BirthDate,
-			DeathDate: req.DeathDate,
-		},
-		MiddleName: req.MiddleName,
-		BirthPlace: req.BirthPlace,
-		Bio:        req.Bio,
+
+	current, err := echox.IfMatch(c, h.current(c, req.StarID), "star", "id", req.StarID)
+	if err != nil {
+		return err
+	}
+
+	star, err := starFromUpdateRequest(req)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		// the star must still be in the version the precondition held for
+		star.Version = current.Version
 	}
 	if err = h.service.Update(c.Request().Context(), star); err != nil {
+		if current != nil && apperrors.Is(err, apperrors.VersionMismatchCode) {
+			return apperrors.PreconditionFailed("star", "id", req.StarID)
+		}
 		return err
 	}
 
 	return c.NoContent(http.StatusOK)
 }
 
+// Patch applies the patch to the current state of the star and updates the star with the result,
+// which is validated as a full update would be.
+func (h *Handler) Patch(c echo.Context) error {
+	req, err := echox.BindPatchAndValidate[contracts.PatchStarRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	current, err := h.service.GetByID(ctx, req.StarID)
+	if err != nil {
+		return err
+	}
+
+	update := &contracts.UpdateStarRequest{
+		FirstName:  current.FirstName,
+		MiddleName: current.MiddleName,
+		LastName:   current.LastName,
+		BirthDate:  current.BirthDate,
+		BirthPlace: current.BirthPlace,
+		DeathDate:  current.DeathDate,
+		Bio:        current.Bio,
+	}
+	// derived names are left out to follow the names they were derived from
+	if current.DisplayName != current.derivedDisplayName() {
+		update.DisplayName = &current.DisplayName
+	}
+	if current.SortName != current.derivedSortName() {
+		update.SortName = &current.SortName
+	}
+	for _, alias := range current.Aliases {
+		update.Aliases = append(update.Aliases, &contracts.StarAlias{Name: alias.Name, Kind: alias.Kind})
+	}
+	if err = mergepatch.ApplyTo(update, req.Fields); err != nil {
+		return apperrors.BadRequest(err)
+	}
+	update.StarID, update.Version = req.StarID, *req.Version
+	if err = validator.Validate(update); err != nil {
+		return apperrors.BadRequest(err)
+	}
+
+	star, err := starFromUpdateRequest(update)
+	if err != nil {
+		return err
+	}
+	if err = h.service.Update(ctx, star); err != nil {
+		return err
+	}
+
+	star, err = h.service.GetByID(ctx, req.StarID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, star)
+}
+
 func (h *Handler) Delete(c echo.Context) error {
 	req, err := echox.BindAndValidate[contracts.DeleteStarRequest](c)
 	if err != nil {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.StarID), "star", "id", req.StarID); err != nil {
+		return err
+	}
 	if err = h.service.Delete(c.Request().Context(), req.StarID); err != nil {
 		return err
 	}
 
 	return c.NoContent(http.StatusOK)
 }
+
+func (h *Handler) Merge(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.MergeStarsRequest](c)
+	if err != nil {
+		return err
+	}
+
+	star, err := h.service.Merge(c.Request().Context(), req.StarID, req.Version, req.DuplicateID, req.DuplicateVersion)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, star)
+}
+
+func (h *Handler) UploadPhoto(c echo.Context) error {
+	data, err := echox.ReadFile(c, "image", h.maxImageSize)
+	if err != nil {
+		return err
+	}
+	req, err := echox.BindAndValidate[contracts.UploadStarPhotoRequest](c)
+	if err != nil {
+		return err
+	}
+
+	image, err := h.service.SetPhoto(c.Request().Context(), req.StarID, data)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, image)
+}
+
+func (h *Handler) DeletePhoto(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteStarPhotoRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.DeletePhoto(c.Request().Context(), req.StarID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// current returns the getter of the star as GET sends it to the client, which If-Match is checked against.
+func (h *Handler) current(c echo.Context, starID int) func() (*StarDetails, error) {
+	return func() (*StarDetails, error) {
+		return h.service.GetByID(c.Request().Context(), starID)
+	}
+}
+
+func starFromUpdateRequest(req *contracts.UpdateStarRequest) (*StarDetails, error) {
+	star := &StarDetails{
+		Star: Star{
+			ID:          req.StarID,
+			DisplayName: deref(req.DisplayName),
+			FirstName:   req.FirstName,
+			LastName:    req.LastName,
+			BirthDate:   req.BirthDate,
+			DeathDate:   req.DeathDate,
+		},
+		SortName:   deref(req.SortName),
+		MiddleName: req.MiddleName,
+		BirthPlace: req.BirthPlace,
+		Bio:        req.Bio,
+		Version:    req.Version,
+	}
+	if err := star.setNames(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	star.Aliases, err = aliasesFromContracts(req.Aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	return star, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
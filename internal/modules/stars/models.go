@@ -1,22 +1,149 @@
 package stars
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/sorting"
+)
+
+var (
+	sortFields = sorting.Fields{
+		"id":         "stars.id",
+		"first_name": "stars.first_name",
+		"last_name":  "stars.last_name",
+		"birth_date": "stars.birth_date",
+		"created_at": "stars.created_at",
+		"name":       "lower(stars.sort_name)",
+		"credits":    creditsCount,
+	}
+	sortTiebreaker = sorting.Order{Field: "id", Expr: "stars.id"}
+)
+
+const (
+	// creditsCount counts the credits of the star in movies which are not deleted
+	creditsCount = `(SELECT COUNT(*)
+		FROM movie_stars
+		INNER JOIN movies ON movies.id = movie_stars.movie_id
+		WHERE movie_stars.star_id = stars.id
+		AND movies.deleted_at IS NULL)`
+	// unaccentedName and unaccentedAlias are the names the trigram indexes are built on, search terms are compared to them
+	unaccentedName  = "immutable_unaccent(lower(stars.display_name))"
+	unaccentedAlias = "immutable_unaccent(lower(star_aliases.name))"
+)
+
+// Kinds of aliases
+const (
+	BirthAlias    = "birth"
+	StageAlias    = "stage"
+	NicknameAlias = "nickname"
+	OtherAlias    = "other"
+)
+
+// StarFilter narrows down the list of stars, nil fields do not filter. Name matches the display names and aliases
+// starting with it, any of their words starting with it or similar to it, regardless of case and accents.
+type StarFilter struct {
+	MovieID   *int
+	Role      *string
+	Name      *string
+	BornFrom  *time.Time
+	BornTo    *time.Time
+	DiedFrom  *time.Time
+	DiedTo    *time.Time
+	AliveOnly bool
+}
 
 type Star struct {
-	ID        int        `json:"id"`
-	FirstName string     `json:"first_name"`
-	LastName  string     `json:"last_name"`
-	BirthDate time.Time  `json:"birth_date"`
-	DeathDate *time.Time `json:"death_date,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID          int           `json:"id"`
+	DisplayName string        `json:"display_name"`
+	FirstName   string        `json:"first_name"`
+	LastName    string        `json:"last_name"`
+	BirthDate   time.Time     `json:"birth_date"`
+	DeathDate   *time.Time    `json:"death_date,omitempty"`
+	Photo       *images.Image `json:"photo,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	DeletedAt   *time.Time    `json:"deleted_at,omitempty"`
 }
 
 type StarDetails struct {
 	Star
-	MiddleName *string `json:"middle_name,omitempty"`
-	BirthPlace *string `json:"birth_place,omitempty"`
-	Bio        *string `json:"bio,omitempty"`
+	SortName    string            `json:"sort_name"`
+	Aliases     []*Alias          `json:"aliases,omitempty"`
+	MiddleName  *string           `json:"middle_name,omitempty"`
+	BirthPlace  *string           `json:"birth_place,omitempty"`
+	Bio         *string           `json:"bio,omitempty"`
+	ExternalIDs []*externalids.ID `json:"external_ids,omitempty"`
+	Version     int               `json:"version"`
+	UpdatedAt   time.Time         `json:"-"`
+}
+
+// Alias is another name the star is known by, Kind tells which one it is, e.g. the birth name.
+type Alias struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// setNames derives the display and sort names left empty from the first and last names,
+// e.g. "George Lucas" and "Lucas, George". A mononym is kept in either of them.
+func (s *StarDetails) setNames() error {
+	if s.DisplayName == "" {
+		s.DisplayName = s.derivedDisplayName()
+	}
+	if s.SortName == "" {
+		s.SortName = s.derivedSortName()
+	}
+	if s.DisplayName == "" || s.SortName == "" {
+		return apperrors.BadRequest(errors.New("either display_name or first_name or last_name must be provided"))
+	}
+
+	return nil
+}
+
+func (s *StarDetails) derivedDisplayName() string {
+	return joinNames(" ", s.FirstName, s.LastName)
+}
+
+func (s *StarDetails) derivedSortName() string {
+	return joinNames(", ", s.LastName, s.FirstName)
+}
+
+func joinNames(sep string, names ...string) string {
+	nonEmpty := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			nonEmpty = append(nonEmpty, name)
+		}
+	}
+
+	return strings.Join(nonEmpty, sep)
+}
+
+// aliasesFromContracts trims the aliases and rejects null aliases and the same alias given twice, regardless of case.
+func aliasesFromContracts(aliases []*contracts.StarAlias) ([]*Alias, error) {
+	result := make([]*Alias, 0, len(aliases))
+	seen := make(map[string]bool, len(aliases))
+	for i, alias := range aliases {
+		// the validator skips nil elements
+		if alias == nil {
+			return nil, apperrors.BadRequest(fmt.Errorf("alias %d is null", i))
+		}
+
+		name := strings.TrimSpace(alias.Name)
+		key := strings.ToLower(name)
+		if seen[key] {
+			return nil, apperrors.BadRequest(fmt.Errorf("alias %q is given more than once", name))
+		}
+		seen[key] = true
+		result = append(result, &Alias{Name: name, Kind: alias.Kind})
+	}
+
+	return result, nil
 }
 
 type MovieCredit struct {
@@ -45,3 +172,79 @@ func (m MovieStarRelation) Key() any {
 		Role:    m.Role,
 	}
 }
+
+// Filmography lists the credits of a star grouped by role, each group in the order the movies were released.
+type Filmography struct {
+	StarID int                `json:"star_id"`
+	Roles  []*FilmographyRole `json:"roles"`
+	Stats  CareerStats        `json:"stats"`
+}
+
+type FilmographyRole struct {
+	Role    string    `json:"role"`
+	Credits []*Credit `json:"credits"`
+}
+
+// Credit is a movie the star has a role in, Details describe the role, e.g. the character played.
+type Credit struct {
+	MovieID     int           `json:"movie_id"`
+	Title       string        `json:"title"`
+	ReleaseDate time.Time     `json:"release_date"`
+	AvgRating   *float64      `json:"avg_rating,omitempty"`
+	Poster      *images.Image `json:"poster,omitempty"`
+	Role        string        `json:"-"`
+	Details     *string       `json:"details,omitempty"`
+}
+
+// CareerStats count each movie of the star once, whatever the number of roles in it.
+// AvgRating is the average of the ratings of the rated movies.
+type CareerStats struct {
+	MoviesCount     int      `json:"movies_count"`
+	AvgRating       *float64 `json:"avg_rating,omitempty"`
+	FirstCreditYear *int     `json:"first_credit_year,omitempty"`
+	LastCreditYear  *int     `json:"last_credit_year,omitempty"`
+}
+
+// newFilmography groups the credits, which are ordered by role and release date, and sums up the career.
+func newFilmography(starID int, credits []*Credit) *Filmography {
+	filmography := &Filmography{
+		StarID: starID,
+		Roles:  []*FilmographyRole{},
+	}
+
+	var ratingsSum float64
+	var ratingsCount int
+	seen := make(map[int]bool)
+	for _, credit := range credits {
+		if n := len(filmography.Roles); n == 0 || filmography.Roles[n-1].Role != credit.Role {
+			filmography.Roles = append(filmography.Roles, &FilmographyRole{Role: credit.Role})
+		}
+		role := filmography.Roles[len(filmography.Roles)-1]
+		role.Credits = append(role.Credits, credit)
+
+		if seen[credit.MovieID] {
+			continue
+		}
+		seen[credit.MovieID] = true
+
+		stats := &filmography.Stats
+		stats.MoviesCount++
+		if credit.AvgRating != nil {
+			ratingsSum += *credit.AvgRating
+			ratingsCount++
+		}
+		year := credit.ReleaseDate.Year()
+		if stats.FirstCreditYear == nil || year < *stats.FirstCreditYear {
+			stats.FirstCreditYear = &year
+		}
+		if stats.LastCreditYear == nil || year > *stats.LastCreditYear {
+			stats.LastCreditYear = &year
+		}
+	}
+	if ratingsCount > 0 {
+		avg := ratingsSum / float64(ratingsCount)
+		filmography.Stats.AvgRating = &avg
+	}
+
+	return filmography
+}
@@ -2,6 +2,7 @@ package stars
 
 import (
 	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/images"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -11,10 +12,10 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool, pagintaionConfig config.PaginationConfig) *Module {
+func NewModule(db *pgxpool.Pool, imagesService *images.Service, pagintaionConfig config.PaginationConfig) *Module {
 	repository := NewRepository(db)
-	service := NewService(repository)
-	handler := NewHandler(service, pagintaionConfig)
+	service := NewService(repository, imagesService)
+	handler := NewHandler(service, pagintaionConfig, imagesService.MaxSize())
 
 	return &Module{
 		Handler:    handler,
@@ -2,14 +2,22 @@ package stars
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/boichique/movie-reviews/internal/dbx"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/sorting"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type Repository struct {
 	db *pgxpool.Pool
 }
@@ -18,98 +26,193 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
+var externalIDs = &externalids.Table{
+	Name:        "star_external_ids",
+	Owners:      "stars",
+	OwnerColumn: "star_id",
+	Subject:     "star",
+}
+
 func (r *Repository) Create(ctx context.Context, star *StarDetails) error {
-	err := r.db.QueryRow(
-		ctx,
-		`INSERT INTO stars (first_name, middle_name, last_name, birth_date, birth_place, death_date, bio)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at;`,
-		star.FirstName,
-		star.MiddleName,
-		star.LastName,
-		star.BirthDate,
-		star.BirthPlace,
-		star.DeathDate,
-		star.Bio,
-	).
-		Scan(&star.ID, &star.CreatedAt)
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx,
+			`INSERT INTO stars (display_name, sort_name, first_name, middle_name, last_name, birth_date, birth_place, death_date, bio)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id, created_at;`,
+			star.DisplayName,
+			star.SortName,
+			star.FirstName,
+			star.MiddleName,
+			star.LastName,
+			star.BirthDate,
+			star.BirthPlace,
+			star.DeathDate,
+			star.Bio,
+		).
+			Scan(&star.ID, &star.CreatedAt)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+
+		if err = insertAliases(ctx, tx, star.ID, star.Aliases); err != nil {
+			return err
+		}
+
+		return externalIDs.Insert(ctx, tx, star.ID, star.ExternalIDs)
+	})
 	if err != nil {
-		return apperrors.Internal(err)
+		return apperrors.EnsureInternal(err)
 	}
 
 	return nil
 }
 
-func (r *Repository) GetStarsPaginated(ctx context.Context, movieID *int, offset int, limit int) ([]*Star, int, error) {
+func (r *Repository) GetStarsPaginated(ctx context.Context, filter *StarFilter, page *pagination.Page) (*pagination.Result[Star], error) {
 	b := &pgx.Batch{}
-	selectQuery := dbx.StatementBuilder.
-		Select("id, first_name, last_name, birth_date, death_date, created_at, deleted_at").
+	selectQuery := applyStarFilter(dbx.StatementBuilder.
+		Select("id, display_name, first_name, last_name, birth_date, death_date, created_at, deleted_at, "+images.Select("photo")).
 		From("stars").
-		Where("deleted_at IS NULL").
-		OrderBy("id").
-		Limit(uint64(limit)).
-		Offset(uint64(offset))
+		Where("deleted_at IS NULL"), filter)
 
-	countQuery := dbx.StatementBuilder.
+	countQuery := applyStarFilter(dbx.StatementBuilder.
 		Select("COUNT(*)").
 		From("stars").
-		Where("deleted_at IS NULL")
-
-	if movieID != nil {
-		selectQuery = selectQuery.
-			Join("movie_stars ON stars.id = movie_stars.star_id").
-			Where("movie_stars.movie_id = ?", movieID)
+		Where("deleted_at IS NULL"), filter)
 
-		countQuery = countQuery.
-			Join("movie_stars ON stars.id = movie_stars.star_id").
-			Where("movie_stars.movie_id = ?", movieID)
-	}
-
-	if err := dbx.QueueBatchSelect(b, selectQuery); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	if err := dbx.QueueBatchSelect(b, page.Apply(selectQuery)); err != nil {
+		return nil, apperrors.Internal(err)
 	}
 
-	if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	if page.WithTotal {
+		if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+			return nil, apperrors.Internal(err)
+		}
 	}
 	br := r.db.SendBatch(ctx, b)
 	defer br.Close()
 
 	rows, err := br.Query()
 	if err != nil {
-		return nil, 0, apperrors.Internal(err)
+		return nil, apperrors.Internal(err)
 	}
 	defer rows.Close()
 
-	var stars []*Star
-	for rows.Next() {
-		var star Star
-		if err = rows.
-			Scan(
-				&star.ID,
-				&star.FirstName,
-				&star.LastName,
-				&star.BirthDate,
-				&star.DeathDate,
-				&star.CreatedAt,
-				&star.DeletedAt,
-			); err != nil {
-			return nil, 0, apperrors.Internal(err)
+	res, err := pagination.Collect(rows, page, func(star *Star) []any {
+		return []any{
+			&star.ID,
+			&star.DisplayName,
+			&star.FirstName,
+			&star.LastName,
+			&star.BirthDate,
+			&star.DeathDate,
+			&star.CreatedAt,
+			&star.DeletedAt,
+			&star.Photo,
 		}
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		stars = append(stars, &star)
+	if page.WithTotal {
+		var total int
+		if err = br.QueryRow().Scan(&total); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		res.Total = &total
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	return res, nil
+}
+
+// orders returns the orders of a star list. Without an explicit sort stars found by name are ordered by relevance,
+// with names starting with the term first. The best matching of the display name and the aliases counts.
+func orders(filter *StarFilter, explicit []sorting.Order) []sorting.Order {
+	if len(explicit) == 0 && filter.Name != nil {
+		term := strings.ToLower(strings.TrimSpace(*filter.Name))
+		prefix := likeEscaper.Replace(term) + "%"
+		explicit = []sorting.Order{
+			{
+				Field: "name_prefix",
+				Expr: "(" + unaccentedName + " LIKE immutable_unaccent(?) OR EXISTS (SELECT 1 FROM star_aliases " +
+					"WHERE star_aliases.star_id = stars.id AND " + unaccentedAlias + " LIKE immutable_unaccent(?)))",
+				Args: []any{prefix, prefix},
+				Desc: true,
+			},
+			{
+				Field: "relevance",
+				// GREATEST ignores the NULL of a star without aliases
+				Expr: "GREATEST(word_similarity(immutable_unaccent(?), " + unaccentedName + "), " +
+					"(SELECT MAX(word_similarity(immutable_unaccent(?), " + unaccentedAlias + ")) FROM star_aliases " +
+					"WHERE star_aliases.star_id = stars.id))",
+				Args: []any{term, term},
+				Desc: true,
+			},
+		}
 	}
 
-	var total int
-	if err = br.QueryRow().Scan(&total); err != nil {
-		return nil, 0, apperrors.Internal(err)
+	return sorting.WithTiebreaker(explicit, sortTiebreaker)
+}
+
+func applyStarFilter(sb squirrel.SelectBuilder, filter *StarFilter) squirrel.SelectBuilder {
+	if filter.MovieID != nil || filter.Role != nil {
+		credits := dbx.StatementBuilder.
+			Select("1").
+			From("movie_stars").
+			Where("movie_stars.star_id = stars.id")
+		if filter.MovieID != nil {
+			credits = credits.Where("movie_stars.movie_id = ?", *filter.MovieID)
+		}
+		if filter.Role != nil {
+			credits = credits.
+				Join("movies ON movies.id = movie_stars.movie_id").
+				Where("movie_stars.role = ?", *filter.Role).
+				Where("movies.deleted_at IS NULL")
+		}
+
+		sb = sb.Where(dbx.Exists(credits))
 	}
 
-	return stars, total, err
+	if filter.Name != nil {
+		term := strings.ToLower(strings.TrimSpace(*filter.Name))
+		prefix := likeEscaper.Replace(term) + "%"
+		// trigram similarity tolerates typos, the indexes on the unaccented names serve all three conditions
+		matches := func(name string) string {
+			return "(" + name + " LIKE immutable_unaccent(?) OR " +
+				name + " LIKE immutable_unaccent(?) OR " +
+				"immutable_unaccent(?) <% " + name + ")"
+		}
+		aliases := dbx.StatementBuilder.
+			Select("1").
+			From("star_aliases").
+			Where("star_aliases.star_id = stars.id").
+			Where(matches(unaccentedAlias), prefix, "% "+prefix, term)
+		sb = sb.Where(
+			squirrel.Or{
+				squirrel.Expr(matches(unaccentedName), prefix, "% "+prefix, term),
+				dbx.Exists(aliases),
+			},
+		)
+	}
+
+	if filter.BornFrom != nil {
+		sb = sb.Where("birth_date >= ?", *filter.BornFrom)
+	}
+	if filter.BornTo != nil {
+		sb = sb.Where("birth_date <= ?", *filter.BornTo)
+	}
+	if filter.DiedFrom != nil {
+		sb = sb.Where("death_date >= ?", *filter.DiedFrom)
+	}
+	if filter.DiedTo != nil {
+		sb = sb.Where("death_date <= ?", *filter.DiedTo)
+	}
+	if filter.AliveOnly {
+		sb = sb.Where("death_date IS NULL")
+	}
+
+	return sb
 }
 
 func (r *Repository) GetByID(ctx context.Context, starID int) (*StarDetails, error) {
@@ -118,7 +221,7 @@ func (r *Repository) GetByID(ctx context.Context, starID int) (*StarDetails, err
 	err := r.db.
 		QueryRow(
 			ctx,
-			`SELECT id, first_name, middle_name, last_name, birth_date, birth_place, death_date, bio, created_at
+			`SELECT id, version, display_name, sort_name, first_name, middle_name, last_name, birth_date, birth_place, death_date, bio, created_at, updated_at, `+images.Select("photo")+`
 			FROM stars
 			WHERE id = $1
 			AND deleted_at IS NULL;`,
@@ -126,6 +229,9 @@ func (r *Repository) GetByID(ctx context.Context, starID int) (*StarDetails, err
 		).
 		Scan(
 			&star.ID,
+			&star.Version,
+			&star.DisplayName,
+			&star.SortName,
 			&star.FirstName,
 			&star.MiddleName,
 			&star.LastName,
@@ -134,6 +240,8 @@ func (r *Repository) GetByID(ctx context.Context, starID int) (*StarDetails, err
 			&star.DeathDate,
 			&star.Bio,
 			&star.CreatedAt,
+			&star.UpdatedAt,
+			&star.Photo,
 		)
 	switch {
 	case dbx.IsNoRows(err):
@@ -142,14 +250,68 @@ func (r *Repository) GetByID(ctx context.Context, starID int) (*StarDetails, err
 		return nil, apperrors.Internal(err)
 	}
 
+	star.Aliases, err = getAliases(ctx, r.db, starID)
+	if err != nil {
+		return nil, err
+	}
+
+	star.ExternalIDs, err = externalIDs.Get(ctx, r.db, starID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &star, nil
 }
 
+// GetExistingIDs returns those of the IDs which belong to stars that aren't deleted.
+func (r *Repository) GetExistingIDs(ctx context.Context, starIDs []int) ([]int, error) {
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT id
+			FROM stars
+			WHERE id = ANY($1)
+			AND deleted_at IS NULL`,
+			starIDs,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return existing, nil
+}
+
+func (r *Repository) GetIDByExternalID(ctx context.Context, source, externalID string) (int, error) {
+	return externalIDs.Resolve(ctx, r.db, source, externalID)
+}
+
+// PutExternalID sets the ID of the star in the source of the ID, replacing the one it had. It leaves the star
+// itself, including its version, untouched.
+func (r *Repository) PutExternalID(ctx context.Context, starID int, id *externalids.ID) error {
+	if _, err := r.GetByID(ctx, starID); err != nil {
+		return err
+	}
+
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		return externalIDs.Put(ctx, tx, starID, id)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
 func (r *Repository) GetByMovieID(ctx context.Context, movieID int) ([]*MovieCredit, error) {
 	rows, err := r.db.
 		Query(
 			ctx,
-			`SELECT s.id, s.first_name, s.last_name, s.birth_date, s.death_date, s.created_at, ms.role, ms.details 
+			`SELECT s.id, s.display_name, s.first_name, s.last_name, s.birth_date, s.death_date, s.created_at, `+images.Select("s.photo")+`, ms.role, ms.details 
 			FROM stars s
 			INNER JOIN movie_stars ms ON ms.star_id = s.id
 			WHERE ms.movie_id = $1
@@ -164,6 +326,41 @@ func (r *Repository) GetByMovieID(ctx context.Context, movieID int) ([]*MovieCre
 	return scanStars(rows)
 }
 
+// GetCredits returns the credits of the star in movies which are not deleted, ordered by role and release date.
+func (r *Repository) GetCredits(ctx context.Context, starID int) ([]*Credit, error) {
+	rows, err := r.db.
+		Query(
+			ctx,
+			`SELECT m.id, m.title, m.release_date, m.avg_rating, `+images.Select("m.poster")+`, ms.role, ms.details
+			FROM movie_stars ms
+			INNER JOIN movies m ON m.id = ms.movie_id
+			WHERE ms.star_id = $1
+			AND m.deleted_at IS NULL
+			ORDER BY ms.role, m.release_date, m.id`,
+			starID,
+		)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var credits []*Credit
+	for rows.Next() {
+		var credit Credit
+		if err = rows.Scan(
+			&credit.MovieID, &credit.Title, &credit.ReleaseDate, &credit.AvgRating,
+			&credit.Poster, &credit.Role, &credit.Details); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		credits = append(credits, &credit)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return credits, nil
+}
+
 func (r *Repository) GetRelationByMovieID(ctx context.Context, movieID int) ([]*MovieStarRelation, error) {
 	rows, err := dbx.FromContext(ctx, r.db).
 		Query(ctx,
@@ -196,36 +393,60 @@ func (r *Repository) GetRelationByMovieID(ctx context.Context, movieID int) ([]*
 	return relations, nil
 }
 
+// Update replaces the fields of the star along with its aliases.
 func (r *Repository) Update(ctx context.Context, star *StarDetails) error {
-	n, err := r.db.
-		Exec(
-			ctx,
-			`UPDATE stars 
-			SET first_name = $1, 
-			middle_name = $2, 
-			last_name = $3, 
-			birth_date = $4, 
-			birth_place = $5, 
-			death_date = $6, 
-			bio = $7 
-			WHERE id = $8`,
-			star.FirstName,
-			star.MiddleName,
-			star.LastName,
-			star.BirthDate,
-			star.BirthPlace,
-			star.DeathDate,
-			star.Bio,
-			star.ID,
-		)
-	if err != nil {
-		return apperrors.Internal(err)
-	}
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.
+			Exec(
+				ctx,
+				`UPDATE stars 
+				SET version = version + 1,
+				display_name = $1,
+				sort_name = $2,
+				first_name = $3, 
+				middle_name = $4, 
+				last_name = $5, 
+				birth_date = $6, 
+				birth_place = $7, 
+				death_date = $8, 
+				bio = $9 
+				WHERE id = $10
+				AND version = $11`,
+				star.DisplayName,
+				star.SortName,
+				star.FirstName,
+				star.MiddleName,
+				star.LastName,
+				star.BirthDate,
+				star.BirthPlace,
+				star.DeathDate,
+				star.Bio,
+				star.ID,
+				star.Version,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
 
-	if n.RowsAffected() == 0 {
-		return errStarWithNotFound(star.ID)
+		if n.RowsAffected() == 0 {
+			if _, err = r.GetByID(ctx, star.ID); err != nil {
+				return err
+			}
+
+			return apperrors.VersionMismatch("star", "id", star.ID, star.Version)
+		}
+
+		if _, err = tx.Exec(ctx, `DELETE FROM star_aliases WHERE star_id = $1`, star.ID); err != nil {
+			return apperrors.Internal(err)
+		}
+
+		return insertAliases(ctx, tx, star.ID, star.Aliases)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
 	}
 
+	star.Version++
 	return nil
 }
 
@@ -250,6 +471,175 @@ func (r *Repository) Delete(ctx context.Context, starID int) error {
 	return nil
 }
 
+// Merge merges the duplicate into the star if both are at the given versions. The credits of the duplicate move
+// to the star unless the star has the same role in the movie, aliases move unless the star has the same one,
+// external IDs move unless the star has one from the same source. The versions of the movies credit the star
+// instead of the duplicate as well, so that they restore the credits of the star. The star gets a new version,
+// the duplicate is deleted and its ID redirects to the star from now on.
+func (r *Repository) Merge(ctx context.Context, starID, version, duplicateID, duplicateVersion int) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		n, err := tx.
+			Exec(
+				ctx,
+				`UPDATE stars
+				SET version = version + 1
+				WHERE id = $1
+				AND version = $2
+				AND deleted_at IS NULL`,
+				starID, version,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			if _, err = r.GetByID(ctx, starID); err != nil {
+				return err
+			}
+
+			return apperrors.VersionMismatch("star", "id", starID, version)
+		}
+
+		n, err = tx.
+			Exec(
+				ctx,
+				`UPDATE stars
+				SET deleted_at = $1
+				WHERE id = $2
+				AND version = $3
+				AND deleted_at IS NULL`,
+				time.Now(), duplicateID, duplicateVersion,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			if _, err = r.GetByID(ctx, duplicateID); err != nil {
+				return err
+			}
+
+			return apperrors.VersionMismatch("star", "id", duplicateID, duplicateVersion)
+		}
+
+		b := &pgx.Batch{}
+		b.Queue(
+			`UPDATE movie_stars ms
+			SET star_id = $1
+			WHERE ms.star_id = $2
+			AND NOT EXISTS (SELECT 1 FROM movie_stars WHERE movie_id = ms.movie_id AND star_id = $1 AND role = ms.role)`,
+			starID, duplicateID,
+		)
+		b.Queue(`DELETE FROM movie_stars WHERE star_id = $1`, duplicateID)
+		b.Queue(
+			`UPDATE movie_versions
+			SET snapshot = jsonb_set(snapshot, '{cast}', (
+				SELECT jsonb_agg(c ORDER BY n)
+				FROM (
+					SELECT c, n, row_number() OVER (PARTITION BY c->'star_id', c->'role' ORDER BY n) AS k
+					FROM (
+						SELECT CASE WHEN (e.c->>'star_id')::int = $2 THEN jsonb_set(e.c, '{star_id}', to_jsonb($1::int)) ELSE e.c END AS c, e.n
+						FROM jsonb_array_elements(snapshot->'cast') WITH ORDINALITY AS e(c, n)
+					) remapped
+				) numbered
+				WHERE k = 1
+			))
+			WHERE snapshot->'cast' @> jsonb_build_array(jsonb_build_object('star_id', $2::int))`,
+			starID, duplicateID,
+		)
+		b.Queue(
+			`UPDATE star_aliases a
+			SET star_id = $1,
+			order_no = a.order_no + (SELECT COALESCE(MAX(order_no) + 1, 0) FROM star_aliases WHERE star_id = $1)
+			WHERE a.star_id = $2
+			AND NOT EXISTS (SELECT 1 FROM star_aliases WHERE star_id = $1 AND name = a.name)`,
+			starID, duplicateID,
+		)
+		b.Queue(
+			`UPDATE star_external_ids e
+			SET star_id = $1
+			WHERE e.star_id = $2
+			AND NOT EXISTS (SELECT 1 FROM star_external_ids WHERE star_id = $1 AND source = e.source)`,
+			starID, duplicateID,
+		)
+		b.Queue(`UPDATE star_redirects SET new_id = $1 WHERE new_id = $2`, starID, duplicateID)
+		b.Queue(`INSERT INTO star_redirects (old_id, new_id) VALUES ($1, $2)`, duplicateID, starID)
+		if err = tx.SendBatch(ctx, b).Close(); err != nil {
+			return apperrors.Internal(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+// GetMergedInto returns the ID of the star the star was merged into, nil when it was not merged.
+func (r *Repository) GetMergedInto(ctx context.Context, starID int) (*int, error) {
+	var newID int
+	err := r.db.
+		QueryRow(
+			ctx,
+			`SELECT new_id
+			FROM star_redirects
+			WHERE old_id = $1`,
+			starID,
+		).
+		Scan(&newID)
+
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, nil
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	return &newID, nil
+}
+
+// insertAliases adds the aliases of the star in the given order.
+func insertAliases(ctx context.Context, q dbx.Queryable, starID int, aliases []*Alias) error {
+	for i, alias := range aliases {
+		_, err := q.Exec(
+			ctx,
+			`INSERT INTO star_aliases (star_id, name, kind, order_no) VALUES ($1, $2, $3, $4)`,
+			starID, alias.Name, alias.Kind, i,
+		)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+	}
+
+	return nil
+}
+
+func getAliases(ctx context.Context, q dbx.Queryable, starID int) ([]*Alias, error) {
+	rows, err := q.Query(
+		ctx,
+		`SELECT name, kind FROM star_aliases WHERE star_id = $1 ORDER BY order_no`,
+		starID,
+	)
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var aliases []*Alias
+	for rows.Next() {
+		var alias Alias
+		if err = rows.Scan(&alias.Name, &alias.Kind); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		aliases = append(aliases, &alias)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	return aliases, nil
+}
+
 func errStarWithNotFound(starID int) error {
 	return apperrors.NotFound("star", "id", starID)
 }
@@ -259,8 +649,8 @@ func scanStars(rows pgx.Rows) ([]*MovieCredit, error) {
 	for rows.Next() {
 		var star MovieCredit
 		if err := rows.Scan(
-			&star.Star.ID, &star.Star.FirstName, &star.Star.LastName, &star.Star.BirthDate,
-			&star.Star.DeathDate, &star.Star.CreatedAt, &star.Role, &star.Details); err != nil {
+			&star.Star.ID, &star.Star.DisplayName, &star.Star.FirstName, &star.Star.LastName, &star.Star.BirthDate,
+			&star.Star.DeathDate, &star.Star.CreatedAt, &star.Star.Photo, &star.Role, &star.Details); err != nil {
 			return nil, apperrors.Internal(err)
 		}
 		stars = append(stars, &star)
@@ -270,3 +660,38 @@ func scanStars(rows pgx.Rows) ([]*MovieCredit, error) {
 	}
 	return stars, nil
 }
+
+// SetPhoto replaces the photo of the star, nil removes it. It returns the key of the previous photo, if any.
+func (r *Repository) SetPhoto(ctx context.Context, starID int, photo *images.Stored) (*string, error) {
+	var key, url, thumbnailURL *string
+	if photo != nil {
+		key, url, thumbnailURL = &photo.Key, &photo.URL, &photo.ThumbnailURL
+	}
+
+	var previous *string
+	err := r.db.
+		QueryRow(
+			ctx,
+			`UPDATE stars t
+			SET photo_key = $2,
+			photo_url = $3,
+			photo_thumbnail_url = $4
+			FROM (SELECT id, photo_key
+				FROM stars
+				WHERE id = $1
+				AND deleted_at IS NULL
+				FOR UPDATE) previous
+			WHERE t.id = previous.id
+			RETURNING previous.photo_key`,
+			starID, key, url, thumbnailURL,
+		).
+		Scan(&previous)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, errStarWithNotFound(starID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	return previous, nil
+}
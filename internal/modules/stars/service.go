@@ -2,16 +2,25 @@ package stars
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/externalids"
+	"github.com/boichique/movie-reviews/internal/images"
 	"github.com/boichique/movie-reviews/internal/log"
+	"github.com/boichique/movie-reviews/internal/pagination"
 )
 
 type Service struct {
-	repo *Repository
+	repo   *Repository
+	images *images.Service
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, imagesService *images.Service) *Service {
+	return &Service{
+		repo:   repo,
+		images: imagesService,
+	}
 }
 
 func (s *Service) Create(ctx context.Context, star *StarDetails) error {
@@ -28,18 +37,105 @@ func (s *Service) Create(ctx context.Context, star *StarDetails) error {
 	return nil
 }
 
-func (s *Service) GetStarsPaginated(ctx context.Context, movieID *int, offset int, limit int) ([]*Star, int, error) {
-	return s.repo.GetStarsPaginated(ctx, movieID, offset, limit)
+func (s *Service) GetStarsPaginated(ctx context.Context, filter *StarFilter, page *pagination.Page) (*pagination.Result[Star], error) {
+	return s.repo.GetStarsPaginated(ctx, filter, page)
 }
 
+// GetByID returns the star, a star merged into another one is reported as moved there.
 func (s *Service) GetByID(ctx context.Context, starID int) (*StarDetails, error) {
-	return s.repo.GetByID(ctx, starID)
+	star, err := s.repo.GetByID(ctx, starID)
+	if apperrors.Is(err, apperrors.NotFoundCode) {
+		return nil, s.movedOr(ctx, starID, err)
+	}
+
+	return star, err
+}
+
+func (s *Service) GetExistingIDs(ctx context.Context, starIDs []int) ([]int, error) {
+	return s.repo.GetExistingIDs(ctx, starIDs)
+}
+
+func (s *Service) GetByExternalID(ctx context.Context, source, externalID string) (*StarDetails, error) {
+	starID, err := s.repo.GetIDByExternalID(ctx, source, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, starID)
+}
+
+func (s *Service) PutExternalID(ctx context.Context, starID int, id *externalids.ID) error {
+	if err := s.repo.PutExternalID(ctx, starID, id); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"star external id saved",
+		"starID", starID,
+		"source", id.Source,
+		"externalID", id.ID,
+	)
+	return nil
+}
+
+// GetFilmography returns the credits of the star grouped by role along with the stats of the career.
+func (s *Service) GetFilmography(ctx context.Context, starID int) (*Filmography, error) {
+	if _, err := s.GetByID(ctx, starID); err != nil {
+		return nil, err
+	}
+
+	credits, err := s.repo.GetCredits(ctx, starID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFilmography(starID, credits), nil
 }
 
 func (s *Service) GetByMovieID(ctx context.Context, movieID int) ([]*MovieCredit, error) {
 	return s.repo.GetByMovieID(ctx, movieID)
 }
 
+// SetPhoto stores the image and makes it the photo of the star, replacing the previous one.
+func (s *Service) SetPhoto(ctx context.Context, starID int, data []byte) (*images.Image, error) {
+	stored, err := s.images.Store(ctx, fmt.Sprintf("stars/%d", starID), data)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.SetPhoto(ctx, starID, stored)
+	if err != nil {
+		s.images.Delete(ctx, stored.Key)
+		return nil, err
+	}
+	if previous != nil {
+		s.images.Delete(ctx, *previous)
+	}
+
+	log.FromContext(ctx).Info(
+		"star photo uploaded",
+		"starID", starID,
+		"key", stored.Key,
+	)
+	return &stored.Image, nil
+}
+
+func (s *Service) DeletePhoto(ctx context.Context, starID int) error {
+	previous, err := s.repo.SetPhoto(ctx, starID, nil)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		s.images.Delete(ctx, *previous)
+	}
+
+	log.FromContext(ctx).Info(
+		"star photo deleted",
+		"starID", starID,
+	)
+	return nil
+}
+
 func (s *Service) Update(ctx context.Context, star *StarDetails) error {
 	if err := s.repo.Update(ctx, star); err != nil {
 		return err
@@ -65,3 +161,35 @@ func (s *Service) Delete(ctx context.Context, starID int) error {
 
 	return nil
 }
+
+// Merge merges the duplicate into the star, which gets the credits of both. The duplicate is deleted and redirects to the star.
+// Both stars must be at the given versions.
+func (s *Service) Merge(ctx context.Context, starID, version, duplicateID, duplicateVersion int) (*StarDetails, error) {
+	if starID == duplicateID {
+		return nil, apperrors.BadRequest(fmt.Errorf("star %d cannot be merged into itself", starID))
+	}
+
+	if err := s.repo.Merge(ctx, starID, version, duplicateID, duplicateVersion); err != nil {
+		return nil, err
+	}
+
+	log.FromContext(ctx).Info(
+		"star merged",
+		"starID", starID,
+		"duplicateID", duplicateID,
+	)
+	return s.repo.GetByID(ctx, starID)
+}
+
+// movedOr returns the moved error of a merged star, the not found error otherwise.
+func (s *Service) movedOr(ctx context.Context, starID int, notFound error) error {
+	newID, err := s.repo.GetMergedInto(ctx, starID)
+	switch {
+	case err != nil:
+		return err
+	case newID == nil:
+		return notFound
+	}
+
+	return apperrors.Moved("star", "id", starID, fmt.Sprintf("/api/stars/%d", *newID))
+}
@@ -0,0 +1,58 @@
+package trash
+
+import (
+	"net/http"
+
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/boichique/movie-reviews/internal/sorting"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service          *Service
+	paginationConfig config.PaginationConfig
+}
+
+func NewHandler(service *Service, paginationConfig config.PaginationConfig) *Handler {
+	return &Handler{
+		service:          service,
+		paginationConfig: paginationConfig,
+	}
+}
+
+func (h *Handler) GetDeletedPaginated(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetTrashRequest](c)
+	if err != nil {
+		return err
+	}
+
+	pagination.SetDefaults(&req.PaginatedRequest, h.paginationConfig)
+
+	orders, err := sorting.Parse(req.Sort, sortFields)
+	if err != nil {
+		return err
+	}
+	page, err := pagination.NewPage(&req.PaginatedRequest, sorting.WithTiebreaker(orders, sortTiebreaker))
+	if err != nil {
+		return err
+	}
+
+	items, err := h.service.GetDeletedPaginated(c.Request().Context(), req.Entity, page)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, pagination.Response(&req.PaginatedRequest, items))
+}
+
+func (h *Handler) Restore(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.RestoreFromTrashRequest](c)
+	if err != nil {
+		return err
+	}
+
+	return h.service.Restore(c.Request().Context(), req.Entity, req.ID)
+}
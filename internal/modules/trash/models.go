@@ -0,0 +1,57 @@
+package trash
+
+import (
+	"time"
+
+	"github.com/boichique/movie-reviews/internal/sorting"
+)
+
+var (
+	sortFields = sorting.Fields{
+		"id":         "id",
+		"deleted_at": "deleted_at",
+	}
+	sortTiebreaker = sorting.Order{Field: "id", Expr: "id"}
+)
+
+// Item is a soft-deleted entity, it is purged for good at PurgeAt.
+type Item struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// entity describes a table with soft-deleted rows, title is the expression naming a row in the listing.
+// Rows listed in the redirects table were merged into other ones and cannot be restored.
+type entity struct {
+	table     string
+	subject   string
+	title     string
+	redirects string
+}
+
+const (
+	Movies  = "movies"
+	Stars   = "stars"
+	Users   = "users"
+	Reviews = "reviews"
+)
+
+var entities = map[string]*entity{
+	Movies:  {table: "movies", subject: "movie", title: "title", redirects: "movie_redirects"},
+	Stars:   {table: "stars", subject: "star", title: "display_name", redirects: "star_redirects"},
+	Users:   {table: "users", subject: "user", title: "username"},
+	Reviews: {table: "reviews", subject: "review", title: "title"},
+}
+
+// Purged holds the numbers of purged entities and the keys of their images.
+type Purged struct {
+	Movies     int
+	Stars      int
+	Users      int
+	Reviews    int
+	PosterKeys []string
+	PhotoKeys  []string
+	AvatarKeys []string
+}
@@ -0,0 +1,32 @@
+package trash
+
+import (
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/modules/movies"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	Handler    *Handler
+	Service    *Service
+	Repository *Repository
+}
+
+func NewModule(
+	db *pgxpool.Pool,
+	moviesModule *movies.Module,
+	imagesService *images.Service,
+	cfg config.TrashConfig,
+	paginationConfig config.PaginationConfig,
+) *Module {
+	repository := NewRepository(db, moviesModule.Repository)
+	service := NewService(repository, imagesService, cfg)
+	handler := NewHandler(service, paginationConfig)
+
+	return &Module{
+		Handler:    handler,
+		Service:    service,
+		Repository: repository,
+	}
+}
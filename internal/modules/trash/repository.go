@@ -0,0 +1,298 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/dbx"
+	"github.com/boichique/movie-reviews/internal/modules/movies"
+	"github.com/boichique/movie-reviews/internal/pagination"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db         *pgxpool.Pool
+	moviesRepo *movies.Repository
+}
+
+func NewRepository(db *pgxpool.Pool, moviesRepo *movies.Repository) *Repository {
+	return &Repository{
+		db:         db,
+		moviesRepo: moviesRepo,
+	}
+}
+
+func (r *Repository) GetDeletedPaginated(ctx context.Context, e *entity, page *pagination.Page) (*pagination.Result[Item], error) {
+	b := &pgx.Batch{}
+	selectQuery := dbx.StatementBuilder.
+		Select("id", e.title, "deleted_at").
+		From(e.table).
+		Where("deleted_at IS NOT NULL")
+
+	if err := dbx.QueueBatchSelect(b, page.Apply(selectQuery)); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	if page.WithTotal {
+		countQuery := dbx.StatementBuilder.
+			Select("COUNT(*)").
+			From(e.table).
+			Where("deleted_at IS NOT NULL")
+		if err := dbx.QueueBatchSelect(b, countQuery); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+	}
+	br := r.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	res, err := pagination.Collect(rows, page, func(item *Item) []any {
+		return []any{
+			&item.ID,
+			&item.Title,
+			&item.DeletedAt,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if page.WithTotal {
+		var total int
+		if err = br.QueryRow().Scan(&total); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+		res.Total = &total
+	}
+
+	return res, nil
+}
+
+// Restore undeletes a movie, star or user. The average rating of a restored movie is recalculated,
+// as its reviews might have changed meanwhile. Merged movies and stars are not restored.
+func (r *Repository) Restore(ctx context.Context, e *entity, id int) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		if e.redirects != "" {
+			var newID int
+			err := tx.QueryRow(ctx, `SELECT new_id FROM `+e.redirects+` WHERE old_id = $1`, id).Scan(&newID)
+			switch {
+			case err == nil:
+				return apperrors.BadRequest(fmt.Errorf("%s %d was merged into %s %d", e.subject, id, e.subject, newID))
+			case !dbx.IsNoRows(err):
+				return apperrors.Internal(err)
+			}
+		}
+
+		n, err := tx.
+			Exec(
+				ctx,
+				`UPDATE `+e.table+`
+				SET deleted_at = NULL
+				WHERE id = $1
+				AND deleted_at IS NOT NULL`,
+				id,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if n.RowsAffected() == 0 {
+			return errNotInTrash(e, id)
+		}
+
+		if e == entities[Movies] {
+			return r.moviesRepo.RecalculateRating(ctx, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+// RestoreReview undeletes the review unless its movie is deleted or the user has reviewed the movie again,
+// and recalculates the average rating of the movie.
+func (r *Repository) RestoreReview(ctx context.Context, reviewID int) error {
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		var movieID, userID int
+		err := tx.
+			QueryRow(
+				ctx,
+				`SELECT movie_id, user_id
+				FROM reviews
+				WHERE id = $1
+				AND deleted_at IS NOT NULL
+				FOR UPDATE`,
+				reviewID,
+			).
+			Scan(&movieID, &userID)
+		switch {
+		case dbx.IsNoRows(err):
+			return errNotInTrash(entities[Reviews], reviewID)
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		if err = r.moviesRepo.Lock(ctx, tx, movieID); err != nil {
+			if apperrors.Is(err, apperrors.NotFoundCode) {
+				return apperrors.BadRequest(fmt.Errorf("movie %d of the review is deleted, restore it first", movieID))
+			}
+			return err
+		}
+
+		_, err = tx.
+			Exec(
+				ctx,
+				`UPDATE reviews
+				SET deleted_at = NULL
+				WHERE id = $1`,
+				reviewID,
+			)
+		switch {
+		case dbx.IsUniqueViolation(err, "movie_id_user_id"):
+			return apperrors.AlreadyExists("review", "(movie_id,user_id)", fmt.Sprintf("(%d,%d)", movieID, userID))
+		case err != nil:
+			return apperrors.Internal(err)
+		}
+
+		return r.moviesRepo.RecalculateRating(ctx, movieID)
+	})
+	if err != nil {
+		return apperrors.EnsureInternal(err)
+	}
+
+	return nil
+}
+
+// Purge hard-deletes the entities deleted before the cutoff along with the rows referring to them.
+// Reviews of purged movies and users go too, live ones changing the ratings of their movies.
+// Purged stars leave the cast of the movies they starred in.
+func (r *Repository) Purge(ctx context.Context, cutoff time.Time) (*Purged, error) {
+	const (
+		purgedMovies = `(SELECT id FROM movies WHERE deleted_at < $1)`
+		purgedStars  = `(SELECT id FROM stars WHERE deleted_at < $1)`
+		purgedUsers  = `(SELECT id FROM users WHERE deleted_at < $1)`
+	)
+
+	var purged Purged
+	err := dbx.InTransaction(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.
+			Query(
+				ctx,
+				`DELETE FROM reviews
+				WHERE deleted_at < $1
+				OR movie_id IN `+purgedMovies+`
+				OR user_id IN `+purgedUsers+`
+				RETURNING movie_id, deleted_at IS NULL`,
+				cutoff,
+			)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+
+		// movies which lost live reviews, those being purged are skipped later
+		rerated := make(map[int]bool)
+		for rows.Next() {
+			var movieID int
+			var live bool
+			if err = rows.Scan(&movieID, &live); err != nil {
+				rows.Close()
+				return apperrors.Internal(err)
+			}
+			if live {
+				rerated[movieID] = true
+			}
+			purged.Reviews++
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return apperrors.Internal(err)
+		}
+
+		for _, stmt := range []string{
+			`DELETE FROM user_recommendations WHERE movie_id IN ` + purgedMovies + ` OR user_id IN ` + purgedUsers,
+			`UPDATE movie_versions SET changed_by = NULL WHERE changed_by IN ` + purgedUsers,
+			`DELETE FROM movie_versions WHERE movie_id IN ` + purgedMovies,
+			`DELETE FROM movie_genres WHERE movie_id IN ` + purgedMovies,
+			`DELETE FROM movie_stars WHERE movie_id IN ` + purgedMovies + ` OR star_id IN ` + purgedStars,
+			`DELETE FROM movie_translations WHERE movie_id IN ` + purgedMovies,
+			`DELETE FROM movie_external_ids WHERE movie_id IN ` + purgedMovies,
+			`DELETE FROM collection_movies WHERE movie_id IN ` + purgedMovies,
+			`DELETE FROM star_external_ids WHERE star_id IN ` + purgedStars,
+		} {
+			if _, err = tx.Exec(ctx, stmt, cutoff); err != nil {
+				return apperrors.Internal(err)
+			}
+		}
+
+		var movieIDs, starIDs, userIDs []int
+		if movieIDs, purged.PosterKeys, err = deleteReturningKeys(ctx, tx, `DELETE FROM movies WHERE deleted_at < $1 RETURNING id, poster_key`, cutoff); err != nil {
+			return err
+		}
+		if starIDs, purged.PhotoKeys, err = deleteReturningKeys(ctx, tx, `DELETE FROM stars WHERE deleted_at < $1 RETURNING id, photo_key`, cutoff); err != nil {
+			return err
+		}
+		if userIDs, purged.AvatarKeys, err = deleteReturningKeys(ctx, tx, `DELETE FROM users WHERE deleted_at < $1 RETURNING id, avatar_key`, cutoff); err != nil {
+			return err
+		}
+		purged.Movies, purged.Stars, purged.Users = len(movieIDs), len(starIDs), len(userIDs)
+
+		for _, movieID := range movieIDs {
+			delete(rerated, movieID)
+		}
+		for movieID := range rerated {
+			if err = r.moviesRepo.RecalculateRating(ctx, movieID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.EnsureInternal(err)
+	}
+
+	return &purged, nil
+}
+
+// deleteReturningKeys runs the statement returning the ids of the deleted rows and their image keys, if set.
+func deleteReturningKeys(ctx context.Context, tx pgx.Tx, stmt string, cutoff time.Time) ([]int, []string, error) {
+	rows, err := tx.Query(ctx, stmt, cutoff)
+	if err != nil {
+		return nil, nil, apperrors.Internal(err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	var keys []string
+	for rows.Next() {
+		var id int
+		var key *string
+		if err = rows.Scan(&id, &key); err != nil {
+			return nil, nil, apperrors.Internal(err)
+		}
+
+		ids = append(ids, id)
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, apperrors.Internal(err)
+	}
+
+	return ids, keys, nil
+}
+
+func errNotInTrash(e *entity, id int) error {
+	return apperrors.NotFound("deleted "+e.subject, "id", id)
+}
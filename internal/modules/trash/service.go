@@ -0,0 +1,101 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/boichique/movie-reviews/internal/log"
+	"github.com/boichique/movie-reviews/internal/modules/users"
+	"github.com/boichique/movie-reviews/internal/pagination"
+)
+
+type Service struct {
+	repo   *Repository
+	images *images.Service
+	cfg    config.TrashConfig
+}
+
+func NewService(repo *Repository, imagesService *images.Service, cfg config.TrashConfig) *Service {
+	return &Service{
+		repo:   repo,
+		images: imagesService,
+		cfg:    cfg,
+	}
+}
+
+func (s *Service) GetDeletedPaginated(ctx context.Context, entityName string, page *pagination.Page) (*pagination.Result[Item], error) {
+	e, err := getEntity(entityName)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.repo.GetDeletedPaginated(ctx, e, page)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range res.Items {
+		item.PurgeAt = item.DeletedAt.Add(s.cfg.Retention)
+	}
+	return res, nil
+}
+
+func (s *Service) Restore(ctx context.Context, entityName string, id int) error {
+	e, err := getEntity(entityName)
+	if err != nil {
+		return err
+	}
+
+	if e == entities[Reviews] {
+		err = s.repo.RestoreReview(ctx, id)
+	} else {
+		err = s.repo.Restore(ctx, e, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info(
+		"entity restored",
+		"entity", entityName,
+		"id", id,
+	)
+	return nil
+}
+
+// Purge hard-deletes the entities deleted longer than the retention period ago and removes their images.
+func (s *Service) Purge(ctx context.Context) error {
+	purged, err := s.repo.Purge(ctx, time.Now().Add(-s.cfg.Retention))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range append(purged.PosterKeys, purged.PhotoKeys...) {
+		s.images.Delete(ctx, key)
+	}
+	for _, key := range purged.AvatarKeys {
+		s.images.DeleteSquare(ctx, key, users.AvatarSizes)
+	}
+
+	log.FromContext(ctx).Info(
+		"trash purged",
+		"movies", purged.Movies,
+		"stars", purged.Stars,
+		"users", purged.Users,
+		"reviews", purged.Reviews,
+	)
+	return nil
+}
+
+func getEntity(name string) (*entity, error) {
+	e, ok := entities[name]
+	if !ok {
+		return nil, apperrors.BadRequest(fmt.Errorf("unknown entity %q, expected one of movies, stars, users or reviews", name))
+	}
+
+	return e, nil
+}
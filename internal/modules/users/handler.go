@@ -5,15 +5,20 @@ import (
 
 	"github.com/boichique/movie-reviews/contracts"
 	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/boichique/movie-reviews/internal/images"
 	"github.com/labstack/echo/v4"
 )
 
 type Handler struct {
-	service *Service
+	service      *Service
+	maxImageSize int64
 }
 
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, maxImageSize int64) *Handler {
+	return &Handler{
+		service:      service,
+		maxImageSize: maxImageSize,
+	}
 }
 
 func (h Handler) GetByID(c echo.Context) error {
@@ -27,7 +32,7 @@ func (h Handler) GetByID(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, user)
+	return echox.JSONConditional(c, user, user.UpdatedAt)
 }
 
 func (h Handler) GetByUsername(c echo.Context) error {
@@ -41,7 +46,7 @@ func (h Handler) GetByUsername(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, user)
+	return echox.JSONConditional(c, user, user.UpdatedAt)
 }
 
 func (h *Handler) UpdateBio(c echo.Context) error {
@@ -50,6 +55,9 @@ func (h *Handler) UpdateBio(c echo.Context) error {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.UserID), "user", "id", req.UserID); err != nil {
+		return err
+	}
 	return h.service.UpdateBio(c.Request().Context(), req.UserID, *req.Bio)
 }
 
@@ -59,6 +67,9 @@ func (h *Handler) UpdateRole(c echo.Context) error {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.UserID), "user", "id", req.UserID); err != nil {
+		return err
+	}
 	return h.service.UpdateRole(c.Request().Context(), req.UserID, req.Role)
 }
 
@@ -68,5 +79,64 @@ func (h *Handler) Delete(c echo.Context) error {
 		return err
 	}
 
+	if _, err = echox.IfMatch(c, h.current(c, req.UserID), "user", "id", req.UserID); err != nil {
+		return err
+	}
 	return h.service.DeleteUser(c.Request().Context(), req.UserID)
 }
+
+func (h *Handler) UploadAvatar(c echo.Context) error {
+	data, err := echox.ReadFile(c, "image", h.maxImageSize)
+	if err != nil {
+		return err
+	}
+	req, err := echox.BindAndValidate[contracts.UploadUserAvatarRequest](c)
+	if err != nil {
+		return err
+	}
+
+	avatar, err := h.service.SetAvatar(c.Request().Context(), req.UserID, data)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, avatar)
+}
+
+func (h *Handler) DeleteAvatar(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.DeleteUserAvatarRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.DeleteAvatar(c.Request().Context(), req.UserID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// current returns the getter of the user as GET sends it to the client, which If-Match is checked against.
+func (h *Handler) current(c echo.Context, userID int) func() (*User, error) {
+	return func() (*User, error) {
+		return h.service.GetExistingUserByID(c.Request().Context(), userID)
+	}
+}
+
+// GetIdenticon serves the default avatar of the user. It depends only on the user id, so it is cached for long.
+func (h *Handler) GetIdenticon(c echo.Context) error {
+	req, err := echox.BindAndValidate[contracts.GetUserIdenticonRequest](c)
+	if err != nil {
+		return err
+	}
+	if req.Size == 0 {
+		req.Size = AvatarMedium
+	}
+
+	data, err := images.Identicon(identiconSeed(req.UserID), req.Size)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=86400")
+	return c.Blob(http.StatusOK, "image/png", data)
+}
@@ -14,10 +14,31 @@ type User struct {
 	Email     string     `json:"email"`
 	Role      string     `json:"role"`
 	Bio       *string    `json:"bio,omitempty"`
+	Avatar    *Avatar    `json:"avatar,omitempty"`
+	AvatarKey *string    `json:"-"`
 	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"-"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
+// Avatar sizes in pixels, an uploaded avatar is stored in each of them
+const (
+	AvatarSmall  = 48
+	AvatarMedium = 128
+	AvatarLarge  = 256
+)
+
+var AvatarSizes = []int{AvatarSmall, AvatarMedium, AvatarLarge}
+
+// Avatar holds the URLs of the square avatar images, Default is set when the user has not uploaded one
+// and the URLs point to the generated identicon.
+type Avatar struct {
+	Small   string `json:"small"`
+	Medium  string `json:"medium"`
+	Large   string `json:"large"`
+	Default bool   `json:"default"`
+}
+
 func (u *User) IsDeleted() bool {
 	return u.DeletedAt != nil
 }
@@ -1,6 +1,9 @@
 package users
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"github.com/boichique/movie-reviews/internal/images"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
 
 type Module struct {
 	Handler    *Handler
@@ -8,10 +11,10 @@ type Module struct {
 	Repository *Repository
 }
 
-func NewModule(db *pgxpool.Pool) *Module {
+func NewModule(db *pgxpool.Pool, imagesService *images.Service, publicURL string) *Module {
 	repository := NewRepository(db)
-	service := NewService(repository)
-	handler := NewHandler(service)
+	service := NewService(repository, imagesService, publicURL)
+	handler := NewHandler(service, imagesService.MaxSize())
 
 	return &Module{
 		Handler:    handler,
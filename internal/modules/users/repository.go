@@ -51,7 +51,7 @@ func (r *Repository) GetExistingUserWithPasswordByEmail(ctx context.Context, ema
 	err := r.db.
 		QueryRow(
 			ctx,
-			`SELECT id, username, email, pass_hash, role, created_at, deleted_at, bio 
+			`SELECT id, username, email, pass_hash, role, created_at, deleted_at, bio, avatar_key 
 			FROM users 
 			WHERE email = $1 
 			AND deleted_at IS NULL;`,
@@ -66,6 +66,7 @@ func (r *Repository) GetExistingUserWithPasswordByEmail(ctx context.Context, ema
 			&user.CreatedAt,
 			&user.DeletedAt,
 			&user.Bio,
+			&user.AvatarKey,
 		)
 
 	switch {
@@ -84,7 +85,7 @@ func (r *Repository) GetExistingUserByID(ctx context.Context, userID int) (*User
 	err := r.db.
 		QueryRow(
 			ctx,
-			`SELECT id, username, email, role, created_at, bio 
+			`SELECT id, username, email, role, created_at, updated_at, bio, avatar_key 
 			FROM users 
 			WHERE id = $1 
 			AND deleted_at IS NULL;`,
@@ -96,7 +97,9 @@ func (r *Repository) GetExistingUserByID(ctx context.Context, userID int) (*User
 			&user.Email,
 			&user.Role,
 			&user.CreatedAt,
+			&user.UpdatedAt,
 			&user.Bio,
+			&user.AvatarKey,
 		)
 
 	switch {
@@ -115,7 +118,7 @@ func (r *Repository) GetExistingUserByUsername(ctx context.Context, username str
 	err := r.db.
 		QueryRow(
 			ctx,
-			`SELECT id, username, email, role, created_at, bio 
+			`SELECT id, username, email, role, created_at, updated_at, bio, avatar_key 
 			FROM users 
 			WHERE username = $1 
 			AND deleted_at IS NULL;`,
@@ -127,7 +130,9 @@ func (r *Repository) GetExistingUserByUsername(ctx context.Context, username str
 			&user.Email,
 			&user.Role,
 			&user.CreatedAt,
+			&user.UpdatedAt,
 			&user.Bio,
+			&user.AvatarKey,
 		)
 
 	switch {
@@ -204,3 +209,31 @@ func (r *Repository) DeleteUser(ctx context.Context, userID int) error {
 
 	return nil
 }
+
+// SetAvatar replaces the avatar key of the user, nil removes the avatar. It returns the previous key.
+func (r *Repository) SetAvatar(ctx context.Context, userID int, key *string) (*string, error) {
+	var previous *string
+	err := r.db.
+		QueryRow(
+			ctx,
+			`UPDATE users t
+			SET avatar_key = $2
+			FROM (SELECT id, avatar_key
+				FROM users
+				WHERE id = $1
+				AND deleted_at IS NULL
+				FOR UPDATE) previous
+			WHERE t.id = previous.id
+			RETURNING previous.avatar_key`,
+			userID, key,
+		).
+		Scan(&previous)
+	switch {
+	case dbx.IsNoRows(err):
+		return nil, apperrors.NotFound("user", "id", userID)
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+
+	return previous, nil
+}
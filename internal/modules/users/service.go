@@ -2,20 +2,34 @@ package users
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/boichique/movie-reviews/internal/images"
 	"github.com/boichique/movie-reviews/internal/log"
 )
 
 type Service struct {
-	repo *Repository
+	repo      *Repository
+	images    *images.Service
+	publicURL string
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, images *images.Service, publicURL string) *Service {
+	return &Service{
+		repo:      repo,
+		images:    images,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
 }
 
 func (s *Service) CreateUser(ctx context.Context, user *UserWithPassword) error {
-	return s.repo.CreateUser(ctx, user)
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		return err
+	}
+
+	s.setAvatar(user.User)
+	return nil
 }
 
 func (s *Service) GetExistingUserWithPasswordByEmail(ctx context.Context, email string) (*UserWithPassword, error) {
@@ -23,11 +37,23 @@ func (s *Service) GetExistingUserWithPasswordByEmail(ctx context.Context, email
 }
 
 func (s *Service) GetExistingUserByID(ctx context.Context, userID int) (*User, error) {
-	return s.repo.GetExistingUserByID(ctx, userID)
+	user, err := s.repo.GetExistingUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setAvatar(user)
+	return user, nil
 }
 
 func (s *Service) GetExistingUserByUsername(ctx context.Context, username string) (*User, error) {
-	return s.repo.GetExistingUserByUsername(ctx, username)
+	user, err := s.repo.GetExistingUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setAvatar(user)
+	return user, nil
 }
 
 func (s *Service) UpdateBio(ctx context.Context, userID int, bio string) error {
@@ -56,3 +82,68 @@ func (s *Service) DeleteUser(ctx context.Context, userID int) error {
 	log.FromContext(ctx).Info("user deleted", "userID", userID)
 	return nil
 }
+
+// SetAvatar stores the image in every avatar size and makes it the avatar of the user, replacing the previous one.
+func (s *Service) SetAvatar(ctx context.Context, userID int, data []byte) (*Avatar, error) {
+	key, err := s.images.StoreSquare(ctx, fmt.Sprintf("users/%d", userID), data, AvatarSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.SetAvatar(ctx, userID, &key)
+	if err != nil {
+		s.images.DeleteSquare(ctx, key, AvatarSizes)
+		return nil, err
+	}
+	if previous != nil {
+		s.images.DeleteSquare(ctx, *previous, AvatarSizes)
+	}
+
+	log.FromContext(ctx).Info("user avatar uploaded", "userID", userID, "key", key)
+	return s.avatar(userID, &key), nil
+}
+
+func (s *Service) DeleteAvatar(ctx context.Context, userID int) error {
+	previous, err := s.repo.SetAvatar(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		s.images.DeleteSquare(ctx, *previous, AvatarSizes)
+	}
+
+	log.FromContext(ctx).Info("user avatar deleted", "userID", userID)
+	return nil
+}
+
+func (s *Service) setAvatar(user *User) {
+	user.Avatar = s.avatar(int(user.ID), user.AvatarKey)
+}
+
+// avatar locates the stored avatar images, or the identicon of the user when there is no avatar.
+func (s *Service) avatar(userID int, key *string) *Avatar {
+	if key == nil {
+		return &Avatar{
+			Small:   s.identiconURL(userID, AvatarSmall),
+			Medium:  s.identiconURL(userID, AvatarMedium),
+			Large:   s.identiconURL(userID, AvatarLarge),
+			Default: true,
+		}
+	}
+
+	return &Avatar{
+		Small:  s.images.SquareURL(*key, AvatarSmall),
+		Medium: s.images.SquareURL(*key, AvatarMedium),
+		Large:  s.images.SquareURL(*key, AvatarLarge),
+	}
+}
+
+// identiconURL is absolute, as the URLs of stored avatars are.
+func (s *Service) identiconURL(userID, size int) string {
+	return fmt.Sprintf("%s/api/users/%d/identicon?size=%d", s.publicURL, userID, size)
+}
+
+// identiconSeed is the seed of the default avatar of the user.
+func identiconSeed(userID int) string {
+	return fmt.Sprintf("user:%d", userID)
+}
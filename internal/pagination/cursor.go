@@ -0,0 +1,55 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/boichique/movie-reviews/internal/apperrors"
+	"github.com/boichique/movie-reviews/internal/sorting"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// cursor is serialized into an opaque token. It remembers the sort it was issued for,
+// so that it cannot be applied to a list ordered differently.
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func encodeCursor(orders []sorting.Order, values []string) string {
+	data, _ := json.Marshal(cursor{Sort: sortKey(orders), Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string, orders []sorting.Order) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, apperrors.BadRequest(errInvalidCursor)
+	}
+
+	var c cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, apperrors.BadRequest(errInvalidCursor)
+	}
+
+	if c.Sort != sortKey(orders) || len(c.Values) != len(orders) {
+		return nil, apperrors.BadRequest(errors.New("cursor does not match the requested sort"))
+	}
+
+	return c.Values, nil
+}
+
+func sortKey(orders []sorting.Order) string {
+	parts := make([]string, 0, len(orders))
+	for _, order := range orders {
+		dir := sorting.Asc
+		if order.Desc {
+			dir = sorting.Desc
+		}
+		parts = append(parts, order.Field+":"+dir)
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,8 +1,14 @@
 package pagination
 
 import (
+	"errors"
+
+	"github.com/Masterminds/squirrel"
 	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/sorting"
+	"github.com/jackc/pgx/v5"
 )
 
 func SetDefaults(r *contracts.PaginatedRequest, cfg config.PaginationConfig) {
@@ -24,11 +30,193 @@ func OffsetLimit(r *contracts.PaginatedRequest) (int, int) {
 	return offset, limit
 }
 
-func Response[T any](r *contracts.PaginatedRequest, total int, items []*T) *contracts.PaginatedResponse[T] {
+// Page is the part of a sorted list requested by a client.
+type Page struct {
+	Orders    []sorting.Order
+	Offset    int
+	Limit     int
+	WithTotal bool
+
+	// cursor holds the sort values of the row the page starts after (or ends before, if backward)
+	cursor   []string
+	backward bool
+}
+
+// NewPage resolves the pagination request against the orders of the list. Orders must end with a unique tiebreaker.
+func NewPage(r *contracts.PaginatedRequest, orders []sorting.Order) (*Page, error) {
+	offset, limit := OffsetLimit(r)
+	p := &Page{
+		Orders: orders,
+		Offset: offset,
+		Limit:  limit,
+	}
+
+	if r.After != nil && r.Before != nil {
+		return nil, apperrors.BadRequest(errors.New("only one of after and before may be provided"))
+	}
+
+	var raw *string
+	switch {
+	case r.After != nil:
+		raw = r.After
+	case r.Before != nil:
+		raw = r.Before
+		p.backward = true
+	}
+
+	if raw != nil {
+		cursor, err := decodeCursor(*raw, orders)
+		if err != nil {
+			return nil, err
+		}
+		p.cursor = cursor
+		p.Offset = 0
+	}
+
+	p.WithTotal = raw == nil
+	if r.WithTotal != nil {
+		p.WithTotal = *r.WithTotal
+	}
+
+	return p, nil
+}
+
+// Apply adds the ordering, the keyset condition and the limits of the page to the query.
+// It also appends a text column per order to the selected columns, which Collect scans to build the cursors.
+func (p *Page) Apply(sb squirrel.SelectBuilder) squirrel.SelectBuilder {
+	orders := p.Orders
+	if p.backward {
+		orders = reverse(orders)
+	}
+
+	for _, order := range p.Orders {
+		sb = sb.Column("("+order.Expr+")::text", order.Args...)
+	}
+
+	if p.cursor != nil {
+		sb = sb.Where(keyset(orders, p.cursor))
+	}
+
+	return sorting.Apply(sb, orders).
+		Limit(uint64(p.Limit + 1)).
+		Offset(uint64(p.Offset))
+}
+
+// keyset builds the condition selecting the rows that come after the cursor in the given orders:
+// (a > $1) OR (a = $1 AND b > $2) OR ...
+func keyset(orders []sorting.Order, cursor []string) squirrel.Or {
+	var or squirrel.Or
+	for i, order := range orders {
+		var and squirrel.And
+		for j := 0; j < i; j++ {
+			and = append(and, squirrel.Expr("("+orders[j].Expr+") = ?", withValue(orders[j].Args, cursor[j])...))
+		}
+
+		op := ">"
+		if order.Desc {
+			op = "<"
+		}
+		and = append(and, squirrel.Expr("("+order.Expr+") "+op+" ?", withValue(order.Args, cursor[i])...))
+		or = append(or, and)
+	}
+
+	return or
+}
+
+func withValue(args []any, value string) []any {
+	return append(append([]any{}, args...), value)
+}
+
+func reverse(orders []sorting.Order) []sorting.Order {
+	reversed := make([]sorting.Order, len(orders))
+	for i, order := range orders {
+		order.Desc = !order.Desc
+		reversed[i] = order
+	}
+	return reversed
+}
+
+// Result is a page of items along with the cursors to its neighbours.
+type Result[T any] struct {
+	Items      []*T
+	Total      *int
+	NextCursor *string
+	PrevCursor *string
+}
+
+// Collect scans the rows of a query prepared with Apply. scan must return the destinations
+// of the item's own columns, the cursor columns are scanned by Collect.
+func Collect[T any](rows pgx.Rows, p *Page, scan func(item *T) []any) (*Result[T], error) {
+	var (
+		items  []*T
+		values [][]string
+	)
+	for rows.Next() {
+		item := new(T)
+		cursor := make([]string, len(p.Orders))
+
+		dest := scan(item)
+		for i := range cursor {
+			dest = append(dest, &cursor[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, apperrors.Internal(err)
+		}
+
+		items = append(items, item)
+		values = append(values, cursor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.Internal(err)
+	}
+
+	hasMore := len(items) > p.Limit
+	if hasMore {
+		items = items[:p.Limit]
+		values = values[:p.Limit]
+	}
+	if p.backward {
+		reverseSlice(items)
+		reverseSlice(values)
+	}
+
+	res := &Result[T]{Items: items}
+	if len(items) == 0 {
+		return res, nil
+	}
+
+	first, last := encodeCursor(p.Orders, values[0]), encodeCursor(p.Orders, values[len(values)-1])
+	if p.backward {
+		res.NextCursor = &last
+		if hasMore {
+			res.PrevCursor = &first
+		}
+	} else {
+		if hasMore {
+			res.NextCursor = &last
+		}
+		if p.cursor != nil || p.Offset > 0 {
+			res.PrevCursor = &first
+		}
+	}
+
+	return res, nil
+}
+
+func reverseSlice[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func Response[T any](r *contracts.PaginatedRequest, res *Result[T]) *contracts.PaginatedResponse[T] {
 	return &contracts.PaginatedResponse[T]{
-		Page:  r.Page,
-		Size:  r.Size,
-		Total: total,
-		Items: items,
+		Page:       r.Page,
+		Size:       r.Size,
+		Total:      res.Total,
+		NextCursor: res.NextCursor,
+		PrevCursor: res.PrevCursor,
+		Items:      res.Items,
 	}
 }
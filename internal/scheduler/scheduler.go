@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+const runTimeout = 10 * time.Minute
+
+// Every runs fn right away and then once per interval until the returned stop function is called.
+// A non-positive interval disables the job.
+func Every(interval time.Duration, name string, fn func(ctx context.Context) error) func() error {
+	if interval <= 0 {
+		return func() error { return nil }
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			run(ctx, name, fn)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() error {
+		cancel()
+		<-done
+		return nil
+	}
+}
+
+func run(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := fn(ctx); err != nil {
+		slog.Error(
+			"scheduled job failed",
+			"job", name,
+			"error", err,
+		)
+		return
+	}
+
+	slog.Debug(
+		"scheduled job finished",
+		"job", name,
+		"duration", time.Since(start),
+	)
+}
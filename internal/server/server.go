@@ -5,19 +5,28 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/boichique/movie-reviews/internal/apperrors"
 	"github.com/boichique/movie-reviews/internal/config"
 	"github.com/boichique/movie-reviews/internal/echox"
+	"github.com/boichique/movie-reviews/internal/images"
 	"github.com/boichique/movie-reviews/internal/jwt"
 	"github.com/boichique/movie-reviews/internal/log"
 	"github.com/boichique/movie-reviews/internal/modules/auth"
+	"github.com/boichique/movie-reviews/internal/modules/collections"
 	"github.com/boichique/movie-reviews/internal/modules/genres"
+	"github.com/boichique/movie-reviews/internal/modules/movieroles"
 	"github.com/boichique/movie-reviews/internal/modules/movies"
+	"github.com/boichique/movie-reviews/internal/modules/recommendations"
 	"github.com/boichique/movie-reviews/internal/modules/reviews"
+	"github.com/boichique/movie-reviews/internal/modules/search"
 	"github.com/boichique/movie-reviews/internal/modules/stars"
+	"github.com/boichique/movie-reviews/internal/modules/trash"
 	"github.com/boichique/movie-reviews/internal/modules/users"
+	"github.com/boichique/movie-reviews/internal/scheduler"
+	"github.com/boichique/movie-reviews/internal/storage"
 	"github.com/boichique/movie-reviews/internal/validation"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
@@ -59,22 +68,43 @@ func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	e := echo.New()
 	e.HTTPErrorHandler = echox.ErrorHandler
 	jwtService := jwt.NewService(cfg.Jwt.Secret, cfg.Jwt.AccessExpiration)
-	usersModule := users.NewModule(db)
+	storageConfig := cfg.Storage
+	if strings.HasPrefix(storageConfig.PublicURL, "/") {
+		storageConfig.PublicURL = strings.TrimSuffix(cfg.PublicURL, "/") + storageConfig.PublicURL
+	}
+	store, err := storage.New(storageConfig)
+	if err != nil {
+		return nil, withClosers(closers, fmt.Errorf("create storage: %w", err))
+	}
+	imagesService := images.NewService(store, cfg.Images)
+	usersModule := users.NewModule(db, imagesService, cfg.PublicURL)
 	authModule := auth.NewModule(usersModule.Service, jwtService)
 	authMiddleware := jwt.NewAuthMiddleware(cfg.Jwt.Secret)
 	genreModule := genres.NewModule(db)
-	starsModule := stars.NewModule(db, cfg.Pagination)
-	moviesModule := movies.NewModule(db, genreModule, starsModule, cfg.Pagination)
+	movieRolesModule := movieroles.NewModule(db)
+	starsModule := stars.NewModule(db, imagesService, cfg.Pagination)
+	collectionsModule := collections.NewModule(db, cfg.Pagination)
+	moviesModule := movies.NewModule(db, genreModule, movieRolesModule, starsModule, collectionsModule, imagesService, cfg.Pagination)
 	reviewsModule := reviews.NewModule(db, moviesModule, cfg.Pagination)
+	recommendationsModule := recommendations.NewModule(db, cfg.Recommendations)
+	searchModule := search.NewModule(db, cfg.Search, cfg.Pagination)
+	trashModule := trash.NewModule(db, moviesModule, imagesService, cfg.Trash, cfg.Pagination)
 
 	if err = createAdmin(cfg.Admin, authModule.Service); err != nil {
 		return nil, withClosers(closers, fmt.Errorf("create admin: %w", err))
 	}
 
+	closers = append(closers, scheduler.Every(cfg.Recommendations.RefreshInterval, "refresh recommendations", recommendationsModule.Service.Refresh))
+	closers = append(closers, scheduler.Every(cfg.Trash.PurgeInterval, "purge trash", trashModule.Service.Purge))
+
 	e.Use(middleware.Recover())
 	e.HideBanner = true
 	e.HidePort = true
 
+	if local, ok := store.(*storage.Local); ok {
+		e.Static(storage.LocalPath, local.Dir())
+	}
+
 	api := e.Group("/api")
 	api.Use(authMiddleware)
 	api.Use(echox.Logger)
@@ -89,6 +119,9 @@ func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	api.PUT("/users/:userID", usersModule.Handler.UpdateBio, auth.Self)
 	api.PUT("/users/:userID/role/:role", usersModule.Handler.UpdateRole, auth.Admin)
 	api.DELETE("/users/:userID", usersModule.Handler.Delete, auth.Self)
+	api.PUT("/users/:userID/avatar", usersModule.Handler.UploadAvatar, auth.Self)
+	api.DELETE("/users/:userID/avatar", usersModule.Handler.DeleteAvatar, auth.Self)
+	api.GET("/users/:userID/identicon", usersModule.Handler.GetIdenticon)
 
 	// genres group
 	api.POST("/genres", genreModule.Handler.Create, auth.Editor)
@@ -97,19 +130,53 @@ func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	api.PUT("/genres/:genreID", genreModule.Handler.UpdateName, auth.Editor)
 	api.DELETE("/genres/:genreID", genreModule.Handler.Delete, auth.Editor)
 
+	// movie roles group
+	api.POST("/movie-roles", movieRolesModule.Handler.Create, auth.Editor)
+	api.GET("/movie-roles", movieRolesModule.Handler.GetRoles)
+	api.GET("/movie-roles/:roleID", movieRolesModule.Handler.GetByID)
+	api.PUT("/movie-roles/:roleID", movieRolesModule.Handler.Update, auth.Editor)
+	api.DELETE("/movie-roles/:roleID", movieRolesModule.Handler.Delete, auth.Editor)
+
 	// stars group
 	api.POST("/stars", starsModule.Handler.Create, auth.Editor)
 	api.GET("/stars", starsModule.Handler.GetStarsPaginated)
 	api.GET("/stars/:starID", starsModule.Handler.GetByID)
+	api.GET("/stars/:starID/filmography", starsModule.Handler.GetFilmography)
+	api.GET("/stars/by-external/:source/:id", starsModule.Handler.GetByExternalID)
+	api.PUT("/stars/:starID/external-ids/:source", starsModule.Handler.PutExternalID, auth.Editor)
 	api.PUT("/stars/:starID", starsModule.Handler.Update, auth.Editor)
+	api.PATCH("/stars/:starID", starsModule.Handler.Patch, auth.Editor)
+	api.PUT("/stars/:starID/photo", starsModule.Handler.UploadPhoto, auth.Editor)
+	api.DELETE("/stars/:starID/photo", starsModule.Handler.DeletePhoto, auth.Editor)
 	api.DELETE("/stars/:starID", starsModule.Handler.Delete, auth.Editor)
+	api.POST("/stars/:starID/merge", starsModule.Handler.Merge, auth.Editor)
 
 	// movies group
 	api.POST("/movies", moviesModule.Handler.Create, auth.Editor)
 	api.GET("/movies", moviesModule.Handler.GetMoviesPaginated)
 	api.GET("/movies/:movieID", moviesModule.Handler.GetByID)
+	api.GET("/movies/by-external/:source/:id", moviesModule.Handler.GetByExternalID)
+	api.PUT("/movies/:movieID/external-ids/:source", moviesModule.Handler.PutExternalID, auth.Editor)
 	api.PUT("/movies/:movieID", moviesModule.Handler.Update, auth.Editor)
+	api.PATCH("/movies/:movieID", moviesModule.Handler.Patch, auth.Editor)
 	api.DELETE("/movies/:movieID", moviesModule.Handler.Delete, auth.Editor)
+	api.POST("/movies/:movieID/merge", moviesModule.Handler.Merge, auth.Editor)
+	api.PUT("/movies/:movieID/poster", moviesModule.Handler.UploadPoster, auth.Editor)
+	api.DELETE("/movies/:movieID/poster", moviesModule.Handler.DeletePoster, auth.Editor)
+	api.GET("/movies/:movieID/versions", moviesModule.Handler.GetVersions)
+	api.GET("/movies/:movieID/versions/:version", moviesModule.Handler.GetVersion)
+	api.POST("/movies/:movieID/versions/:version/rollback", moviesModule.Handler.Rollback, auth.Editor)
+	api.GET("/movies/:movieID/translations", moviesModule.Handler.GetTranslations)
+	api.PUT("/movies/:movieID/translations/:locale", moviesModule.Handler.PutTranslation, auth.Editor)
+	api.DELETE("/movies/:movieID/translations/:locale", moviesModule.Handler.DeleteTranslation, auth.Editor)
+
+	// collections group
+	api.POST("/collections", collectionsModule.Handler.Create, auth.Editor)
+	api.GET("/collections", collectionsModule.Handler.GetCollectionsPaginated)
+	api.GET("/collections/:collectionID", collectionsModule.Handler.GetByID)
+	api.PUT("/collections/:collectionID", collectionsModule.Handler.Update, auth.Editor)
+	api.PUT("/collections/:collectionID/movies", collectionsModule.Handler.SetMovies, auth.Editor)
+	api.DELETE("/collections/:collectionID", collectionsModule.Handler.Delete, auth.Editor)
 
 	// reviews group
 	api.POST("/users/:userID/reviews", reviewsModule.Handler.Create, auth.Self)
@@ -118,6 +185,17 @@ func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	api.PUT("/users/:userID/reviews/:reviewID", reviewsModule.Handler.Update, auth.Self)
 	api.DELETE("/users/:userID/reviews/:reviewID", reviewsModule.Handler.Delete, auth.Self)
 
+	// recommendations group
+	api.GET("/users/:userID/recommendations", recommendationsModule.Handler.GetByUserID, auth.Self)
+
+	// search group
+	api.GET("/search", searchModule.Handler.Search)
+	api.GET("/search/suggest", searchModule.Handler.Suggest)
+
+	// trash group
+	api.GET("/trash/:entity", trashModule.Handler.GetDeletedPaginated, auth.Admin)
+	api.POST("/trash/:entity/:id/restore", trashModule.Handler.Restore, auth.Admin)
+
 	return &Server{
 		e:       e,
 		cfg:     cfg,
@@ -23,3 +23,24 @@ func NoChangeFunc[S any]() func(S) S {
 		return item
 	}
 }
+
+func Contains[S comparable](slice []S, item S) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Filter[S any](slice []S, fn func(S) bool) []S {
+	result := make([]S, 0, len(slice))
+	for _, item := range slice {
+		if fn(item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
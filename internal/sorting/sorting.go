@@ -0,0 +1,85 @@
+package sorting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/boichique/movie-reviews/internal/apperrors"
+)
+
+const (
+	Asc  = "asc"
+	Desc = "desc"
+)
+
+// Fields whitelists the sort fields accepted from clients, mapping each of them to the SQL expression to order by.
+// Expressions must never evaluate to NULL, so that the ordering stays total.
+type Fields map[string]string
+
+type Order struct {
+	Field string
+	Expr  string
+	Args  []any
+	Desc  bool
+}
+
+// Parse parses a sort parameter in the form "field:dir,field:dir". The direction is optional and defaults to asc.
+func Parse(raw *string, fields Fields) ([]Order, error) {
+	if raw == nil || *raw == "" {
+		return nil, nil
+	}
+
+	var orders []Order
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(*raw, ",") {
+		field, dir, _ := strings.Cut(strings.TrimSpace(part), ":")
+
+		expr, ok := fields[field]
+		if !ok {
+			return nil, apperrors.BadRequest(fmt.Errorf("unknown sort field %q", field))
+		}
+
+		if seen[field] {
+			return nil, apperrors.BadRequest(fmt.Errorf("duplicate sort field %q", field))
+		}
+		seen[field] = true
+
+		order := Order{Field: field, Expr: expr}
+		switch strings.ToLower(dir) {
+		case "", Asc:
+		case Desc:
+			order.Desc = true
+		default:
+			return nil, apperrors.BadRequest(fmt.Errorf("sort direction of %q must be one of asc or desc", field))
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// WithTiebreaker appends the unique tiebreaker to orders unless it is already there,
+// which makes the ordering deterministic between pages.
+func WithTiebreaker(orders []Order, tiebreaker Order) []Order {
+	for _, order := range orders {
+		if order.Field == tiebreaker.Field {
+			return orders
+		}
+	}
+
+	return append(orders, tiebreaker)
+}
+
+func Apply(sb squirrel.SelectBuilder, orders []Order) squirrel.SelectBuilder {
+	for _, order := range orders {
+		dir := "ASC"
+		if order.Desc {
+			dir = "DESC"
+		}
+		sb = sb.OrderByClause(order.Expr+" "+dir, order.Args...)
+	}
+
+	return sb
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local keeps the objects in a directory of the filesystem, the server exposes it at LocalPath.
+type Local struct {
+	dir       string
+	publicURL string
+}
+
+// LocalPath is where the server serves the objects of the local storage.
+const LocalPath = "/images"
+
+func NewLocal(dir, publicURL string) *Local {
+	return &Local{
+		dir:       dir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+func (s *Local) Dir() string {
+	return s.dir
+}
+
+func (s *Local) Put(_ context.Context, key, _ string, data []byte) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *Local) Delete(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *Local) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+func (s *Local) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(filepath.Clean("/"+key)))
+}
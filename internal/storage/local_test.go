@@ -0,0 +1,64 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := storage.NewLocal(dir, "http://localhost:8080/images/")
+
+	t.Run("storage.Local: put", func(t *testing.T) {
+		err := s.Put(ctx, "movies/1/poster.png", "image/png", []byte("poster"))
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "movies", "1", "poster.png"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("poster"), data)
+		require.Equal(t, "http://localhost:8080/images/movies/1/poster.png", s.URL("movies/1/poster.png"))
+
+		// the object is replaced
+		err = s.Put(ctx, "movies/1/poster.png", "image/png", []byte("new poster"))
+		require.NoError(t, err)
+
+		data, err = os.ReadFile(filepath.Join(dir, "movies", "1", "poster.png"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("new poster"), data)
+	})
+
+	t.Run("storage.Local: keys stay in the directory", func(t *testing.T) {
+		err := s.Put(ctx, "../../escaped.png", "image/png", []byte("escaped"))
+		require.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(dir, "escaped.png"))
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(filepath.Dir(dir), "escaped.png"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("storage.Local: delete", func(t *testing.T) {
+		err := s.Delete(ctx, "movies/1/poster.png")
+		require.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(dir, "movies", "1", "poster.png"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		// deleting a missing object is not an error
+		err = s.Delete(ctx, "movies/1/poster.png")
+		require.NoError(t, err)
+	})
+
+	t.Run("storage.New: local driver", func(t *testing.T) {
+		store, err := storage.New(config.StorageConfig{Driver: storage.LocalDriver, LocalDir: dir})
+		require.NoError(t, err)
+		require.IsType(t, &storage.Local{}, store)
+	})
+}
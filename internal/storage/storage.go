@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boichique/movie-reviews/internal/config"
+)
+
+// Storage keeps binary objects, e.g. images, under keys like movies/1/poster.jpg
+// and serves them at public URLs.
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete removes the object, deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+const (
+	LocalDriver = "local"
+	S3Driver    = "s3"
+)
+
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case LocalDriver:
+		return NewLocal(cfg.LocalDir, cfg.PublicURL), nil
+	case S3Driver:
+		return NewS3(cfg.S3), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
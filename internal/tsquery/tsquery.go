@@ -0,0 +1,64 @@
+package tsquery
+
+import (
+	"regexp"
+	"strings"
+)
+
+var wordRegexp = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// Prefix turns a plain-text search term into a to_tsquery expression matching every word of the term as a prefix,
+// e.g. "star wa" becomes "star:* & wa:*". Operators and punctuation are dropped, so the result is always valid.
+func Prefix(term string) string {
+	words := wordRegexp.FindAllString(strings.ToLower(term), -1)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
+
+// Match is a tsquery matching a plain-text search term as typed, along with every word of it as a prefix.
+// It takes the arguments returned by Args.
+const Match = "(websearch_to_tsquery('english', ?) || to_tsquery('english', ?))"
+
+func Args(term string) []any {
+	return []any{term, Prefix(term)}
+}
+
+// MatchIn is Match stemming the term with the configuration of a language, see the search_config SQL function.
+// It takes the arguments returned by ArgsIn.
+const MatchIn = "(websearch_to_tsquery(search_config(?), ?) || to_tsquery(search_config(?), ?))"
+
+func ArgsIn(lang, term string) []any {
+	return []any{lang, term, lang, Prefix(term)}
+}
+
+// MatchInColumn is MatchIn stemming the term with the language stored in the column of every matched row,
+// so that the term is stemmed the same way as the search vector of the row. It takes the arguments returned by Args.
+// The query depends on the row, so it can't be served by an index and is meant for ranking the matched rows,
+// see MatchesInColumn for matching them.
+func MatchInColumn(column string) string {
+	return "(websearch_to_tsquery(search_config(" + column + "), ?) || to_tsquery(search_config(" + column + "), ?))"
+}
+
+// configs are the text search configurations returned by the search_config SQL function.
+var configs = []string{
+	"arabic", "danish", "dutch", "english", "finnish", "french", "german", "hungarian", "italian",
+	"norwegian", "portuguese", "romanian", "russian", "spanish", "swedish", "turkish", "simple",
+}
+
+// MatchesInColumn is the predicate matching the term against the search vector of the rows stemmed in the language
+// stored in the column, along with its arguments. It has a branch with a constant query for every configuration,
+// so that the index of the search vector serves each of them.
+func MatchesInColumn(vector, column, term string) (string, []any) {
+	branches := make([]string, len(configs))
+	args := make([]any, 0, 2*len(configs))
+	for i, config := range configs {
+		branches[i] = "(search_config(" + column + ") = '" + config + "'::regconfig AND " + vector + " @@ " +
+			"(websearch_to_tsquery('" + config + "', ?) || to_tsquery('" + config + "', ?)))"
+		args = append(args, Args(term)...)
+	}
+
+	return "(" + strings.Join(branches, " OR ") + ")", args
+}
@@ -5,7 +5,10 @@ import (
 	"net/mail"
 	"strings"
 
+	"github.com/boichique/movie-reviews/internal/modules/movieroles"
+	"github.com/boichique/movie-reviews/internal/modules/stars"
 	"github.com/boichique/movie-reviews/internal/modules/users"
+	"golang.org/x/text/language"
 	"gopkg.in/validator.v2"
 )
 
@@ -18,6 +21,12 @@ func SetupValidators() {
 		{"email", email},
 		{"role", role},
 		{"sort", sort},
+		{"match", match},
+		{"lang", lang},
+		{"locale", locale},
+		{"country", country},
+		{"aliasKind", aliasKind},
+		{"roleCategory", roleCategory},
 	}
 
 	for _, v := range validators {
@@ -84,6 +93,41 @@ func role(v interface{}, _ string) error {
 	return nil
 }
 
+func aliasKind(v interface{}, _ string) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("aliasKind only validates string")
+	}
+
+	switch s {
+	case stars.BirthAlias, stars.StageAlias, stars.NicknameAlias, stars.OtherAlias:
+		return nil
+	}
+	return fmt.Errorf("alias kind should be birth/stage/nickname/other")
+}
+
+func roleCategory(v interface{}, _ string) error {
+	validate := func(s *string) error {
+		if s == nil {
+			return nil
+		}
+		switch *s {
+		case movieroles.CastCategory, movieroles.CrewCategory:
+			return nil
+		}
+		return fmt.Errorf("role category should be cast/crew")
+	}
+
+	switch s := v.(type) {
+	case string:
+		return validate(&s)
+	case *string:
+		return validate(s)
+	default:
+		return fmt.Errorf("roleCategory only validates strings or pointers to strings")
+	}
+}
+
 func sort(v interface{}, _ string) error {
 	validate := func(s *string) error {
 		if s == nil {
@@ -106,3 +150,92 @@ func sort(v interface{}, _ string) error {
 
 	}
 }
+
+func match(v interface{}, _ string) error {
+	validate := func(s *string) error {
+		if s == nil {
+			return nil
+		}
+		switch *s {
+		case "any", "all":
+			return nil
+		}
+		return fmt.Errorf("match must be one of any or all")
+	}
+
+	switch s := v.(type) {
+	case string:
+		return validate(&s)
+	case *string:
+		return validate(s)
+	default:
+		return fmt.Errorf("match only validates strings or pointers to strings")
+	}
+}
+
+// lang validates optional ISO 639-1 language codes.
+func lang(v interface{}, _ string) error {
+	return validateCodes(v, "lang", func(s string) error {
+		if !isCode(s, "abcdefghijklmnopqrstuvwxyz") {
+			return fmt.Errorf("lang must be a two-letter lowercase ISO 639-1 code")
+		}
+		return nil
+	})
+}
+
+// country validates optional ISO 3166-1 alpha-2 country codes.
+func country(v interface{}, _ string) error {
+	return validateCodes(v, "country", func(s string) error {
+		if !isCode(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			return fmt.Errorf("country must be a two-letter uppercase ISO 3166-1 code")
+		}
+		return nil
+	})
+}
+
+// validateCodes applies validate to a string, a string pointer or every string of a slice. Empty values are skipped.
+func validateCodes(v interface{}, name string, validate func(string) error) error {
+	switch s := v.(type) {
+	case string:
+		if s == "" {
+			return nil
+		}
+		return validate(s)
+	case *string:
+		if s == nil {
+			return nil
+		}
+		return validate(*s)
+	case []string:
+		for _, code := range s {
+			if err := validate(code); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s only validates strings, pointers to strings or slices of strings", name)
+	}
+}
+
+func isCode(s, alphabet string) bool {
+	return len(s) == 2 && strings.Trim(s, alphabet) == ""
+}
+
+// locale validates an optional BCP 47 locale, e.g. "pt-BR".
+func locale(v interface{}, _ string) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("locale only validates string")
+	}
+
+	if s == "" {
+		return nil
+	}
+
+	if _, err := language.Parse(s); err != nil || len(s) > 16 {
+		return fmt.Errorf("locale must be a BCP 47 language tag such as en or pt-BR")
+	}
+
+	return nil
+}
@@ -98,6 +98,10 @@ func runIngest(opts *IngestOptions, logger *slog.Logger) error {
 	if err = genreIngester.Ingest(genres); err != nil {
 		return fmt.Errorf("failed to ingest genres: %w", err)
 	}
+	roleIngester := ingesters.NewRoleIngester(cl, token, logger)
+	if err = roleIngester.Ingest(cast); err != nil {
+		return fmt.Errorf("failed to ingest roles: %w", err)
+	}
 	starIngester := ingesters.NewStarIngester(cl, token, logger)
 	if err = starIngester.Ingest(stars, bios); err != nil {
 		return fmt.Errorf("failed to ingest stars: %w", err)
@@ -48,6 +48,20 @@ func NewBioCollector(c *colly.Collector, logger *slog.Logger) *BioCollector {
 					bio.BirthPlace = strings.TrimSpace(selection.Text())
 				}
 			})
+
+			overviewSection.Find("li.ipc-metadata-list__item").Each(func(i int, item *goquery.Selection) {
+				values := item.Find(".ipc-html-content-inner-div")
+				switch strings.TrimSpace(item.Find(".ipc-metadata-list-item__label").First().Text()) {
+				case "Birth name":
+					bio.BirthName = strings.TrimSpace(values.First().Text())
+				case "Nickname", "Nicknames":
+					values.Each(func(i int, value *goquery.Selection) {
+						if nickname := strings.TrimSpace(value.Text()); nickname != "" {
+							bio.Nicknames = append(bio.Nicknames, nickname)
+						}
+					})
+				}
+			})
 		}
 
 		bioSection := getSectionByTitle(e, "mini_bio")
@@ -12,6 +12,28 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+// Categories of the roles, as the server catalogs them
+const (
+	castCategory = "cast"
+	crewCategory = "crew"
+)
+
+// crewDepartments map the sections of the full credits page to the crew roles the stars in them are credited with
+var crewDepartments = []struct {
+	section string
+	role    string
+}{
+	{"writer", "writer"},
+	{"producer", "producer"},
+	{"composer", "composer"},
+	{"cinematographer", "cinematographer"},
+	{"editor", "editor"},
+	{"casting_director", "casting director"},
+	{"production_designer", "production designer"},
+	{"art_director", "art director"},
+	{"costume_designer", "costume designer"},
+}
+
 type CastCollector struct {
 	c *colly.Collector
 	l *slog.Logger
@@ -29,7 +51,7 @@ func NewCastCollector(c *colly.Collector, starCollector *StarCollector, logger *
 		starLinks: make(map[string]bool),
 	}
 
-	// note about movie roles: 'actor', 'voice actor', 'writer', 'producer', 'director', 'composer'
+	// the cast goes right after the directors, the rest of the crew follows in the order of the departments
 	c.OnHTML("html", func(e *colly.HTMLElement) {
 		movieID := getMovieID(e.Request.URL)
 
@@ -45,19 +67,11 @@ func NewCastCollector(c *colly.Collector, starCollector *StarCollector, logger *
 			collector.addCastFromCastTable(cast, castHeader.Next(), 15)
 		}
 
-		writerHeader := e.DOM.Find("h4#writer")
-		if writerHeader.Nodes != nil {
-			collector.addCastFromSimpleTable(cast, "writer", writerHeader.Next())
-		}
-
-		producerHeader := e.DOM.Find("h4#producer")
-		if producerHeader.Nodes != nil {
-			collector.addCastFromSimpleTable(cast, "producer", producerHeader.Next())
-		}
-
-		composerHeader := e.DOM.Find("h4#composer")
-		if composerHeader.Nodes != nil {
-			collector.addCastFromSimpleTable(cast, "composer", composerHeader.Next())
+		for _, department := range crewDepartments {
+			header := e.DOM.Find("h4#" + department.section)
+			if header.Nodes != nil {
+				collector.addCastFromSimpleTable(cast, department.role, header.Next())
+			}
 		}
 
 		collector.l.
@@ -124,6 +138,7 @@ func (c *CastCollector) addCastFromSimpleTable(cast *models.Cast, role string, t
 
 		credit := &models.Credit{
 			Role:     role,
+			Category: crewCategory,
 			Details:  strings.TrimSpace(details),
 			StarName: strings.TrimSpace(starLink.Text()),
 			StarLink: link,
@@ -163,6 +178,7 @@ func (c *CastCollector) addCastFromCastTable(cast *models.Cast, table *goquery.S
 
 		credit := &models.Credit{
 			Role:     role,
+			Category: castCategory,
 			Details:  details,
 			StarID:   getStarID(link),
 			StarName: strings.TrimSpace(name),
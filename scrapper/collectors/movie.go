@@ -42,6 +42,8 @@ func NewMovieCollector(c *colly.Collector, castCollector *CastCollector, logger
 			Description   string   `json:"description"`
 			Genre         []string `json:"genre"`
 			DatePublished string   `json:"datePublished"`
+			Duration      string   `json:"duration"`
+			ContentRating string   `json:"contentRating"`
 		}
 
 		var info movieInfo
@@ -58,6 +60,12 @@ func NewMovieCollector(c *colly.Collector, castCollector *CastCollector, logger
 		movie.Description = info.Description
 		movie.Genres = info.Genre
 		movie.ReleaseDate = mustParseDate(info.DatePublished)
+		movie.RuntimeMinutes = parseRuntime(info.Duration)
+		movie.AgeRating = info.ContentRating
+		movie.ImageURL = info.Image
+		movie.Countries = linkParams(e, "li[data-testid='title-details-origin'] a", "country_of_origin", strings.ToUpper)
+		movie.SpokenLanguages = linkParams(e, "li[data-testid='title-details-languages'] a", "primary_language", strings.ToLower)
+		movie.Tagline = e.ChildText("li[data-testid='storyline-taglines'] .ipc-metadata-list-item__list-content-item")
 
 		collector.toAllGenres(movie.Genres)
 
@@ -126,3 +134,35 @@ func mustParseDate(date string) time.Time {
 
 	return t
 }
+
+// parseRuntime converts an ISO 8601 duration like PT2H22M to minutes, 0 if it can't be parsed.
+func parseRuntime(duration string) int {
+	d, err := time.ParseDuration(strings.ToLower(strings.TrimPrefix(duration, "PT")))
+	if err != nil {
+		return 0
+	}
+
+	return int(d.Minutes())
+}
+
+// linkParams collects the distinct two-letter codes passed in the param of the links' query.
+func linkParams(e *colly.HTMLElement, selector, param string, normalize func(string) string) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	e.ForEach(selector, func(_ int, a *colly.HTMLElement) {
+		u, err := url.Parse(a.Attr("href"))
+		if err != nil {
+			return
+		}
+
+		code := normalize(u.Query().Get(param))
+		if len(code) != 2 || seen[code] {
+			return
+		}
+
+		seen[code] = true
+		codes = append(codes, code)
+	})
+
+	return codes
+}
@@ -56,7 +56,7 @@ func NewStarCollector(c *colly.Collector, bioCollector *BioCollector, logger *sl
 		}
 
 		star.Name = info.Name
-		star.FirstName, star.LastName = splitName(info.Name)
+		star.FirstName, star.MiddleName, star.LastName = splitName(info.Name)
 		star.BirthDate = mustParseDate(info.MainEntity.BirthDate)
 		if info.MainEntity.DeathDate != "" {
 			deathDate := mustParseDate(info.MainEntity.DeathDate)
@@ -97,20 +97,39 @@ func (c *StarCollector) getOrCreateStar(starID, link string) *models.Star {
 	return star
 }
 
-func splitName(name string) (string, string) {
-	names := strings.Split(name, " ")
+var (
+	// surnameParticles start compound surnames along with the words following them, e.g. "van" in "Ludwig van Beethoven"
+	surnameParticles = map[string]bool{
+		"al": true, "bin": true, "da": true, "de": true, "del": true, "della": true, "der": true, "di": true,
+		"do": true, "dos": true, "du": true, "ibn": true, "la": true, "le": true, "st.": true, "ten": true,
+		"ter": true, "van": true, "von": true,
+	}
+	// nameSuffixes belong to the surname, e.g. "Jr." in "Robert Downey Jr."
+	nameSuffixes = map[string]bool{"jr.": true, "sr.": true, "jr": true, "sr": true, "ii": true, "iii": true, "iv": true}
+)
+
+// splitName splits the full name into the first, middle and last names. A mononym is kept as the first name,
+// a surname takes the particles preceding it and the suffixes following it, e.g. "Dick Van Dyke" is Dick and Van Dyke.
+// Compound surnames without particles, e.g. "Helena Bonham Carter", can't be told from middle names,
+// the display name keeps the full name anyway.
+func splitName(name string) (first, middle, last string) {
+	names := strings.Fields(name)
 	switch len(names) {
+	case 0:
+		return "", "", ""
 	case 1:
-		return names[0], ""
-	case 2:
-		return names[0], names[1]
-	case 3:
-		if names[2] == "Jr." || names[2] == "Sr." {
-			return names[0], strings.Join(names[1:], " ")
-		}
+		return names[0], "", ""
+	}
+
+	end := len(names)
+	for end > 2 && nameSuffixes[strings.ToLower(names[end-1])] {
+		end--
+	}
 
-		return names[0], names[2]
-	default:
-		return names[0], strings.Join(names[2:], " ")
+	start := end - 1
+	for start > 1 && surnameParticles[strings.ToLower(names[start-1])] {
+		start--
 	}
+
+	return names[0], strings.Join(names[1:start], " "), strings.Join(names[start:], " ")
 }
@@ -0,0 +1,34 @@
+package collectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name                string
+		first, middle, last string
+	}{
+		{name: ""},
+		{name: "Zendaya", first: "Zendaya"},
+		{name: "  Tom   Hanks ", first: "Tom", last: "Hanks"},
+		{name: "Samuel L. Jackson", first: "Samuel", middle: "L.", last: "Jackson"},
+		{name: "Helena Bonham Carter", first: "Helena", middle: "Bonham", last: "Carter"},
+		{name: "Dick Van Dyke", first: "Dick", last: "Van Dyke"},
+		{name: "Jean-Claude Van Damme", first: "Jean-Claude", last: "Van Damme"},
+		{name: "Oscar de la Hoya", first: "Oscar", last: "de la Hoya"},
+		{name: "Robert Downey Jr.", first: "Robert", last: "Downey Jr."},
+		{name: "Martin Luther King Jr.", first: "Martin", middle: "Luther", last: "King Jr."},
+		{name: "Louis Gossett Jr", first: "Louis", last: "Gossett Jr"},
+		{name: "John D. Rockefeller III", first: "John", middle: "D.", last: "Rockefeller III"},
+	}
+
+	for _, tt := range tests {
+		first, middle, last := splitName(tt.name)
+		require.Equal(t, tt.first, first, tt.name)
+		require.Equal(t, tt.middle, middle, tt.name)
+		require.Equal(t, tt.last, last, tt.name)
+	}
+}
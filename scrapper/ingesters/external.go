@@ -0,0 +1,31 @@
+package ingesters
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+)
+
+// imdbSource is the source of the external IDs of scrapped movies and stars
+const imdbSource = "imdb"
+
+func imdbID(id string) []*contracts.ExternalID {
+	return []*contracts.ExternalID{{Source: imdbSource, ID: id}}
+}
+
+func hasIMDbID(ids []*contracts.ExternalID) bool {
+	for _, id := range ids {
+		if id.Source == imdbSource {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNotFound(err error) bool {
+	var cerr *client.Error
+	return errors.As(err, &cerr) && cerr.Code == http.StatusNotFound
+}
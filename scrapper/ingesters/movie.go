@@ -3,20 +3,27 @@ package ingesters
 import (
 	"context"
 	"fmt"
-	"sync"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/boichique/movie-reviews/client"
 	"github.com/boichique/movie-reviews/contracts"
-	"github.com/boichique/movie-reviews/internal/maps"
-	"github.com/boichique/movie-reviews/internal/slices"
 	"github.com/boichique/movie-reviews/scrapper/models"
 	"golang.org/x/exp/slog"
 	"golang.org/x/sync/errgroup"
 )
 
+const (
+	posterDownloadTimeout = 30 * time.Second
+	// posterMaxSize bounds the download of a poster, the server rejects larger images anyway
+	posterMaxSize = 10 << 20
+)
+
 type MovieIngester struct {
 	c                *client.Client
+	httpClient       *http.Client
 	token            string
 	genreIDConverter func(string) (int, bool)
 	starIDConverter  func(string) (int, bool)
@@ -26,6 +33,7 @@ type MovieIngester struct {
 func NewMovieIngester(c *client.Client, token string, genreIDConverter func(string) (int, bool), starIDConverter func(string) (int, bool), logger *slog.Logger) *MovieIngester {
 	return &MovieIngester{
 		c:                c,
+		httpClient:       &http.Client{Timeout: posterDownloadTimeout},
 		token:            token,
 		genreIDConverter: genreIDConverter,
 		starIDConverter:  starIDConverter,
@@ -33,110 +41,199 @@ func NewMovieIngester(c *client.Client, token string, genreIDConverter func(stri
 	}
 }
 
+// Ingest creates the movies missing on the server. Movies are matched by their IMDb IDs, so re-ingestion is exact.
+// A movie without the IMDb ID, e.g. created by hand, is matched by its title and release date and gets the IMDb ID.
 func (i *MovieIngester) Ingest(movies map[string]*models.Movie, casts map[string]*models.Cast) error {
-	existingMovies, err := client.Paginate(&contracts.GetMoviesPaginatedRequest{}, i.c.GetMovies)
-	if err != nil {
-		return err
-	}
-
-	type movieCommonIdentifier struct {
-		Title       string
-		ReleaseDate time.Time
-	}
-
-	getID := func(m *contracts.Movie) movieCommonIdentifier {
-		return movieCommonIdentifier{
-			Title:       m.Title,
-			ReleaseDate: m.ReleaseDate,
-		}
-	}
-
-	idToMovieMap := slices.ToMap(existingMovies, getID, slices.NoChangeFunc[*contracts.Movie]())
-	var mx sync.RWMutex
-
 	group, _ := errgroup.WithContext(context.Background())
 	group.SetLimit(8)
 
 	for _, movie := range movies {
 		movie := movie
-		commonID := movieCommonIdentifier{movie.Title, movie.ReleaseDate}
-
-		if maps.ExistsLocked(idToMovieMap, commonID, &mx) {
-			continue
-		}
 
 		group.Go(func() error {
-			var created bool
-			_, created, err = maps.GetOrCreateLocked(idToMovieMap, commonID, &mx, func(name movieCommonIdentifier) (*contracts.Movie, error) {
-				req := &contracts.CreateMovieRequest{
-					Title:       movie.Title,
-					ReleaseDate: movie.ReleaseDate,
-					Description: movie.Description,
-				}
-
-				// Prepare genres
-				for _, genre := range movie.Genres {
-					genreID, ok := i.genreIDConverter(genre)
-					if !ok {
-						i.logger.With("genre", genre).Error("Cannot convert genre")
-						continue
-					}
-
-					req.GenresID = append(req.GenresID, genreID)
-				}
-
-				// Prepare cast
-				cast, ok := casts[movie.ID]
-				if !ok {
-					i.logger.With("movie_id", movie.ID).Error("Cast not found")
-					cast = &models.Cast{}
-				}
+			_, err := i.c.GetMovieByExternalID(imdbSource, movie.ID)
+			switch {
+			case err == nil:
+				return nil
+			case !isNotFound(err):
+				return fmt.Errorf("get movie %q: %w", movie.ID, err)
+			}
 
-				for _, credit := range cast.Cast {
-					starID, ok := i.starIDConverter(credit.StarID)
-					if !ok {
-						i.logger.With("star_id", credit.StarID).Warn("Cannot convert star id")
-						continue
-					}
-
-					creditInfo := &contracts.MovieCreditInfo{
-						StarID: starID,
-						Role:   credit.Role,
-					}
-					if credit.Details != "" {
-						creditInfo.Details = &credit.Details
-					}
-
-					req.Cast = append(req.Cast, creditInfo)
+			existing, err := i.findByTitle(movie)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				if err = i.attachIMDbID(existing.ID, movie.ID); err != nil {
+					return err
 				}
 
-				var md *contracts.MovieDetails
-				md, err = i.c.CreateMovie(contracts.NewAuthenticated(req, i.token))
-				if err != nil {
-					return nil, fmt.Errorf("create movie: %w", err)
-				}
+				i.logger.
+					With("movie_id", movie.ID).
+					With("id", existing.ID).
+					Debug("Matched movie by title")
+				return nil
+			}
 
-				return &md.Movie, nil
-			})
+			md, err := i.create(movie, casts[movie.ID])
 			if err != nil {
 				return err
 			}
 
-			if created {
-				i.logger.
-					With("movie_id", movie.ID).
-					With("movie_common_id", commonID).
-					Debug("Created movie")
+			i.logger.
+				With("movie_id", movie.ID).
+				With("id", md.ID).
+				Debug("Created movie")
+
+			if movie.ImageURL != "" {
+				if err = i.uploadPoster(md.ID, movie.ImageURL); err != nil {
+					i.logger.With("movie_id", movie.ID).With("err", err).Warn("Cannot upload poster")
+				}
 			}
 
 			return nil
 		})
 	}
 
-	if err = group.Wait(); err != nil {
+	if err := group.Wait(); err != nil {
 		return fmt.Errorf("ingest movies: %w", err)
 	}
 
 	i.logger.Info("Successfully ingested movies")
 	return nil
 }
+
+func (i *MovieIngester) create(movie *models.Movie, cast *models.Cast) (*contracts.MovieDetails, error) {
+	req := &contracts.CreateMovieRequest{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Description: movie.Description,
+		ExternalIDs: imdbID(movie.ID),
+		MovieMetadata: contracts.MovieMetadata{
+			Countries:       movie.Countries,
+			SpokenLanguages: movie.SpokenLanguages,
+		},
+	}
+	if movie.RuntimeMinutes > 0 {
+		req.RuntimeMinutes = &movie.RuntimeMinutes
+	}
+	if movie.AgeRating != "" {
+		req.AgeRating = &movie.AgeRating
+	}
+	if movie.Tagline != "" {
+		req.Tagline = &movie.Tagline
+	}
+
+	// Prepare genres
+	for _, genre := range movie.Genres {
+		genreID, ok := i.genreIDConverter(genre)
+		if !ok {
+			i.logger.With("genre", genre).Error("Cannot convert genre")
+			continue
+		}
+
+		req.GenresID = append(req.GenresID, genreID)
+	}
+
+	// Prepare cast
+	if cast == nil {
+		i.logger.With("movie_id", movie.ID).Error("Cast not found")
+		cast = &models.Cast{}
+	}
+
+	for _, credit := range cast.Cast {
+		starID, ok := i.starIDConverter(credit.StarID)
+		if !ok {
+			i.logger.With("star_id", credit.StarID).Warn("Cannot convert star id")
+			continue
+		}
+
+		creditInfo := &contracts.MovieCreditInfo{
+			StarID: starID,
+			Role:   credit.Role,
+		}
+		if credit.Details != "" {
+			creditInfo.Details = &credit.Details
+		}
+
+		req.Cast = append(req.Cast, creditInfo)
+	}
+
+	md, err := i.c.CreateMovie(contracts.NewAuthenticated(req, i.token))
+	if err != nil {
+		return nil, fmt.Errorf("create movie %q: %w", movie.ID, err)
+	}
+
+	return md, nil
+}
+
+// findByTitle returns the movie on the server with the title and the release date of the scrapped movie, nil if there
+// is none. Movies with another IMDb ID are different movies of the same title.
+func (i *MovieIngester) findByTitle(movie *models.Movie) (*contracts.MovieDetails, error) {
+	title := movie.Title
+	res, err := i.c.GetMovies(&contracts.GetMoviesPaginatedRequest{
+		SearchTerm:   &title,
+		ReleasedFrom: &movie.ReleaseDate,
+		ReleasedTo:   &movie.ReleaseDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find movie %q by title: %w", movie.ID, err)
+	}
+
+	for _, candidate := range res.Items {
+		if !strings.EqualFold(candidate.Title, movie.Title) {
+			continue
+		}
+
+		details, err := i.c.GetMovie(candidate.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get movie %d: %w", candidate.ID, err)
+		}
+		if !hasIMDbID(details.ExternalIDs) {
+			return details, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// attachIMDbID gives the IMDb ID to the movie matched by title, so that the next ingestion matches it exactly.
+func (i *MovieIngester) attachIMDbID(movieID int, imdbID string) error {
+	req := &contracts.PutMovieExternalIDRequest{
+		MovieID:              movieID,
+		PutExternalIDRequest: contracts.PutExternalIDRequest{Source: imdbSource, ExternalID: imdbID},
+	}
+	if _, err := i.c.PutMovieExternalID(contracts.NewAuthenticated(req, i.token)); err != nil {
+		return fmt.Errorf("attach imdb id %q to movie %d: %w", imdbID, movieID, err)
+	}
+
+	return nil
+}
+
+// uploadPoster downloads the poster of the movie and uploads it to the server.
+func (i *MovieIngester) uploadPoster(movieID int, imageURL string) error {
+	res, err := i.httpClient.Get(imageURL)
+	if err != nil {
+		return fmt.Errorf("download poster: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("download poster: unexpected status %d", res.StatusCode)
+	}
+
+	image, err := io.ReadAll(io.LimitReader(res.Body, posterMaxSize+1))
+	if err != nil {
+		return fmt.Errorf("download poster: %w", err)
+	}
+	if len(image) > posterMaxSize {
+		return fmt.Errorf("download poster: larger than %d bytes", posterMaxSize)
+	}
+
+	req := &contracts.UploadMoviePosterRequest{MovieID: movieID}
+	if _, err = i.c.UploadMoviePoster(contracts.NewAuthenticated(req, i.token), image); err != nil {
+		return fmt.Errorf("upload poster: %w", err)
+	}
+
+	return nil
+}
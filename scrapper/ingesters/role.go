@@ -0,0 +1,66 @@
+package ingesters
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/scrapper/models"
+	"golang.org/x/exp/slog"
+)
+
+type RoleIngester struct {
+	c      *client.Client
+	token  string
+	logger *slog.Logger
+}
+
+func NewRoleIngester(c *client.Client, token string, logger *slog.Logger) *RoleIngester {
+	return &RoleIngester{
+		c:      c,
+		token:  token,
+		logger: logger.With("ingester", "role"),
+	}
+}
+
+// Ingest creates the roles of the credits missing from the catalog on the server, credits can't refer to them otherwise.
+// Roles are matched by name, the existing ones are kept in their categories.
+func (i *RoleIngester) Ingest(casts map[string]*models.Cast) error {
+	existingRoles, err := i.c.GetMovieRoles(&contracts.GetMovieRolesRequest{})
+	if err != nil {
+		return fmt.Errorf("get roles: %w", err)
+	}
+
+	existing := make(map[string]bool, len(existingRoles))
+	for _, role := range existingRoles {
+		existing[role.Name] = true
+	}
+
+	missing := make(map[string]string)
+	for _, cast := range casts {
+		for _, credit := range cast.Cast {
+			if !existing[credit.Role] {
+				missing[credit.Role] = credit.Category
+			}
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		req := &contracts.CreateMovieRoleRequest{Name: name, Category: missing[name]}
+		if _, err = i.c.CreateMovieRole(contracts.NewAuthenticated(req, i.token)); err != nil {
+			return fmt.Errorf("create role %q: %w", name, err)
+		}
+
+		i.logger.With("role", name).Debug("Created role")
+	}
+
+	i.logger.Info("Successfully ingested roles")
+	return nil
+}
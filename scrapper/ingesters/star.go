@@ -3,13 +3,11 @@ package ingesters
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
-	"time"
 
 	"github.com/boichique/movie-reviews/client"
 	"github.com/boichique/movie-reviews/contracts"
-	"github.com/boichique/movie-reviews/internal/maps"
-	"github.com/boichique/movie-reviews/internal/slices"
 	"github.com/boichique/movie-reviews/scrapper/models"
 	"golang.org/x/exp/slog"
 	"golang.org/x/sync/errgroup"
@@ -31,105 +29,192 @@ func NewStarIngester(c *client.Client, token string, logger *slog.Logger) *StarI
 	}
 }
 
+// Ingest creates the stars missing on the server. Stars are matched by their IMDb IDs, so re-ingestion is exact.
+// A star without the IMDb ID, e.g. created by hand, is matched by its display name or aliases along with the birth date
+// and gets the IMDb ID.
 func (i *StarIngester) Ingest(stars map[string]*models.Star, bios map[string]*models.Bio) error {
-	existingStars, err := client.Paginate(&contracts.GetStarsPaginatedRequest{}, i.c.GetStars)
-	if err != nil {
-		return err
-	}
-
-	type starCommonIdentifier struct {
-		FirstName string
-		LastName  string
-		BirthDate time.Time
-	}
-
-	getID := func(s *contracts.Star) starCommonIdentifier {
-		return starCommonIdentifier{
-			FirstName: s.FirstName,
-			LastName:  s.LastName,
-			BirthDate: s.BirthDate,
-		}
-	}
-
-	idToStarMap := slices.ToMap(existingStars, getID, slices.NoChangeFunc[*contracts.Star]())
-	var mx sync.RWMutex
+	i.conversionMap = make(map[string]int, len(stars))
+	var mx sync.Mutex
 
 	group, _ := errgroup.WithContext(context.Background())
 	group.SetLimit(8)
 
 	for _, star := range stars {
 		star := star
-		commonID := starCommonIdentifier{star.FirstName, star.LastName, star.BirthDate}
-
-		if maps.ExistsLocked(idToStarMap, commonID, &mx) {
-			continue
-		}
 
 		group.Go(func() error {
-			var created bool
-			_, created, err = maps.GetOrCreateLocked(idToStarMap, commonID, &mx, func(name starCommonIdentifier) (*contracts.Star, error) {
-				bio, ok := bios[star.ID]
-				if !ok {
-					i.logger.With("star_id", star.ID).Error("Bio not found")
-					bio = &models.Bio{}
-				}
-
-				req := &contracts.CreateStarRequest{
-					FirstName: star.FirstName,
-					LastName:  star.LastName,
-					BirthDate: star.BirthDate,
-					DeathDate: star.DeathDate,
-				}
-				if bio.Bio != "" {
-					req.Bio = &bio.Bio
+			existing, err := i.c.GetStarByExternalID(imdbSource, star.ID)
+			switch {
+			case err == nil:
+			case isNotFound(err):
+				existing, err = i.findByName(star, bios[star.ID])
+				if err != nil {
+					return err
 				}
-				if bio.BirthPlace != "" {
-					req.BirthPlace = &bio.BirthPlace
+				if existing != nil {
+					if err = i.attachIMDbID(existing.ID, star.ID); err != nil {
+						return err
+					}
+
+					i.logger.
+						With("star_id", star.ID).
+						With("id", existing.ID).
+						Debug("Matched star by name")
+					break
 				}
 
-				var sd *contracts.StarDetails
-				sd, err = i.c.CreateStar(contracts.NewAuthenticated(req, i.token))
+				existing, err = i.create(star, bios[star.ID])
 				if err != nil {
-					return nil, fmt.Errorf("create star %q: %w", name, err)
+					return err
 				}
 
-				return &sd.Star, nil
-			})
-			if err != nil {
-				return err
-			}
-
-			if created {
 				i.logger.
 					With("star_id", star.ID).
-					With("star_common_id", commonID).
+					With("id", existing.ID).
 					Debug("Created star")
+			default:
+				return fmt.Errorf("get star %q: %w", star.ID, err)
 			}
 
+			mx.Lock()
+			i.conversionMap[star.ID] = existing.ID
+			mx.Unlock()
+
 			return nil
 		})
 	}
 
-	if err = group.Wait(); err != nil {
+	if err := group.Wait(); err != nil {
 		return fmt.Errorf("ingest stars: %w", err)
 	}
 
-	i.conversionMap = make(map[string]int, len(idToStarMap))
-	for _, star := range stars {
-		commonID := starCommonIdentifier{star.FirstName, star.LastName, star.BirthDate}
-		s, ok := idToStarMap[commonID]
-		if !ok {
-			i.logger.With("star_id", star.ID).Error("Cannot find star for conversion map creation")
-			continue
-		}
+	i.logger.Info("Successfully ingested stars")
+	return nil
+}
 
-		i.conversionMap[star.ID] = s.ID
+func (i *StarIngester) create(star *models.Star, bio *models.Bio) (*contracts.StarDetails, error) {
+	if bio == nil {
+		i.logger.With("star_id", star.ID).Error("Bio not found")
+		bio = &models.Bio{}
+	}
+
+	req := &contracts.CreateStarRequest{
+		DisplayName: &star.Name,
+		Aliases:     aliases(star, bio),
+		FirstName:   star.FirstName,
+		LastName:    star.LastName,
+		BirthDate:   star.BirthDate,
+		DeathDate:   star.DeathDate,
+		ExternalIDs: imdbID(star.ID),
+	}
+	if star.MiddleName != "" {
+		req.MiddleName = &star.MiddleName
+	}
+	if bio.Bio != "" {
+		req.Bio = &bio.Bio
+	}
+	if bio.BirthPlace != "" {
+		req.BirthPlace = &bio.BirthPlace
+	}
+
+	sd, err := i.c.CreateStar(contracts.NewAuthenticated(req, i.token))
+	if err != nil {
+		return nil, fmt.Errorf("create star %q: %w", star.ID, err)
+	}
+
+	return sd, nil
+}
+
+// attachIMDbID gives the IMDb ID to the star matched by name, so that the next ingestion matches it exactly.
+func (i *StarIngester) attachIMDbID(starID int, imdbID string) error {
+	req := &contracts.PutStarExternalIDRequest{
+		StarID:               starID,
+		PutExternalIDRequest: contracts.PutExternalIDRequest{Source: imdbSource, ExternalID: imdbID},
+	}
+	if _, err := i.c.PutStarExternalID(contracts.NewAuthenticated(req, i.token)); err != nil {
+		return fmt.Errorf("attach imdb id %q to star %d: %w", imdbID, starID, err)
 	}
 
-	i.logger.Info("Successfully ingested stars")
 	return nil
 }
 
+// findByName returns the star on the server born on the same day and known by the name or the birth name
+// of the scrapped star, nil if there is none. Stars with another IMDb ID are different people of the same name.
+func (i *StarIngester) findByName(star *models.Star, bio *models.Bio) (*contracts.StarDetails, error) {
+	if star.BirthDate.IsZero() {
+		return nil, nil
+	}
+
+	names := []string{star.Name}
+	if bio != nil && bio.BirthName != "" {
+		names = append(names, bio.BirthName)
+	}
+
+	for _, name := range names {
+		name := name
+		res, err := i.c.GetStars(&contracts.GetStarsPaginatedRequest{
+			Name:     &name,
+			BornFrom: &star.BirthDate,
+			BornTo:   &star.BirthDate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("find star %q by name: %w", star.ID, err)
+		}
+
+		for _, candidate := range res.Items {
+			details, err := i.c.GetStarByID(candidate.ID)
+			if err != nil {
+				return nil, fmt.Errorf("get star %d: %w", candidate.ID, err)
+			}
+			if knownAs(details, names) && !hasIMDbID(details.ExternalIDs) {
+				return details, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// aliases returns the birth name and the nicknames of the star which differ from its name.
+func aliases(star *models.Star, bio *models.Bio) []*contracts.StarAlias {
+	if bio == nil {
+		return nil
+	}
+
+	var result []*contracts.StarAlias
+	seen := map[string]bool{strings.ToLower(star.Name): true}
+	add := func(name, kind string) {
+		if name == "" || seen[strings.ToLower(name)] {
+			return
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, &contracts.StarAlias{Name: name, Kind: kind})
+	}
+
+	add(bio.BirthName, "birth")
+	for _, nickname := range bio.Nicknames {
+		add(nickname, "nickname")
+	}
+
+	return result
+}
+
+// knownAs tells whether the display name or any of the aliases of the star is one of the names, regardless of case.
+func knownAs(star *contracts.StarDetails, names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(star.DisplayName, name) {
+			return true
+		}
+		for _, alias := range star.Aliases {
+			if strings.EqualFold(alias.Name, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (i *StarIngester) Converter(imdbID string) (int, bool) {
 	id, ok := i.conversionMap[imdbID]
 	return id, ok
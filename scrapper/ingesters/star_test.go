@@ -0,0 +1,149 @@
+package ingesters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/scrapper/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	star := &models.Star{Name: "John Wayne"}
+
+	tests := []struct {
+		name string
+		bio  *models.Bio
+		want []*contracts.StarAlias
+	}{
+		{name: "no bio"},
+		{name: "no names", bio: &models.Bio{}},
+		{
+			name: "birth name and nicknames",
+			bio:  &models.Bio{BirthName: "Marion Robert Morrison", Nicknames: []string{"The Duke", "Duke"}},
+			want: []*contracts.StarAlias{
+				{Name: "Marion Robert Morrison", Kind: "birth"},
+				{Name: "The Duke", Kind: "nickname"},
+				{Name: "Duke", Kind: "nickname"},
+			},
+		},
+		{
+			name: "birth name same as the name",
+			bio:  &models.Bio{BirthName: "john wayne", Nicknames: []string{"The Duke"}},
+			want: []*contracts.StarAlias{{Name: "The Duke", Kind: "nickname"}},
+		},
+		{
+			name: "repeated nicknames",
+			bio:  &models.Bio{Nicknames: []string{"The Duke", "", "the duke", "John Wayne"}},
+			want: []*contracts.StarAlias{{Name: "The Duke", Kind: "nickname"}},
+		},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, aliases(star, tt.bio), tt.name)
+	}
+}
+
+func TestFindByName(t *testing.T) {
+	birthDate := time.Date(1907, time.May, 26, 0, 0, 0, 0, time.UTC)
+	duke := &contracts.StarDetails{
+		Star:    contracts.Star{ID: 1, DisplayName: "John Wayne", BirthDate: birthDate},
+		Aliases: []*contracts.StarAlias{{Name: "Marion Robert Morrison", Kind: "birth"}},
+	}
+	namesake := &contracts.StarDetails{
+		Star: contracts.Star{ID: 2, DisplayName: "Jon Wayne", BirthDate: birthDate},
+	}
+	scrapped := &contracts.StarDetails{
+		Star:        contracts.Star{ID: 3, DisplayName: "Duke Morrison", BirthDate: birthDate},
+		ExternalIDs: []*contracts.ExternalID{{Source: imdbSource, ID: "nm0000079"}},
+	}
+
+	tests := []struct {
+		name  string
+		star  *models.Star
+		bio   *models.Bio
+		stars []*contracts.StarDetails
+		want  *contracts.StarDetails
+	}{
+		{
+			name:  "no birth date",
+			star:  &models.Star{ID: "nm0000078", Name: "John Wayne"},
+			stars: []*contracts.StarDetails{duke},
+		},
+		{
+			name:  "by display name",
+			star:  &models.Star{ID: "nm0000078", Name: "john wayne", BirthDate: birthDate},
+			stars: []*contracts.StarDetails{namesake, duke},
+			want:  duke,
+		},
+		{
+			name:  "by birth name",
+			star:  &models.Star{ID: "nm0000078", Name: "The Duke", BirthDate: birthDate},
+			bio:   &models.Bio{BirthName: "Marion Robert Morrison"},
+			stars: []*contracts.StarDetails{duke},
+			want:  duke,
+		},
+		{
+			name:  "similar name only",
+			star:  &models.Star{ID: "nm0000078", Name: "John Wayne", BirthDate: birthDate},
+			stars: []*contracts.StarDetails{namesake},
+		},
+		{
+			name:  "already scrapped",
+			star:  &models.Star{ID: "nm0000078", Name: "Duke Morrison", BirthDate: birthDate},
+			stars: []*contracts.StarDetails{scrapped},
+		},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(starsHandler(t, tt.stars))
+		ingester := &StarIngester{c: client.New(server.URL)}
+
+		got, err := ingester.findByName(tt.star, tt.bio)
+		server.Close()
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+// starsHandler serves the stars like a fuzzy search would: every star is a candidate for any name.
+func starsHandler(t *testing.T, stars []*contracts.StarDetails) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/stars", func(w http.ResponseWriter, r *http.Request) {
+		require.NotEmpty(t, r.URL.Query().Get("name"))
+		require.NotEmpty(t, r.URL.Query().Get("bornFrom"))
+
+		res := contracts.PaginatedResponse[contracts.Star]{}
+		for _, star := range stars {
+			star := star.Star
+			res.Items = append(res.Items, &star)
+		}
+		writeJSON(t, w, res)
+	})
+	mux.HandleFunc("/api/stars/", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/stars/"))
+		require.NoError(t, err)
+
+		for _, star := range stars {
+			if star.ID == id {
+				writeJSON(t, w, star)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+
+	return mux
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
@@ -9,6 +9,13 @@ type Movie struct {
 	ReleaseDate time.Time `json:"release_date"`
 	Genres      []string  `json:"genres"`
 
+	RuntimeMinutes  int      `json:"runtime_minutes"`
+	Countries       []string `json:"countries"`
+	SpokenLanguages []string `json:"spoken_languages"`
+	AgeRating       string   `json:"age_rating"`
+	Tagline         string   `json:"tagline"`
+	ImageURL        string   `json:"image_url"`
+
 	Link string `json:"_link"`
 }
 
@@ -21,6 +28,7 @@ type Cast struct {
 
 type Credit struct {
 	Role     string `json:"role"`
+	Category string `json:"category"`
 	Details  string `json:"details"`
 	StarID   string `json:"star_id"`
 	StarName string `json:"star_name"`
@@ -28,20 +36,23 @@ type Credit struct {
 }
 
 type Star struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	FirstName string     `json:"first_name"`
-	LastName  string     `json:"last_name"`
-	BirthDate time.Time  `json:"birth_date"`
-	DeathDate *time.Time `json:"death_date"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	FirstName  string     `json:"first_name"`
+	MiddleName string     `json:"middle_name,omitempty"`
+	LastName   string     `json:"last_name"`
+	BirthDate  time.Time  `json:"birth_date"`
+	DeathDate  *time.Time `json:"death_date"`
 
 	Link string `json:"_link"`
 }
 
 type Bio struct {
-	ID         string `json:"id"`
-	Bio        string `json:"bio"`
-	BirthPlace string `json:"birth_place"`
+	ID         string   `json:"id"`
+	Bio        string   `json:"bio"`
+	BirthPlace string   `json:"birth_place"`
+	BirthName  string   `json:"birth_name,omitempty"`
+	Nicknames  []string `json:"nicknames,omitempty"`
 
 	Link string `json:"_link"`
 }
@@ -0,0 +1,14 @@
+CREATE TABLE user_recommendations (
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    movie_id INTEGER NOT NULL REFERENCES movies(id),
+    score FLOAT8 NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, movie_id)
+);
+CREATE INDEX idx_user_recommendations_movie_id ON user_recommendations(movie_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_user_recommendations_movie_id;
+DROP TABLE user_recommendations;
@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_movies_title_trgm ON movies USING GIN(lower(title) gin_trgm_ops);
+
+---- create above / drop below ----
+
+DROP INDEX idx_movies_title_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,5 @@
+CREATE INDEX idx_stars_name_trgm ON stars USING GIN(lower(first_name || ' ' || last_name) gin_trgm_ops);
+
+---- create above / drop below ----
+
+DROP INDEX idx_stars_name_trgm;
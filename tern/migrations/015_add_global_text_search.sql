@@ -0,0 +1,84 @@
+ALTER TABLE stars ADD COLUMN search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION stars_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        new.search_vector :=
+            setweight(to_tsvector('english', concat_ws(' ', new.first_name, new.middle_name, new.last_name)), 'A') ||
+            setweight(to_tsvector('english', coalesce(new.bio, '')), 'B') ||
+            setweight(to_tsvector('english', coalesce(new.birth_place, '')), 'C');
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stars_search_vector_update_trigger
+    BEFORE INSERT OR UPDATE ON stars
+    FOR EACH ROW
+EXECUTE FUNCTION stars_search_vector_trigger();
+
+UPDATE stars SET search_vector =
+    setweight(to_tsvector('english', concat_ws(' ', first_name, middle_name, last_name)), 'A') ||
+    setweight(to_tsvector('english', coalesce(bio, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(birth_place, '')), 'C');
+
+CREATE INDEX idx_stars_search_vector ON stars USING GIN(search_vector);
+
+ALTER TABLE reviews ADD COLUMN search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION reviews_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        new.search_vector :=
+            setweight(to_tsvector('english', coalesce(new.title, '')), 'A') ||
+            setweight(to_tsvector('english', coalesce(new.content, '')), 'B');
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviews_search_vector_update_trigger
+    BEFORE INSERT OR UPDATE ON reviews
+    FOR EACH ROW
+EXECUTE FUNCTION reviews_search_vector_trigger();
+
+UPDATE reviews SET search_vector =
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B');
+
+CREATE INDEX idx_reviews_search_vector ON reviews USING GIN(search_vector);
+
+ALTER TABLE users ADD COLUMN search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION users_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        new.search_vector :=
+            setweight(to_tsvector('english', new.username), 'A') ||
+            setweight(to_tsvector('english', coalesce(new.bio, '')), 'B');
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_search_vector_update_trigger
+    BEFORE INSERT OR UPDATE ON users
+    FOR EACH ROW
+EXECUTE FUNCTION users_search_vector_trigger();
+
+UPDATE users SET search_vector =
+    setweight(to_tsvector('english', username), 'A') ||
+    setweight(to_tsvector('english', coalesce(bio, '')), 'B');
+
+CREATE INDEX idx_users_search_vector ON users USING GIN(search_vector);
+
+---- create above / drop below ----
+
+DROP INDEX idx_users_search_vector;
+DROP TRIGGER users_search_vector_update_trigger ON users;
+DROP FUNCTION users_search_vector_trigger();
+ALTER TABLE users DROP COLUMN search_vector;
+
+DROP INDEX idx_reviews_search_vector;
+DROP TRIGGER reviews_search_vector_update_trigger ON reviews;
+DROP FUNCTION reviews_search_vector_trigger();
+ALTER TABLE reviews DROP COLUMN search_vector;
+
+DROP INDEX idx_stars_search_vector;
+DROP TRIGGER stars_search_vector_update_trigger ON stars;
+DROP FUNCTION stars_search_vector_trigger();
+ALTER TABLE stars DROP COLUMN search_vector;
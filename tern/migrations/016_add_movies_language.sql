@@ -0,0 +1,56 @@
+ALTER TABLE movies ADD COLUMN language VARCHAR(2) NOT NULL DEFAULT 'en';
+
+-- search_config maps an ISO 639-1 language code to the text search configuration used to stem it.
+-- Languages without a stemmer fall back to the simple configuration.
+CREATE OR REPLACE FUNCTION search_config(lang TEXT) RETURNS regconfig AS $$
+    SELECT CASE lower(lang)
+        WHEN 'ar' THEN 'arabic'
+        WHEN 'da' THEN 'danish'
+        WHEN 'de' THEN 'german'
+        WHEN 'en' THEN 'english'
+        WHEN 'es' THEN 'spanish'
+        WHEN 'fi' THEN 'finnish'
+        WHEN 'fr' THEN 'french'
+        WHEN 'hu' THEN 'hungarian'
+        WHEN 'it' THEN 'italian'
+        WHEN 'nl' THEN 'dutch'
+        WHEN 'no' THEN 'norwegian'
+        WHEN 'pt' THEN 'portuguese'
+        WHEN 'ro' THEN 'romanian'
+        WHEN 'ru' THEN 'russian'
+        WHEN 'sv' THEN 'swedish'
+        WHEN 'tr' THEN 'turkish'
+        ELSE 'simple'
+    END::regconfig
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION movies_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        new.search_vector :=
+            setweight(to_tsvector(search_config(new.language), new.title), 'A') ||
+            setweight(to_tsvector(search_config(new.language), new.description), 'B');
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+UPDATE movies SET search_vector =
+    setweight(to_tsvector(search_config(language), title), 'A') ||
+    setweight(to_tsvector(search_config(language), description), 'B');
+
+---- create above / drop below ----
+
+CREATE OR REPLACE FUNCTION movies_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        new.search_vector :=
+            setweight(to_tsvector('english', new.title), 'A') ||
+            setweight(to_tsvector('english', new.description), 'B');
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+UPDATE movies SET search_vector =
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', description), 'B');
+
+DROP FUNCTION search_config(TEXT);
+ALTER TABLE movies DROP COLUMN language;
@@ -0,0 +1,13 @@
+CREATE TABLE movie_translations
+(
+    movie_id    INTEGER      NOT NULL REFERENCES movies(id),
+    locale      VARCHAR(16)  NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, locale)
+);
+
+---- create above / drop below ----
+
+DROP TABLE movie_translations;
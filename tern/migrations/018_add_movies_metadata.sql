@@ -0,0 +1,21 @@
+ALTER TABLE movies
+    ADD COLUMN runtime_minutes  SMALLINT,
+    ADD COLUMN countries        VARCHAR(2)[] NOT NULL DEFAULT '{}',
+    ADD COLUMN spoken_languages VARCHAR(2)[] NOT NULL DEFAULT '{}',
+    ADD COLUMN age_rating       VARCHAR(16),
+    ADD COLUMN tagline          VARCHAR(255);
+
+CREATE INDEX idx_movies_countries ON movies USING GIN(countries);
+CREATE INDEX idx_movies_spoken_languages ON movies USING GIN(spoken_languages);
+
+---- create above / drop below ----
+
+DROP INDEX idx_movies_spoken_languages;
+DROP INDEX idx_movies_countries;
+
+ALTER TABLE movies
+    DROP COLUMN tagline,
+    DROP COLUMN age_rating,
+    DROP COLUMN spoken_languages,
+    DROP COLUMN countries,
+    DROP COLUMN runtime_minutes;
@@ -0,0 +1,24 @@
+CREATE TABLE movie_external_ids
+(
+    movie_id    INTEGER     NOT NULL REFERENCES movies(id),
+    source      VARCHAR(32) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, external_id),
+    CONSTRAINT movie_external_ids_movie_source UNIQUE (movie_id, source)
+);
+
+CREATE TABLE star_external_ids
+(
+    star_id     INTEGER     NOT NULL REFERENCES stars(id),
+    source      VARCHAR(32) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, external_id),
+    CONSTRAINT star_external_ids_star_source UNIQUE (star_id, source)
+);
+
+---- create above / drop below ----
+
+DROP TABLE star_external_ids;
+DROP TABLE movie_external_ids;
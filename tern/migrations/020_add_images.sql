@@ -0,0 +1,21 @@
+ALTER TABLE movies
+    ADD COLUMN poster_key           VARCHAR(255),
+    ADD COLUMN poster_url           TEXT,
+    ADD COLUMN poster_thumbnail_url TEXT;
+
+ALTER TABLE stars
+    ADD COLUMN photo_key           VARCHAR(255),
+    ADD COLUMN photo_url           TEXT,
+    ADD COLUMN photo_thumbnail_url TEXT;
+
+---- create above / drop below ----
+
+ALTER TABLE stars
+    DROP COLUMN photo_thumbnail_url,
+    DROP COLUMN photo_url,
+    DROP COLUMN photo_key;
+
+ALTER TABLE movies
+    DROP COLUMN poster_thumbnail_url,
+    DROP COLUMN poster_url,
+    DROP COLUMN poster_key;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN avatar_key VARCHAR(255);
+
+---- create above / drop below ----
+
+ALTER TABLE users
+    DROP COLUMN avatar_key;
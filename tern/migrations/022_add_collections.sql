@@ -0,0 +1,21 @@
+CREATE TABLE collections
+(
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(128) UNIQUE NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+-- a movie belongs to at most one collection
+CREATE TABLE collection_movies
+(
+    collection_id INTEGER  NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    movie_id      INTEGER  NOT NULL UNIQUE REFERENCES movies(id),
+    order_no      SMALLINT NOT NULL,
+    PRIMARY KEY (collection_id, movie_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE collection_movies;
+DROP TABLE collections;
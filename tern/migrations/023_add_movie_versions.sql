@@ -0,0 +1,38 @@
+CREATE TABLE movie_versions
+(
+    movie_id    INTEGER   NOT NULL REFERENCES movies(id),
+    version     INTEGER   NOT NULL,
+    snapshot    JSONB     NOT NULL,
+    changed_by  INTEGER REFERENCES users(id),
+    rollback_of INTEGER,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, version)
+);
+
+-- the current state of the existing movies is their first stored version
+INSERT INTO movie_versions (movie_id, version, snapshot)
+SELECT m.id,
+       m.version,
+       jsonb_build_object(
+           'title', m.title,
+           'description', m.description,
+           'release_date', to_char(m.release_date, 'YYYY-MM-DD"T"00:00:00"Z"'),
+           'language', m.language,
+           'runtime_minutes', m.runtime_minutes,
+           'countries', m.countries,
+           'spoken_languages', m.spoken_languages,
+           'age_rating', m.age_rating,
+           'tagline', m.tagline,
+           'genre_ids', COALESCE((SELECT jsonb_agg(mg.genre_id ORDER BY mg.order_no)
+                                  FROM movie_genres mg
+                                  WHERE mg.movie_id = m.id), '[]'),
+           'cast', COALESCE((SELECT jsonb_agg(jsonb_build_object('star_id', ms.star_id, 'role', ms.role, 'details', ms.details)
+                                              ORDER BY ms.order_no)
+                             FROM movie_stars ms
+                             WHERE ms.movie_id = m.id), '[]')
+       )
+FROM movies m;
+
+---- create above / drop below ----
+
+DROP TABLE movie_versions;
@@ -0,0 +1,18 @@
+-- a deleted review no longer prevents the user from reviewing the movie again
+ALTER TABLE reviews DROP CONSTRAINT reviews_movie_id_user_id_key;
+CREATE UNIQUE INDEX idx_reviews_movie_id_user_id ON reviews(movie_id, user_id) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_movies_deleted_at ON movies(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_stars_deleted_at ON stars(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_reviews_deleted_at ON reviews(deleted_at) WHERE deleted_at IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX idx_reviews_deleted_at;
+DROP INDEX idx_users_deleted_at;
+DROP INDEX idx_stars_deleted_at;
+DROP INDEX idx_movies_deleted_at;
+
+DROP INDEX idx_reviews_movie_id_user_id;
+ALTER TABLE reviews ADD CONSTRAINT reviews_movie_id_user_id_key UNIQUE (movie_id, user_id);
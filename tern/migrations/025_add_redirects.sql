@@ -0,0 +1,17 @@
+-- an id merged into another entity keeps redirecting to it, chains are collapsed on every merge
+CREATE TABLE movie_redirects (
+    old_id INTEGER PRIMARY KEY,
+    new_id INTEGER NOT NULL REFERENCES movies(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_movie_redirects_new_id ON movie_redirects(new_id);
+
+CREATE TABLE star_redirects (
+    old_id INTEGER PRIMARY KEY,
+    new_id INTEGER NOT NULL REFERENCES stars(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_star_redirects_new_id ON star_redirects(new_id);
+
+---- create above / drop below ----
+
+DROP TABLE star_redirects;
+DROP TABLE movie_redirects;
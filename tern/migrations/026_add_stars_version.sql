@@ -0,0 +1,5 @@
+ALTER TABLE stars ADD COLUMN version integer NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE stars DROP COLUMN version;
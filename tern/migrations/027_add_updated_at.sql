@@ -0,0 +1,55 @@
+-- updated_at is the Last-Modified of the entity, kept by the trigger on every update of the row
+CREATE OR REPLACE FUNCTION set_updated_at_trigger() RETURNS trigger AS $$
+    begin
+        new.updated_at := NOW();
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE movies ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE movies SET updated_at = created_at;
+CREATE TRIGGER movies_updated_at_trigger
+    BEFORE UPDATE ON movies
+    FOR EACH ROW
+EXECUTE FUNCTION set_updated_at_trigger();
+
+ALTER TABLE stars ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE stars SET updated_at = created_at;
+CREATE TRIGGER stars_updated_at_trigger
+    BEFORE UPDATE ON stars
+    FOR EACH ROW
+EXECUTE FUNCTION set_updated_at_trigger();
+
+ALTER TABLE genres ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+CREATE TRIGGER genres_updated_at_trigger
+    BEFORE UPDATE ON genres
+    FOR EACH ROW
+EXECUTE FUNCTION set_updated_at_trigger();
+
+ALTER TABLE users ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE users SET updated_at = created_at;
+CREATE TRIGGER users_updated_at_trigger
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+EXECUTE FUNCTION set_updated_at_trigger();
+
+ALTER TABLE reviews ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE reviews SET updated_at = created_at;
+CREATE TRIGGER reviews_updated_at_trigger
+    BEFORE UPDATE ON reviews
+    FOR EACH ROW
+EXECUTE FUNCTION set_updated_at_trigger();
+
+---- create above / drop below ----
+
+DROP TRIGGER reviews_updated_at_trigger ON reviews;
+ALTER TABLE reviews DROP COLUMN updated_at;
+DROP TRIGGER users_updated_at_trigger ON users;
+ALTER TABLE users DROP COLUMN updated_at;
+DROP TRIGGER genres_updated_at_trigger ON genres;
+ALTER TABLE genres DROP COLUMN updated_at;
+DROP TRIGGER stars_updated_at_trigger ON stars;
+ALTER TABLE stars DROP COLUMN updated_at;
+DROP TRIGGER movies_updated_at_trigger ON movies;
+ALTER TABLE movies DROP COLUMN updated_at;
+DROP FUNCTION set_updated_at_trigger();
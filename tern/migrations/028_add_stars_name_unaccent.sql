@@ -0,0 +1,14 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent is only stable, as its dictionary may change, indexes need the dictionary pinned down
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX idx_stars_name_unaccent_trgm ON stars USING GIN(immutable_unaccent(lower(first_name || ' ' || last_name)) gin_trgm_ops);
+
+---- create above / drop below ----
+
+DROP INDEX idx_stars_name_unaccent_trgm;
+DROP FUNCTION immutable_unaccent(text);
+DROP EXTENSION IF EXISTS unaccent;
@@ -0,0 +1,30 @@
+-- the display name is how the star is credited, e.g. Zendaya, the sort name is how the star is filed, e.g. "Bonham Carter, Helena"
+ALTER TABLE stars ADD COLUMN display_name VARCHAR(100);
+ALTER TABLE stars ADD COLUMN sort_name VARCHAR(100);
+UPDATE stars SET
+    display_name = concat_ws(' ', nullif(first_name, ''), nullif(last_name, '')),
+    sort_name = concat_ws(', ', nullif(last_name, ''), nullif(first_name, ''));
+ALTER TABLE stars ALTER COLUMN display_name SET NOT NULL;
+ALTER TABLE stars ALTER COLUMN sort_name SET NOT NULL;
+
+DROP INDEX idx_stars_name_unaccent_trgm;
+CREATE INDEX idx_stars_display_name_unaccent_trgm ON stars USING GIN(immutable_unaccent(lower(display_name)) gin_trgm_ops);
+
+-- aliases are the other names the star is known by: birth names, stage names, nicknames
+CREATE TABLE star_aliases (
+    star_id INT NOT NULL REFERENCES stars(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('birth', 'stage', 'nickname', 'other')),
+    order_no INT NOT NULL,
+    PRIMARY KEY (star_id, name)
+);
+
+CREATE INDEX idx_star_aliases_name_unaccent_trgm ON star_aliases USING GIN(immutable_unaccent(lower(name)) gin_trgm_ops);
+
+---- create above / drop below ----
+
+DROP TABLE star_aliases;
+DROP INDEX idx_stars_display_name_unaccent_trgm;
+CREATE INDEX idx_stars_name_unaccent_trgm ON stars USING GIN(immutable_unaccent(lower(first_name || ' ' || last_name)) gin_trgm_ops);
+ALTER TABLE stars DROP COLUMN sort_name;
+ALTER TABLE stars DROP COLUMN display_name;
@@ -0,0 +1,40 @@
+-- roles are a catalog editors manage instead of an enum, the cast are credited in front of the camera, the crew behind it
+CREATE TABLE movie_roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    category VARCHAR(8) NOT NULL CHECK (category IN ('cast', 'crew')),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER movie_roles_updated_at_trigger
+    BEFORE UPDATE ON movie_roles
+    FOR EACH ROW
+EXECUTE FUNCTION set_updated_at_trigger();
+
+INSERT INTO movie_roles (name, category) VALUES
+    ('actor', 'cast'),
+    ('voice actor', 'cast'),
+    ('director', 'crew'),
+    ('writer', 'crew'),
+    ('producer', 'crew'),
+    ('composer', 'crew'),
+    ('cinematographer', 'crew'),
+    ('editor', 'crew'),
+    ('casting director', 'crew'),
+    ('production designer', 'crew'),
+    ('art director', 'crew'),
+    ('costume designer', 'crew');
+
+-- credits follow the renames of their roles, roles still credited can't be deleted
+ALTER TABLE movie_stars ALTER COLUMN role TYPE VARCHAR(50) USING role::text;
+ALTER TABLE movie_stars ADD CONSTRAINT movie_stars_role_fkey FOREIGN KEY (role) REFERENCES movie_roles(name) ON UPDATE CASCADE;
+DROP TYPE movie_role;
+
+---- create above / drop below ----
+
+CREATE TYPE movie_role AS ENUM ('actor', 'voice actor', 'writer', 'producer', 'director', 'composer');
+ALTER TABLE movie_stars DROP CONSTRAINT movie_stars_role_fkey;
+DELETE FROM movie_stars WHERE role NOT IN ('actor', 'voice actor', 'writer', 'producer', 'director', 'composer');
+ALTER TABLE movie_stars ALTER COLUMN role TYPE movie_role USING role::movie_role;
+DROP TRIGGER movie_roles_updated_at_trigger ON movie_roles;
+DROP TABLE movie_roles;
@@ -0,0 +1,55 @@
+-- stars are searched by the name they are credited with and by their aliases
+CREATE OR REPLACE FUNCTION stars_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        new.search_vector :=
+            setweight(to_tsvector('english', concat_ws(' ', new.display_name,
+                (SELECT string_agg(name, ' ' ORDER BY order_no) FROM star_aliases WHERE star_id = new.id))), 'A') ||
+            setweight(to_tsvector('english', coalesce(new.bio, '')), 'B') ||
+            setweight(to_tsvector('english', coalesce(new.birth_place, '')), 'C');
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+-- touching the star makes its trigger pick up the changed aliases
+CREATE OR REPLACE FUNCTION star_aliases_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        if TG_OP <> 'INSERT' then
+            UPDATE stars SET search_vector = NULL WHERE id = old.star_id;
+        end if;
+        if TG_OP <> 'DELETE' then
+            UPDATE stars SET search_vector = NULL WHERE id = new.star_id;
+        end if;
+        return NULL;
+    end
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER star_aliases_search_vector_update_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON star_aliases
+    FOR EACH ROW
+EXECUTE FUNCTION star_aliases_search_vector_trigger();
+
+UPDATE stars SET search_vector =
+    setweight(to_tsvector('english', concat_ws(' ', display_name,
+        (SELECT string_agg(a.name, ' ' ORDER BY a.order_no) FROM star_aliases a WHERE a.star_id = stars.id))), 'A') ||
+    setweight(to_tsvector('english', coalesce(bio, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(birth_place, '')), 'C');
+
+---- create above / drop below ----
+
+DROP TRIGGER star_aliases_search_vector_update_trigger ON star_aliases;
+DROP FUNCTION star_aliases_search_vector_trigger();
+
+CREATE OR REPLACE FUNCTION stars_search_vector_trigger() RETURNS trigger AS $$
+    begin
+        new.search_vector :=
+            setweight(to_tsvector('english', concat_ws(' ', new.first_name, new.middle_name, new.last_name)), 'A') ||
+            setweight(to_tsvector('english', coalesce(new.bio, '')), 'B') ||
+            setweight(to_tsvector('english', coalesce(new.birth_place, '')), 'C');
+        return new;
+    end
+$$ LANGUAGE plpgsql;
+
+UPDATE stars SET search_vector =
+    setweight(to_tsvector('english', concat_ws(' ', first_name, middle_name, last_name)), 'A') ||
+    setweight(to_tsvector('english', coalesce(bio, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(birth_place, '')), 'C');
@@ -0,0 +1,6 @@
+-- stars are suggested by their display names and aliases, served by the indexes on the unaccented names
+DROP INDEX idx_stars_name_trgm;
+
+---- create above / drop below ----
+
+CREATE INDEX idx_stars_name_trgm ON stars USING GIN(lower(first_name || ' ' || last_name) gin_trgm_ops);
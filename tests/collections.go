@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func collectionsAPIChecks(t *testing.T, c *client.Client) {
+	var trilogy *contracts.Collection
+	t.Run("collections.CreateCollection: success", func(t *testing.T) {
+		req := &contracts.CreateCollectionRequest{
+			Name:        "Random Trilogy",
+			Description: ptr("Three random movies"),
+		}
+		collection, err := c.CreateCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, collection.ID)
+		require.Equal(t, req.Name, collection.Name)
+		require.Equal(t, req.Description, collection.Description)
+
+		trilogy = collection
+	})
+
+	t.Run("collections.CreateCollection: existing name", func(t *testing.T) {
+		req := &contracts.CreateCollectionRequest{Name: trilogy.Name}
+		_, err := c.CreateCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistsError(t, err, "collection", "name", trilogy.Name)
+	})
+
+	t.Run("collections.CreateCollection: non-editor", func(t *testing.T) {
+		user := registerRandomUser(t, c)
+		token := login(t, c, user.Email, standardPassword)
+
+		req := &contracts.CreateCollectionRequest{Name: "Forbidden Saga"}
+		_, err := c.CreateCollection(contracts.NewAuthenticated(req, token))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+
+	t.Run("collections.GetCollections: success", func(t *testing.T) {
+		res, err := c.GetCollections(&contracts.GetCollectionsPaginatedRequest{})
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.Collection{trilogy}, res.Items)
+	})
+
+	first, second, third := createRandomMovie(t, c), createRandomMovie(t, c), createRandomMovie(t, c)
+	t.Run("collections.SetCollectionMovies: success", func(t *testing.T) {
+		req := &contracts.SetCollectionMoviesRequest{
+			CollectionID: trilogy.ID,
+			MovieIDs:     []int{second.ID, first.ID},
+		}
+		err := c.SetCollectionMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		collection := getCollection(t, c, trilogy.ID)
+		require.Equal(t, []int{second.ID, first.ID}, collectionMovieIDs(collection))
+
+		// reorder and extend
+		req.MovieIDs = []int{first.ID, second.ID, third.ID}
+		err = c.SetCollectionMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		collection = getCollection(t, c, trilogy.ID)
+		require.Equal(t, []int{first.ID, second.ID, third.ID}, collectionMovieIDs(collection))
+		require.Equal(t, &contracts.MovieCollection{
+			ID:       trilogy.ID,
+			Name:     trilogy.Name,
+			Position: 2,
+		}, getMovie(t, c, second.ID).Collection)
+	})
+
+	t.Run("collections.SetCollectionMovies: bad movies", func(t *testing.T) {
+		req := &contracts.SetCollectionMoviesRequest{
+			CollectionID: trilogy.ID,
+			MovieIDs:     []int{first.ID, first.ID},
+		}
+		err := c.SetCollectionMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "listed more than once")
+
+		req.MovieIDs = []int{first.ID, 100000}
+		err = c.SetCollectionMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "movie", "id", 100000)
+
+		other, err := c.CreateCollection(contracts.NewAuthenticated(&contracts.CreateCollectionRequest{Name: "Other"}, johnDoeToken))
+		require.NoError(t, err)
+		req = &contracts.SetCollectionMoviesRequest{
+			CollectionID: other.ID,
+			MovieIDs:     []int{third.ID},
+		}
+		err = c.SetCollectionMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "already belongs to collection")
+
+		err = c.DeleteCollection(contracts.NewAuthenticated(&contracts.GetOrDeleteCollectionRequest{CollectionID: other.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		// the failed requests left the collection intact
+		require.Equal(t, []int{first.ID, second.ID, third.ID}, collectionMovieIDs(getCollection(t, c, trilogy.ID)))
+	})
+
+	t.Run("collections.GetCollection: aggregate rating", func(t *testing.T) {
+		reviewer := registerRandomUser(t, c)
+		token := login(t, c, reviewer.Email, standardPassword)
+		for movieID, rating := range map[int]int{first.ID: 9, second.ID: 6, third.ID: 3} {
+			req := &contracts.CreateReviewRequest{
+				MovieID: movieID,
+				UserID:  reviewer.ID,
+				Rating:  rating,
+				Title:   "Part of the trilogy",
+				Content: "Watch all of them in order, they tell one story.",
+			}
+			_, err := c.CreateReview(contracts.NewAuthenticated(req, token))
+			require.NoError(t, err)
+		}
+
+		collection := getCollection(t, c, trilogy.ID)
+		require.Equal(t, 3, collection.RatingsCount)
+		require.Equal(t, 6.0, *collection.AvgRating)
+
+		// deleted movies leave the collection and its rating
+		err := c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: third.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		collection = getCollection(t, c, trilogy.ID)
+		require.Equal(t, []int{first.ID, second.ID}, collectionMovieIDs(collection))
+		require.Equal(t, 2, collection.RatingsCount)
+		require.Equal(t, 7.5, *collection.AvgRating)
+	})
+
+	t.Run("collections.UpdateCollection: success", func(t *testing.T) {
+		req := &contracts.UpdateCollectionRequest{
+			CollectionID: trilogy.ID,
+			Name:         "Random Duology",
+		}
+		err := c.UpdateCollection(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		collection := getCollection(t, c, trilogy.ID)
+		require.Equal(t, req.Name, collection.Name)
+		require.Nil(t, collection.Description)
+		require.Equal(t, req.Name, getMovie(t, c, first.ID).Collection.Name)
+	})
+
+	t.Run("collections.DeleteCollection: success", func(t *testing.T) {
+		err := c.DeleteCollection(contracts.NewAuthenticated(&contracts.GetOrDeleteCollectionRequest{CollectionID: trilogy.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		require.Nil(t, getCollection(t, c, trilogy.ID))
+		require.Nil(t, getMovie(t, c, first.ID).Collection)
+
+		err = c.DeleteCollection(contracts.NewAuthenticated(&contracts.GetOrDeleteCollectionRequest{CollectionID: trilogy.ID}, johnDoeToken))
+		requireNotFoundError(t, err, "collection", "id", trilogy.ID)
+	})
+}
+
+func getCollection(t *testing.T, c *client.Client, id int) *contracts.CollectionDetails {
+	collection, err := c.GetCollectionByID(id)
+	if err != nil {
+		cerr, ok := err.(*client.Error)
+		require.True(t, ok)
+		require.Equal(t, http.StatusNotFound, cerr.Code)
+		return nil
+	}
+
+	return collection
+}
+
+func collectionMovieIDs(collection *contracts.CollectionDetails) []int {
+	ids := make([]int, len(collection.Movies))
+	for i, movie := range collection.Movies {
+		ids[i] = movie.ID
+	}
+
+	return ids
+}
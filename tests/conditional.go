@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func conditionalAPIChecks(t *testing.T, c *client.Client, addr string) {
+	movie := createRandomMovie(t, c)
+
+	t.Run("conditional.GetMovieConditional: not modified", func(t *testing.T) {
+		tagged, err := c.GetMovieConditional(movie.ID, "")
+		require.NoError(t, err)
+		require.NotEmpty(t, tagged.ETag)
+		require.False(t, tagged.LastModified.IsZero())
+		require.Equal(t, getMovie(t, c, movie.ID), tagged.Entity)
+
+		again, err := c.GetMovieConditional(movie.ID, tagged.ETag)
+		require.NoError(t, err)
+		require.Nil(t, again.Entity)
+		require.Equal(t, tagged.ETag, again.ETag)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/movies/%d", addr, movie.ID), nil)
+		require.NoError(t, err)
+		req.Header.Set(contracts.HeaderIfNoneMatch, `"other", W/`+tagged.ETag)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotModified, res.StatusCode)
+		require.Equal(t, "Accept-Language", res.Header.Get("Vary"))
+	})
+
+	t.Run("conditional.UpdateMovie: If-Match", func(t *testing.T) {
+		tagged, err := c.GetMovieConditional(movie.ID, "")
+		require.NoError(t, err)
+
+		// the precondition stands for the version, which is left out
+		req := &contracts.UpdateMovieRequest{
+			MovieID:     movie.ID,
+			Title:       movie.Title,
+			ReleaseDate: movie.ReleaseDate,
+			Description: "Updated with a precondition",
+			GenresID:    []int{Drama.ID},
+		}
+		err = c.UpdateMovie(contracts.NewAuthenticated(req, johnDoeToken).WithIfMatch(tagged.ETag))
+		require.NoError(t, err)
+
+		req.Description = "Updated with a stale precondition"
+		err = c.UpdateMovie(contracts.NewAuthenticated(req, johnDoeToken).WithIfMatch(tagged.ETag))
+		requirePreconditionFailedError(t, err, "movie", "id", movie.ID)
+
+		updated, err := c.GetMovieConditional(movie.ID, tagged.ETag)
+		require.NoError(t, err)
+		require.NotNil(t, updated.Entity)
+		require.NotEqual(t, tagged.ETag, updated.ETag)
+		require.Equal(t, "Updated with a precondition", updated.Entity.Description)
+		require.Equal(t, tagged.Entity.Version+1, updated.Entity.Version)
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken).WithIfMatch(tagged.ETag))
+		requirePreconditionFailedError(t, err, "movie", "id", movie.ID)
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken).WithIfMatch(updated.ETag))
+		require.NoError(t, err)
+	})
+
+	t.Run("conditional.UpdateStar: If-Match", func(t *testing.T) {
+		star := createRandomStar(t, c, johnDoeToken)
+		tagged, err := c.GetStarConditional(star.ID, "")
+		require.NoError(t, err)
+
+		req := &contracts.UpdateStarRequest{
+			StarID:    star.ID,
+			FirstName: star.FirstName,
+			LastName:  star.LastName,
+			BirthDate: star.BirthDate,
+			Bio:       ptr("Updated with a precondition"),
+		}
+		err = c.UpdateStar(contracts.NewAuthenticated(req, johnDoeToken).WithIfMatch(`"stale"`))
+		requirePreconditionFailedError(t, err, "star", "id", star.ID)
+		err = c.UpdateStar(contracts.NewAuthenticated(req, johnDoeToken).WithIfMatch(tagged.ETag))
+		require.NoError(t, err)
+
+		err = c.DeleteStar(contracts.NewAuthenticated(&contracts.DeleteStarRequest{StarID: star.ID}, johnDoeToken).WithIfMatch(tagged.ETag))
+		requirePreconditionFailedError(t, err, "star", "id", star.ID)
+		err = c.DeleteStar(contracts.NewAuthenticated(&contracts.DeleteStarRequest{StarID: star.ID}, johnDoeToken).WithIfMatch("*"))
+		require.NoError(t, err)
+	})
+
+	t.Run("conditional.UpdateGenre: If-Match", func(t *testing.T) {
+		genre, err := c.CreateGenre(contracts.NewAuthenticated(&contracts.CreateGenreRequest{Name: "Noir"}, johnDoeToken))
+		require.NoError(t, err)
+		tagged, err := c.GetGenreConditional(genre.ID, "")
+		require.NoError(t, err)
+		require.Equal(t, genre, tagged.Entity)
+
+		req := &contracts.UpdateGenreRequest{GenreID: genre.ID, Name: "Neo-Noir"}
+		err = c.UpdateGenre(contracts.NewAuthenticated(req, johnDoeToken).WithIfMatch(tagged.ETag))
+		require.NoError(t, err)
+
+		del := &contracts.GetOrDeleteGenreRequest{GenreID: genre.ID}
+		err = c.DeleteGenre(contracts.NewAuthenticated(del, johnDoeToken).WithIfMatch(tagged.ETag))
+		requirePreconditionFailedError(t, err, "genre", "id", genre.ID)
+
+		updated, err := c.GetGenreConditional(genre.ID, tagged.ETag)
+		require.NoError(t, err)
+		require.Equal(t, "Neo-Noir", updated.Entity.Name)
+		err = c.DeleteGenre(contracts.NewAuthenticated(del, johnDoeToken).WithIfMatch(updated.ETag))
+		require.NoError(t, err)
+	})
+
+	t.Run("conditional.UpdateUserBio: If-Match", func(t *testing.T) {
+		user := registerRandomUser(t, c)
+		token := login(t, c, user.Email, standardPassword)
+		tagged, err := c.GetUserConditional(user.ID, "")
+		require.NoError(t, err)
+
+		req := &contracts.UpdateUserBioRequest{UserID: user.ID, Bio: ptr("Changed once")}
+		err = c.UpdateUserBio(contracts.NewAuthenticated(req, token).WithIfMatch(tagged.ETag))
+		require.NoError(t, err)
+
+		req.Bio = ptr("Changed twice")
+		err = c.UpdateUserBio(contracts.NewAuthenticated(req, token).WithIfMatch(tagged.ETag))
+		requirePreconditionFailedError(t, err, "user", "id", user.ID)
+
+		updated, err := c.GetUserConditional(user.ID, tagged.ETag)
+		require.NoError(t, err)
+		require.Equal(t, "Changed once", *updated.Entity.Bio)
+		require.False(t, updated.LastModified.Before(tagged.LastModified))
+	})
+
+	t.Run("conditional.UpdateReview: If-Match", func(t *testing.T) {
+		reviewed := createRandomMovie(t, c)
+		user := registerRandomUser(t, c)
+		token := login(t, c, user.Email, standardPassword)
+		review, err := c.CreateReview(contracts.NewAuthenticated(&contracts.CreateReviewRequest{
+			MovieID: reviewed.ID,
+			UserID:  user.ID,
+			Rating:  7,
+			Title:   "Worth a look",
+			Content: "Reviewed once and revised later, only if nobody else changed it.",
+		}, token))
+		require.NoError(t, err)
+
+		tagged, err := c.GetReviewConditional(review.ID, "")
+		require.NoError(t, err)
+		notModified, err := c.GetReviewConditional(review.ID, tagged.ETag)
+		require.NoError(t, err)
+		require.Nil(t, notModified.Entity)
+
+		req := &contracts.UpdateReviewRequest{
+			ReviewID: review.ID,
+			UserID:   user.ID,
+			Rating:   8,
+			Title:    review.Title,
+			Content:  review.Content,
+		}
+		err = c.UpdateReview(contracts.NewAuthenticated(req, token).WithIfMatch(tagged.ETag))
+		require.NoError(t, err)
+
+		del := &contracts.DeleteReviewRequest{ReviewID: review.ID, UserID: user.ID}
+		err = c.DeleteReview(contracts.NewAuthenticated(del, token).WithIfMatch(tagged.ETag))
+		requirePreconditionFailedError(t, err, "review", "id", review.ID)
+
+		updated, err := c.GetReview(review.ID)
+		require.NoError(t, err)
+		require.Equal(t, 8, updated.Rating)
+	})
+}
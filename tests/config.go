@@ -6,12 +6,19 @@ import (
 	"github.com/boichique/movie-reviews/internal/config"
 )
 
-const testPaginationSize = 2
+const (
+	testPaginationSize = 2
+	testImageMaxSize   = 1 << 20
+	testThumbnailWidth = 50
+	testTrashRetention = time.Hour * 24 * 30
+	testPublicURL      = "http://movie-reviews.test"
+)
 
 func getConfig(pgConnString string) *config.Config {
 	return &config.Config{
-		DBUrl: pgConnString,
-		Port:  0,
+		DBUrl:     pgConnString,
+		Port:      0,
+		PublicURL: testPublicURL,
 		Jwt: config.JwtConfig{
 			Secret:           "secret",
 			AccessExpiration: time.Minute * 15,
@@ -25,6 +32,24 @@ func getConfig(pgConnString string) *config.Config {
 			DefaultSize: testPaginationSize,
 			MaxSize:     50,
 		},
+		Recommendations: config.RecommendationsConfig{
+			RefreshInterval:  time.Hour,
+			Limit:            10,
+			MinRating:        7,
+			TopChartMinVotes: 1,
+		},
+		Search: config.SearchConfig{
+			SuggestLimit:   5,
+			SuggestTimeout: time.Second,
+		},
+		Images: config.ImagesConfig{
+			MaxSize:        testImageMaxSize,
+			ThumbnailWidth: testThumbnailWidth,
+		},
+		Trash: config.TrashConfig{
+			Retention:     testTrashRetention,
+			PurgeInterval: time.Hour,
+		},
 		Local:    true,
 		LogLevel: "error",
 	}
@@ -36,6 +36,11 @@ func requireVersionMismatchError(t *testing.T, err error, subject, key string, v
 	requireAPIError(t, err, http.StatusConflict, msg)
 }
 
+func requirePreconditionFailedError(t *testing.T, err error, subject, key string, value any) {
+	msg := apperrors.PreconditionFailed(subject, key, value).Error()
+	requireAPIError(t, err, http.StatusPreconditionFailed, msg)
+}
+
 func requireAPIError(t *testing.T, err error, statusCode int, msg string) {
 	cerr, ok := err.(*client.Error)
 	require.True(t, ok, "expected client.Error")
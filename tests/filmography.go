@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func filmographyAPIChecks(t *testing.T, c *client.Client) {
+	star := createRandomStar(t, c, johnDoeToken)
+	createMovie := func(title string, year int, cast ...*contracts.MovieCreditInfo) *contracts.MovieDetails {
+		req := &contracts.CreateMovieRequest{
+			Title:       title,
+			ReleaseDate: time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Description: "A movie of the filmography",
+			GenresID:    []int{Drama.ID},
+			Cast:        cast,
+		}
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		return movie
+	}
+	later := createMovie("The Sequel", 2001, &contracts.MovieCreditInfo{StarID: star.ID, Role: "actor", Details: ptr("Hero")})
+	earlier := createMovie("The Original", 1995,
+		&contracts.MovieCreditInfo{StarID: star.ID, Role: "actor", Details: ptr("Villain")},
+		&contracts.MovieCreditInfo{StarID: star.ID, Role: "director"},
+	)
+	deleted := createMovie("The Spin-off", 2010, &contracts.MovieCreditInfo{StarID: star.ID, Role: "writer"})
+
+	reviewer := registerRandomUser(t, c)
+	token := login(t, c, reviewer.Email, standardPassword)
+	for movieID, rating := range map[int]int{later.ID: 8, earlier.ID: 6} {
+		req := &contracts.CreateReviewRequest{
+			MovieID: movieID,
+			UserID:  reviewer.ID,
+			Rating:  rating,
+			Title:   "Part of a career",
+			Content: "The star shows a different side of their talent in every role.",
+		}
+		_, err := c.CreateReview(contracts.NewAuthenticated(req, token))
+		require.NoError(t, err)
+	}
+	err := c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: deleted.ID}, johnDoeToken))
+	require.NoError(t, err)
+
+	t.Run("stars.GetStarFilmography: success", func(t *testing.T) {
+		filmography, err := c.GetStarFilmography(star.ID)
+		require.NoError(t, err)
+		require.Equal(t, star.ID, filmography.StarID)
+
+		require.Len(t, filmography.Roles, 2)
+		actor, director := filmography.Roles[0], filmography.Roles[1]
+		require.Equal(t, "actor", actor.Role)
+		require.Len(t, actor.Credits, 2)
+		require.Equal(t, earlier.ID, actor.Credits[0].MovieID)
+		require.Equal(t, "Villain", *actor.Credits[0].Details)
+		require.Equal(t, later.ID, actor.Credits[1].MovieID)
+		require.Equal(t, "Hero", *actor.Credits[1].Details)
+		require.Equal(t, 8.0, *actor.Credits[1].AvgRating)
+		require.Equal(t, "director", director.Role)
+		require.Len(t, director.Credits, 1)
+		require.Equal(t, earlier.Title, director.Credits[0].Title)
+		require.Nil(t, director.Credits[0].Details)
+
+		require.Equal(t, contracts.CareerStats{
+			MoviesCount:     2,
+			AvgRating:       ptr(7.0),
+			FirstCreditYear: ptr(1995),
+			LastCreditYear:  ptr(2001),
+		}, filmography.Stats)
+	})
+
+	t.Run("stars.GetStarFilmography: no credits", func(t *testing.T) {
+		newcomer := createRandomStar(t, c, johnDoeToken)
+		filmography, err := c.GetStarFilmography(newcomer.ID)
+		require.NoError(t, err)
+		require.Empty(t, filmography.Roles)
+		require.Equal(t, contracts.CareerStats{}, filmography.Stats)
+	})
+
+	t.Run("stars.GetStarFilmography: not found", func(t *testing.T) {
+		nonExistingID := 100000
+		_, err := c.GetStarFilmography(nonExistingID)
+		requireNotFoundError(t, err, "star", "id", nonExistingID)
+	})
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func mergesAPIChecks(t *testing.T, c *client.Client, addr string) {
+	star, duplicateStar := createRandomStar(t, c, johnDoeToken), createRandomStar(t, c, johnDoeToken)
+	createMovie := func(title string, genreID, starID int) *contracts.MovieDetails {
+		req := &contracts.CreateMovieRequest{
+			Title:       title,
+			ReleaseDate: time.Date(1999, time.March, 31, 0, 0, 0, 0, time.UTC),
+			Description: "The same movie entered twice",
+			GenresID:    []int{genreID},
+			Cast:        []*contracts.MovieCreditInfo{{StarID: starID, Role: "director"}},
+		}
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		return movie
+	}
+	movie := createMovie("The Duplicate", Action.ID, star.ID)
+	duplicate := createMovie("The Duplicate (1999)", Drama.ID, duplicateStar.ID)
+
+	// rejects redirects, so that they can be checked
+	noRedirects := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	t.Run("movies.MergeMovies: success", func(t *testing.T) {
+		reviewer := registerRandomUser(t, c)
+		reviewerToken := login(t, c, reviewer.Email, standardPassword)
+		other := registerRandomUser(t, c)
+		otherToken := login(t, c, other.Email, standardPassword)
+
+		for _, r := range []struct {
+			movieID int
+			userID  int
+			token   string
+			rating  int
+		}{
+			{movie.ID, reviewer.ID, reviewerToken, 4},
+			{duplicate.ID, reviewer.ID, reviewerToken, 8},
+			{duplicate.ID, other.ID, otherToken, 6},
+		} {
+			req := &contracts.CreateReviewRequest{
+				MovieID: r.movieID,
+				UserID:  r.userID,
+				Rating:  r.rating,
+				Title:   "Seen it twice",
+				Content: "Or maybe it was the same movie listed two times.",
+			}
+			_, err := c.CreateReview(contracts.NewAuthenticated(req, r.token))
+			require.NoError(t, err)
+		}
+
+		req := &contracts.MergeMoviesRequest{MovieID: movie.ID, DuplicateID: duplicate.ID}
+		merged, err := c.MergeMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, movie.Version+1, merged.Version)
+		require.Equal(t, []int{Action.ID, Drama.ID}, []int{merged.Genres[0].ID, merged.Genres[1].ID})
+		require.Len(t, merged.Cast, 2)
+		require.Equal(t, []int{star.ID, duplicateStar.ID}, []int{merged.Cast[0].Star.ID, merged.Cast[1].Star.ID})
+		// the newer review of the reviewer wins
+		require.Equal(t, 7.0, *merged.AvgRating)
+
+		// the client follows the redirect
+		require.Equal(t, movie.ID, getMovie(t, c, duplicate.ID).ID)
+
+		res, err := noRedirects.Get(fmt.Sprintf("%s/api/movies/%d", addr, duplicate.ID))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusMovedPermanently, res.StatusCode)
+		require.Equal(t, fmt.Sprintf("/api/movies/%d", movie.ID), res.Header.Get("Location"))
+	})
+
+	t.Run("movies.MergeMovies: bad duplicates", func(t *testing.T) {
+		req := &contracts.MergeMoviesRequest{MovieID: movie.ID, DuplicateID: movie.ID}
+		_, err := c.MergeMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "cannot be merged into itself")
+
+		req.DuplicateID = duplicate.ID
+		_, err = c.MergeMovies(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "movie", "id", duplicate.ID)
+
+		restoreReq := &contracts.RestoreFromTrashRequest{Entity: "movies", ID: duplicate.ID}
+		err = c.RestoreFromTrash(contracts.NewAuthenticated(restoreReq, adminToken))
+		requireBadRequestError(t, err, fmt.Sprintf("movie %d was merged into movie %d", duplicate.ID, movie.ID))
+	})
+
+	t.Run("movies.MergeMovies: non-editor", func(t *testing.T) {
+		user := registerRandomUser(t, c)
+		token := login(t, c, user.Email, standardPassword)
+
+		req := &contracts.MergeMoviesRequest{MovieID: movie.ID, DuplicateID: duplicate.ID}
+		_, err := c.MergeMovies(contracts.NewAuthenticated(req, token))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+
+	t.Run("stars.MergeStars: version mismatch", func(t *testing.T) {
+		req := &contracts.MergeStarsRequest{
+			StarID:           star.ID,
+			Version:          star.Version + 1,
+			DuplicateID:      duplicateStar.ID,
+			DuplicateVersion: duplicateStar.Version,
+		}
+		_, err := c.MergeStars(contracts.NewAuthenticated(req, johnDoeToken))
+		requireVersionMismatchError(t, err, "star", "id", star.ID, star.Version+1)
+
+		req.Version = star.Version
+		req.DuplicateVersion = duplicateStar.Version + 1
+		_, err = c.MergeStars(contracts.NewAuthenticated(req, johnDoeToken))
+		requireVersionMismatchError(t, err, "star", "id", duplicateStar.ID, duplicateStar.Version+1)
+	})
+
+	t.Run("stars.MergeStars: success", func(t *testing.T) {
+		req := &contracts.MergeStarsRequest{
+			StarID:           star.ID,
+			Version:          star.Version,
+			DuplicateID:      duplicateStar.ID,
+			DuplicateVersion: duplicateStar.Version,
+		}
+		merged, err := c.MergeStars(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, star.ID, merged.ID)
+		require.Equal(t, star.Version+1, merged.Version)
+
+		// both directed the movie, a single credit is left
+		current := getMovie(t, c, movie.ID)
+		require.Len(t, current.Cast, 1)
+		require.Equal(t, star.ID, current.Cast[0].Star.ID)
+
+		// the versions of the movie credit the star instead of the duplicate, so they restore its credits
+		version, err := c.GetMovieVersion(&contracts.GetMovieVersionRequest{MovieID: movie.ID, Version: current.Version})
+		require.NoError(t, err)
+		require.Len(t, version.Snapshot.Cast, 1)
+		require.Equal(t, star.ID, version.Snapshot.Cast[0].StarID)
+
+		err = c.UpdateMovie(contracts.NewAuthenticated(&contracts.UpdateMovieRequest{
+			MovieID:     movie.ID,
+			Version:     current.Version,
+			Title:       current.Title,
+			ReleaseDate: current.ReleaseDate,
+			Description: current.Description,
+			GenresID:    []int{Action.ID},
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		rollback := &contracts.RollbackMovieRequest{MovieID: movie.ID, Version: current.Version}
+		rolledBack, err := c.RollbackMovie(contracts.NewAuthenticated(rollback, johnDoeToken))
+		require.NoError(t, err)
+		require.Len(t, rolledBack.Cast, 1)
+		require.Equal(t, star.ID, rolledBack.Cast[0].Star.ID)
+
+		require.Equal(t, star.ID, getStar(t, c, duplicateStar.ID).ID)
+
+		res, err := noRedirects.Get(fmt.Sprintf("%s/api/stars/%d", addr, duplicateStar.ID))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusMovedPermanently, res.StatusCode)
+		require.Equal(t, fmt.Sprintf("/api/stars/%d", star.ID), res.Header.Get("Location"))
+	})
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func movieRolesAPIChecks(t *testing.T, c *client.Client) {
+	roleNames := func(roles []*contracts.MovieRole) []string {
+		names := make([]string, len(roles))
+		for i, role := range roles {
+			names[i] = role.Name
+		}
+		return names
+	}
+
+	t.Run("movieRoles.GetMovieRoles: catalog", func(t *testing.T) {
+		roles, err := c.GetMovieRoles(&contracts.GetMovieRolesRequest{})
+		require.NoError(t, err)
+		require.Subset(t, roleNames(roles), []string{"actor", "voice actor", "director", "writer", "cinematographer", "editor"})
+
+		roles, err = c.GetMovieRoles(&contracts.GetMovieRolesRequest{Category: ptr("cast")})
+		require.NoError(t, err)
+		require.Equal(t, []string{"actor", "voice actor"}, roleNames(roles))
+
+		_, err = c.GetMovieRoles(&contracts.GetMovieRolesRequest{Category: ptr("extras")})
+		requireBadRequestError(t, err, "role category should be cast/crew")
+	})
+
+	var stunts *contracts.MovieRole
+	t.Run("movieRoles.CreateMovieRole: success", func(t *testing.T) {
+		user := registerRandomUser(t, c)
+		token := login(t, c, user.Email, standardPassword)
+
+		req := &contracts.CreateMovieRoleRequest{Name: "  Stunt   Coordinator ", Category: "crew"}
+		_, err := c.CreateMovieRole(contracts.NewAuthenticated(req, token))
+		requireForbiddenError(t, err, "insufficient permissions")
+
+		stunts, err = c.CreateMovieRole(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, stunts.ID)
+		require.Equal(t, "stunt coordinator", stunts.Name)
+		require.Equal(t, "crew", stunts.Category)
+
+		role, err := c.GetMovieRoleByID(stunts.ID)
+		require.NoError(t, err)
+		require.Equal(t, stunts, role)
+
+		_, err = c.CreateMovieRole(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistsError(t, err, "role", "name", "stunt coordinator")
+
+		req.Name = "   "
+		_, err = c.CreateMovieRole(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "name should have at least 2 characters besides spaces")
+	})
+
+	t.Run("movieRoles.UpdateMovieRole: credits follow the name", func(t *testing.T) {
+		star := createRandomStar(t, c, johnDoeToken)
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Falling Down the Stairs",
+			ReleaseDate: time.Date(2011, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Description: "Every fall in it was planned to the inch",
+			GenresID:    []int{Action.ID},
+			Cast:        []*contracts.MovieCreditInfo{{StarID: star.ID, Role: "stunt coordinator"}},
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		req := &contracts.UpdateMovieRoleRequest{RoleID: stunts.ID, Name: "stunt director", Category: "crew"}
+		err = c.UpdateMovieRole(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		cast := getMovie(t, c, movie.ID).Cast
+		require.Len(t, cast, 1)
+		require.Equal(t, "stunt director", cast[0].Role)
+
+		// the versions of the movie follow the name too, so they can be restored
+		first, err := c.GetMovieVersion(&contracts.GetMovieVersionRequest{MovieID: movie.ID, Version: 0})
+		require.NoError(t, err)
+		require.Equal(t, "stunt director", first.Snapshot.Cast[0].Role)
+
+		err = c.UpdateMovie(contracts.NewAuthenticated(&contracts.UpdateMovieRequest{
+			MovieID:     movie.ID,
+			Version:     movie.Version,
+			Title:       movie.Title,
+			ReleaseDate: movie.ReleaseDate,
+			Description: movie.Description,
+			GenresID:    []int{Action.ID},
+		}, johnDoeToken))
+		require.NoError(t, err)
+		require.Empty(t, getMovie(t, c, movie.ID).Cast)
+
+		rolledBack, err := c.RollbackMovie(contracts.NewAuthenticated(&contracts.RollbackMovieRequest{MovieID: movie.ID, Version: 0}, johnDoeToken))
+		require.NoError(t, err)
+		require.Len(t, rolledBack.Cast, 1)
+		require.Equal(t, "stunt director", rolledBack.Cast[0].Role)
+
+		err = c.DeleteMovieRole(contracts.NewAuthenticated(&contracts.GetOrDeleteMovieRoleRequest{RoleID: stunts.ID}, johnDoeToken))
+		requireBadRequestError(t, err, "is credited in movies")
+	})
+
+	t.Run("movieRoles.CreateMovie: unknown role", func(t *testing.T) {
+		_, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Lights On",
+			ReleaseDate: time.Date(2012, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Description: "A movie about the people behind the lights",
+			GenresID:    []int{Drama.ID},
+			Cast:        []*contracts.MovieCreditInfo{{StarID: GeorgeLucas.ID, Role: "gaffer"}},
+		}, johnDoeToken))
+		requireBadRequestError(t, err, `unknown role "gaffer"`)
+	})
+
+	t.Run("movieRoles.DeleteMovieRole: success", func(t *testing.T) {
+		role, err := c.CreateMovieRole(contracts.NewAuthenticated(&contracts.CreateMovieRoleRequest{Name: "gaffer", Category: "crew"}, johnDoeToken))
+		require.NoError(t, err)
+
+		// the role is credited in an earlier version of the movie only
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Lights Out",
+			ReleaseDate: time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Description: "A movie about the people behind the lights, once again",
+			GenresID:    []int{Drama.ID},
+			Cast: []*contracts.MovieCreditInfo{
+				{StarID: GeorgeLucas.ID, Role: "director"},
+				{StarID: GeorgeLucas.ID, Role: "gaffer"},
+			},
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		err = c.UpdateMovie(contracts.NewAuthenticated(&contracts.UpdateMovieRequest{
+			MovieID:     movie.ID,
+			Version:     movie.Version,
+			Title:       movie.Title,
+			ReleaseDate: movie.ReleaseDate,
+			Description: movie.Description,
+			GenresID:    []int{Drama.ID},
+			Cast:        []*contracts.MovieCreditInfo{{StarID: GeorgeLucas.ID, Role: "director"}},
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		del := &contracts.GetOrDeleteMovieRoleRequest{RoleID: role.ID}
+		err = c.DeleteMovieRole(contracts.NewAuthenticated(del, johnDoeToken))
+		require.NoError(t, err)
+
+		_, err = c.GetMovieRoleByID(role.ID)
+		requireNotFoundError(t, err, "role", "id", role.ID)
+
+		// the credits with the deleted role are left out of a rollback
+		rolledBack, err := c.RollbackMovie(contracts.NewAuthenticated(&contracts.RollbackMovieRequest{MovieID: movie.ID, Version: 0}, johnDoeToken))
+		require.NoError(t, err)
+		require.Len(t, rolledBack.Cast, 1)
+		require.Equal(t, "director", rolledBack.Cast[0].Role)
+	})
+}
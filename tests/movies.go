@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -98,10 +99,34 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 		res, err := c.GetMovies(req)
 		require.NoError(t, err)
 
-		require.Equal(t, 2, res.Total)
+		require.Equal(t, 2, *res.Total)
 		require.Equal(t, 1, res.Page)
 		require.Equal(t, testPaginationSize, res.Size)
 		require.Equal(t, []*contracts.Movie{&StarWars.Movie, &StarTrek.Movie}, res.Items)
+		require.Nil(t, res.NextCursor)
+	})
+
+	t.Run("movies.GetMovies: facets", func(t *testing.T) {
+		res, err := c.GetMoviesWithFacets(&contracts.GetMoviesPaginatedRequest{})
+		require.NoError(t, err)
+
+		require.Equal(t, []*contracts.Movie{&StarWars.Movie, &StarTrek.Movie}, res.Items)
+		require.NotNil(t, res.Facets)
+		require.Equal(t, []*contracts.GenreFacet{
+			{GenreID: Adventure.ID, Name: Adventure.Name, Count: 2},
+			{GenreID: Drama.ID, Name: Drama.Name, Count: 1},
+		}, res.Facets.Genres)
+		require.Equal(t, []*contracts.DecadeFacet{{Decade: 1970, Count: 2}}, res.Facets.Decades)
+		require.Empty(t, res.Facets.Ratings)
+
+		res, err = c.GetMoviesWithFacets(&contracts.GetMoviesPaginatedRequest{GenreIDs: contracts.IntList{Drama.ID}})
+		require.NoError(t, err)
+
+		require.Equal(t, []*contracts.Movie{&StarWars.Movie}, res.Items)
+		require.Equal(t, []*contracts.GenreFacet{
+			{GenreID: Adventure.ID, Name: Adventure.Name, Count: 1},
+			{GenreID: Drama.ID, Name: Drama.Name, Count: 1},
+		}, res.Facets.Genres)
 	})
 
 	t.Run("movies.UpdateMovie: success", func(t *testing.T) {
@@ -147,6 +172,202 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 		}
 	})
 
+	t.Run("movies.GetMovieVersions: history and rollback", func(t *testing.T) {
+		movie := createRandomMovie(t, c)
+		req := &contracts.UpdateMovieRequest{
+			MovieID:     movie.ID,
+			Version:     movie.Version,
+			Title:       movie.Title,
+			ReleaseDate: movie.ReleaseDate,
+			Description: "Rewritten description",
+			GenresID:    []int{Drama.ID, Action.ID},
+		}
+		err := c.UpdateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		versions, err := c.GetMovieVersions(movie.ID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, 1, versions[0].Version)
+		require.Equal(t, johnDoe.ID, *versions[0].ChangedBy)
+		require.Nil(t, versions[0].RollbackOf)
+		require.Equal(t, []*contracts.FieldChange{
+			{
+				Field: "description",
+				Old:   mustMarshal(t, movie.Description),
+				New:   json.RawMessage(`"Rewritten description"`),
+			},
+			{
+				Field: "genre_ids",
+				Old:   json.RawMessage(fmt.Sprintf("[%d]", Action.ID)),
+				New:   json.RawMessage(fmt.Sprintf("[%d,%d]", Drama.ID, Action.ID)),
+			},
+		}, versions[0].Changes)
+		require.Equal(t, 0, versions[1].Version)
+		require.Empty(t, versions[1].Changes)
+
+		first, err := c.GetMovieVersion(&contracts.GetMovieVersionRequest{MovieID: movie.ID, Version: 0})
+		require.NoError(t, err)
+		require.Equal(t, movie.Description, first.Snapshot.Description)
+		require.Equal(t, []int{Action.ID}, first.Snapshot.GenreIDs)
+
+		_, err = c.GetMovieVersion(&contracts.GetMovieVersionRequest{MovieID: movie.ID, Version: 10})
+		requireNotFoundError(t, err, "movie version", "version", 10)
+
+		rollback := &contracts.RollbackMovieRequest{MovieID: movie.ID, Version: 0}
+		rolledBack, err := c.RollbackMovie(contracts.NewAuthenticated(rollback, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, 2, rolledBack.Version)
+		require.Equal(t, movie.Description, rolledBack.Description)
+		require.Equal(t, movie.Genres, rolledBack.Genres)
+		require.Equal(t, rolledBack, getMovie(t, c, movie.ID))
+
+		versions, err = c.GetMovieVersions(movie.ID)
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+		require.Equal(t, 0, *versions[0].RollbackOf)
+		require.Equal(t, []string{"description", "genre_ids"}, []string{versions[0].Changes[0].Field, versions[0].Changes[1].Field})
+
+		rollback.Version = 2
+		_, err = c.RollbackMovie(contracts.NewAuthenticated(rollback, johnDoeToken))
+		requireBadRequestError(t, err, "current version")
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		_, err = c.GetMovieVersions(movie.ID)
+		requireNotFoundError(t, err, "movie", "id", movie.ID)
+	})
+
+	t.Run("movies.RollbackMovie: deleted stars are left out", func(t *testing.T) {
+		star, kept := createRandomStar(t, c, johnDoeToken), createRandomStar(t, c, johnDoeToken)
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Casting changes",
+			ReleaseDate: time.Date(2010, time.May, 1, 0, 0, 0, 0, time.UTC),
+			Description: "A movie which loses a star",
+			Cast: []*contracts.MovieCreditInfo{
+				{StarID: star.ID, Role: "actor"},
+				{StarID: kept.ID, Role: "director"},
+			},
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		err = c.UpdateMovie(contracts.NewAuthenticated(&contracts.UpdateMovieRequest{
+			MovieID:     movie.ID,
+			Version:     movie.Version,
+			Title:       movie.Title,
+			Description: movie.Description,
+			ReleaseDate: movie.ReleaseDate,
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		err = c.DeleteStar(contracts.NewAuthenticated(&contracts.DeleteStarRequest{StarID: star.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		rollback := &contracts.RollbackMovieRequest{MovieID: movie.ID, Version: 0}
+		rolledBack, err := c.RollbackMovie(contracts.NewAuthenticated(rollback, johnDoeToken))
+		require.NoError(t, err)
+		require.Len(t, rolledBack.Cast, 1)
+		require.Equal(t, kept.ID, rolledBack.Cast[0].Star.ID)
+		require.Equal(t, "director", rolledBack.Cast[0].Role)
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("movies.PatchMovie: partial updates", func(t *testing.T) {
+		movie := createRandomMovie(t, c)
+		req := &contracts.PatchMovieRequest{
+			MovieID: movie.ID,
+			Version: ptr(movie.Version),
+			Genres:  &contracts.GenresPatch{Add: []int{Drama.ID}, Remove: []int{Action.ID}},
+			Cast:    &contracts.CastPatch{Add: []*contracts.MovieCreditInfo{{StarID: GeorgeLucas.ID, Role: "director"}}},
+			Fields:  json.RawMessage(`{"tagline": "Patched, not replaced"}`),
+		}
+		patched, err := c.PatchMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, movie.Version+1, patched.Version)
+		require.Equal(t, movie.Title, patched.Title)
+		require.Equal(t, movie.Description, patched.Description)
+		require.Equal(t, "Patched, not replaced", *patched.Tagline)
+		require.Equal(t, []*contracts.Genre{Drama}, patched.Genres)
+		require.Len(t, patched.Cast, 1)
+		require.Equal(t, GeorgeLucas.ID, patched.Cast[0].Star.ID)
+
+		// the version is checked as for full updates
+		_, err = c.PatchMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireVersionMismatchError(t, err, "movie", "id", movie.ID, movie.Version)
+
+		req = &contracts.PatchMovieRequest{
+			MovieID: movie.ID,
+			Version: ptr(patched.Version),
+			Fields:  json.RawMessage(`{"title": null}`),
+		}
+		_, err = c.PatchMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "Title: zero value")
+
+		req.Fields = json.RawMessage(`{"rating": 10}`)
+		_, err = c.PatchMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, `unknown field "rating"`)
+
+		req.Fields = json.RawMessage(`{"tagline": null}`)
+		patched, err = c.PatchMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Nil(t, patched.Tagline)
+		require.Equal(t, []*contracts.Genre{Drama}, patched.Genres)
+		require.Equal(t, patched, getMovie(t, c, movie.ID))
+	})
+
+	t.Run("movies.Translations: localized titles and descriptions", func(t *testing.T) {
+		for _, req := range []*contracts.PutMovieTranslationRequest{
+			{MovieID: StarWars.ID, Locale: "ru", Title: "Звёздные войны", Description: ptr("Давным-давно в далёкой галактике...")},
+			{MovieID: StarWars.ID, Locale: "pt-BR", Title: "Guerra nas Estrelas"},
+		} {
+			_, err := c.PutMovieTranslation(contracts.NewAuthenticated(req, johnDoeToken))
+			require.NoError(t, err)
+		}
+
+		translations, err := c.GetMovieTranslations(StarWars.ID)
+		require.NoError(t, err)
+		require.Len(t, translations, 2)
+		require.Equal(t, "pt-br", translations[0].Locale)
+		require.Equal(t, "ru", translations[1].Locale)
+
+		movie, err := c.GetMovieLocalized(&contracts.GetMovieRequest{MovieID: StarWars.ID, Locale: "ru"}, "")
+		require.NoError(t, err)
+		require.Equal(t, "Звёздные войны", movie.Title)
+		require.Equal(t, StarWars.Title, *movie.OriginalTitle)
+		require.Equal(t, "Давным-давно в далёкой галактике...", movie.Description)
+		require.Equal(t, StarWars.Version, movie.Version)
+
+		// the description falls back to the next locale on its own
+		movie, err = c.GetMovieLocalized(&contracts.GetMovieRequest{MovieID: StarWars.ID}, "pt-BR,ru;q=0.5")
+		require.NoError(t, err)
+		require.Equal(t, "Guerra nas Estrelas", movie.Title)
+		require.Equal(t, "Давным-давно в далёкой галактике...", movie.Description)
+
+		movie, err = c.GetMovieLocalized(&contracts.GetMovieRequest{MovieID: StarWars.ID}, "fr")
+		require.NoError(t, err)
+		require.Equal(t, StarWars, movie)
+
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{Locale: "ru"})
+		require.NoError(t, err)
+		require.Equal(t, "Звёздные войны", res.Items[0].Title)
+		require.Equal(t, StarTrek.Title, res.Items[1].Title)
+		require.Nil(t, res.Items[1].OriginalTitle)
+
+		// locales are canonicalized, so any spelling of the tag finds the translation
+		for _, locale := range []string{"ru", "PT_br"} {
+			req := &contracts.DeleteMovieTranslationRequest{MovieID: StarWars.ID, Locale: locale}
+			err = c.DeleteMovieTranslation(contracts.NewAuthenticated(req, johnDoeToken))
+			require.NoError(t, err)
+		}
+
+		req := &contracts.DeleteMovieTranslationRequest{MovieID: StarWars.ID, Locale: "ru"}
+		err = c.DeleteMovieTranslation(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "movie translation", "locale", "ru")
+	})
+
 	t.Run("movies.DeleteMovie: success", func(t *testing.T) {
 		movie := createRandomMovie(t, c)
 		err := c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
@@ -163,7 +384,7 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 		res, err := c.GetStars(req)
 		require.NoError(t, err)
 
-		require.Equal(t, 2, res.Total)
+		require.Equal(t, 2, *res.Total)
 		require.Equal(t, 1, res.Page)
 		require.Equal(t, testPaginationSize, res.Size)
 		require.Equal(t, []*contracts.Star{&GeorgeLucas.Star, &MarkHamill.Star}, res.Items)
@@ -176,7 +397,7 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 		res, err := c.GetMovies(req)
 		require.NoError(t, err)
 
-		require.Equal(t, 1, res.Total)
+		require.Equal(t, 1, *res.Total)
 		require.Equal(t, 1, res.Page)
 		require.Equal(t, testPaginationSize, res.Size)
 		require.Equal(t, []*contracts.Movie{&StarWars.Movie}, res.Items)
@@ -189,11 +410,318 @@ func moviesAPIChecks(t *testing.T, c *client.Client) {
 		res, err := c.GetMovies(req)
 		require.NoError(t, err)
 
-		require.Equal(t, 1, res.Total)
+		require.Equal(t, 1, *res.Total)
 		require.Equal(t, 1, res.Page)
 		require.Equal(t, testPaginationSize, res.Size)
 		require.Equal(t, []*contracts.Movie{&StarWars.Movie}, res.Items)
 	})
+
+	t.Run("movies.GetMovies: forgiving search", func(t *testing.T) {
+		cases := []struct {
+			name string
+			q    string
+			exp  []*contracts.Movie
+		}{
+			{"plain text", "star wars", []*contracts.Movie{&StarWars.Movie}},
+			{"operators", "godfather:", nil},
+			{"prefixes", "sta", []*contracts.Movie{&StarWars.Movie, &StarTrek.Movie}},
+			{"typo", "star wors", []*contracts.Movie{&StarWars.Movie}},
+		}
+
+		for _, cc := range cases {
+			t.Run(cc.name, func(t *testing.T) {
+				res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{SearchTerm: ptr(cc.q)})
+				require.NoError(t, err)
+				require.ElementsMatch(t, cc.exp, res.Items)
+			})
+		}
+	})
+
+	t.Run("movies.GetMovies: exact title first", func(t *testing.T) {
+		// the sequel mentions every search term more often, so it outranks the original by text relevance alone
+		sequel, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Star Trek: The Motion Picture II",
+			ReleaseDate: time.Date(1982, time.June, 4, 0, 0, 0, 0, time.UTC),
+			Description: "The motion picture sequel to Star Trek: The Motion Picture, the star trek picture in motion again",
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{SearchTerm: ptr(StarTrek.Title)})
+		require.NoError(t, err)
+		require.Len(t, res.Items, 2)
+		require.Equal(t, StarTrek.ID, res.Items[0].ID)
+		require.Equal(t, sequel.ID, res.Items[1].ID)
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: sequel.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("movies.GetMovies: language hint", func(t *testing.T) {
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Война и мир",
+			ReleaseDate: time.Date(1966, time.March, 14, 0, 0, 0, 0, time.UTC),
+			Description: "Экранизация одноимённого романа Льва Толстого",
+			Language:    "ru",
+		}, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, "ru", movie.Language)
+
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{SearchTerm: ptr("романы"), Lang: "ru"})
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.Movie{&movie.Movie}, res.Items)
+
+		res, err = c.GetMovies(&contracts.GetMoviesPaginatedRequest{SearchTerm: ptr("романы")})
+		require.NoError(t, err)
+		require.Empty(t, res.Items)
+
+		_, err = c.GetMovies(&contracts.GetMoviesPaginatedRequest{SearchTerm: ptr("романы"), Lang: "russian"})
+		requireBadRequestError(t, err, "lang")
+
+		// an update without a language keeps the current one
+		err = c.UpdateMovie(contracts.NewAuthenticated(&contracts.UpdateMovieRequest{
+			MovieID:     movie.ID,
+			Version:     movie.Version,
+			Title:       movie.Title,
+			Description: movie.Description,
+			ReleaseDate: movie.ReleaseDate,
+		}, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, "ru", getMovie(t, c, movie.ID).Language)
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("movies.GetMovies: metadata", func(t *testing.T) {
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Amélie",
+			ReleaseDate: time.Date(2001, time.April, 25, 0, 0, 0, 0, time.UTC),
+			Description: "A shy waitress decides to change the lives of those around her",
+			Language:    "fr",
+			MovieMetadata: contracts.MovieMetadata{
+				RuntimeMinutes:  ptr(122),
+				Countries:       []string{"FR", "DE"},
+				SpokenLanguages: []string{"fr", "ru"},
+				AgeRating:       ptr("R"),
+				Tagline:         ptr("She'll change your life."),
+			},
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		got := getMovie(t, c, movie.ID)
+		require.Equal(t, 122, *got.RuntimeMinutes)
+		require.Equal(t, []string{"FR", "DE"}, got.Countries)
+		require.Equal(t, []string{"fr", "ru"}, got.SpokenLanguages)
+		require.Equal(t, "R", *got.AgeRating)
+		require.Equal(t, "She'll change your life.", *got.Tagline)
+
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{Country: ptr("DE"), MinRuntime: ptr(120)})
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.Movie{&movie.Movie}, res.Items)
+
+		res, err = c.GetMovies(&contracts.GetMoviesPaginatedRequest{SpokenLanguage: ptr("ru"), AgeRatings: contracts.StringList{"PG", "R"}})
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.Movie{&movie.Movie}, res.Items)
+
+		res, err = c.GetMovies(&contracts.GetMoviesPaginatedRequest{Country: ptr("FR"), MaxRuntime: ptr(90)})
+		require.NoError(t, err)
+		require.Empty(t, res.Items)
+
+		_, err = c.GetMovies(&contracts.GetMoviesPaginatedRequest{Country: ptr("fra")})
+		requireBadRequestError(t, err, "country")
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("movies.GetMovieByExternalID: success", func(t *testing.T) {
+		req := &contracts.CreateMovieRequest{
+			Title:       "The Empire Strikes Back",
+			ReleaseDate: time.Date(1980, time.May, 21, 0, 0, 0, 0, time.UTC),
+			Description: "The Rebels are scattered after the Empire attacks their base on Hoth",
+			ExternalIDs: []*contracts.ExternalID{{Source: "imdb", ID: "tt0080684"}},
+		}
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		m, err := c.GetMovieByExternalID("IMDB", "tt0080684")
+		require.NoError(t, err)
+		require.Equal(t, movie.ID, m.ID)
+		require.Equal(t, []*contracts.ExternalID{{Source: "imdb", ID: "tt0080684"}}, m.ExternalIDs)
+
+		_, err = c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistsError(t, err, "movie", "imdb", "tt0080684")
+
+		req.ExternalIDs = []*contracts.ExternalID{{Source: "tmdb", ID: "1891"}, {Source: "TMDB", ID: "1892"}}
+		_, err = c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, `more than one external id of source "tmdb"`)
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		_, err = c.GetMovieByExternalID("imdb", "tt0080684")
+		requireNotFoundError(t, err, "movie", "imdb", "tt0080684")
+
+		// the deleted movie gives its external ids up
+		req.ExternalIDs = []*contracts.ExternalID{{Source: "imdb", ID: "tt0080684"}}
+		recreated, err := c.CreateMovie(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		m, err = c.GetMovieByExternalID("imdb", "tt0080684")
+		require.NoError(t, err)
+		require.Equal(t, recreated.ID, m.ID)
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: recreated.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("movies.PutMovieExternalID: success", func(t *testing.T) {
+		movie, other := createRandomMovie(t, c), createRandomMovie(t, c)
+		req := &contracts.PutMovieExternalIDRequest{
+			MovieID:              movie.ID,
+			PutExternalIDRequest: contracts.PutExternalIDRequest{Source: "TMDB", ExternalID: "1891"},
+		}
+		id, err := c.PutMovieExternalID(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, &contracts.ExternalID{Source: "tmdb", ID: "1891"}, id)
+
+		// the id replaces the one of the same source
+		req.ExternalID = "1892"
+		_, err = c.PutMovieExternalID(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.ExternalID{{Source: "tmdb", ID: "1892"}}, getMovie(t, c, movie.ID).ExternalIDs)
+
+		m, err := c.GetMovieByExternalID("tmdb", "1892")
+		require.NoError(t, err)
+		require.Equal(t, movie.ID, m.ID)
+
+		req.MovieID = other.ID
+		_, err = c.PutMovieExternalID(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistsError(t, err, "movie", "tmdb", "1892")
+
+		for _, movieID := range []int{movie.ID, other.ID} {
+			err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movieID}, johnDoeToken))
+			require.NoError(t, err)
+		}
+
+		_, err = c.PutMovieExternalID(contracts.NewAuthenticated(req, johnDoeToken))
+		requireNotFoundError(t, err, "movie", "id", other.ID)
+	})
+
+	t.Run("movies.UploadMoviePoster: success", func(t *testing.T) {
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Return of the Jedi",
+			ReleaseDate: time.Date(1983, time.May, 25, 0, 0, 0, 0, time.UTC),
+			Description: "Luke Skywalker attempts to bring his father back to the light side of the Force",
+		}, johnDoeToken))
+		require.NoError(t, err)
+		req := &contracts.UploadMoviePosterRequest{MovieID: movie.ID}
+
+		poster, err := c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken), pngImage(t, 200, 300))
+		require.NoError(t, err)
+		require.Equal(t, 200, getImage(t, poster.URL).Bounds().Dx())
+		thumbnail := getImage(t, poster.ThumbnailURL).Bounds()
+		require.Equal(t, testThumbnailWidth, thumbnail.Dx())
+		require.Equal(t, testThumbnailWidth*3/2, thumbnail.Dy())
+
+		m := getMovie(t, c, movie.ID)
+		require.Equal(t, poster, m.Poster)
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{SearchTerm: ptr("Return of the Jedi")})
+		require.NoError(t, err)
+		require.Equal(t, poster, res.Items[0].Poster)
+
+		// the new poster replaces the previous one
+		next, err := c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken), pngImage(t, 20, 30))
+		require.NoError(t, err)
+		require.Nil(t, getImage(t, poster.URL))
+		require.Equal(t, 20, getImage(t, next.ThumbnailURL).Bounds().Dx())
+
+		_, err = c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken), []byte("not an image"))
+		requireBadRequestError(t, err, "unsupported image type")
+
+		_, err = c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken), make([]byte, testImageMaxSize+1))
+		requireBadRequestError(t, err, "image is larger than")
+
+		err = c.DeleteMoviePoster(contracts.NewAuthenticated(&contracts.DeleteMoviePosterRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+		require.Nil(t, getMovie(t, c, movie.ID).Poster)
+		require.Nil(t, getImage(t, next.URL))
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		_, err = c.UploadMoviePoster(contracts.NewAuthenticated(req, johnDoeToken), pngImage(t, 20, 30))
+		requireNotFoundError(t, err, "movie", "id", movie.ID)
+	})
+
+	t.Run("movies.GetMovies: filtered", func(t *testing.T) {
+		cases := []struct {
+			name string
+			req  *contracts.GetMoviesPaginatedRequest
+			exp  []*contracts.Movie
+		}{
+			{
+				name: "any of genres",
+				req: &contracts.GetMoviesPaginatedRequest{
+					GenreIDs: contracts.IntList{Action.ID, Adventure.ID},
+				},
+				exp: []*contracts.Movie{&StarWars.Movie, &StarTrek.Movie},
+			},
+			{
+				name: "all of genres",
+				req: &contracts.GetMoviesPaginatedRequest{
+					GenreIDs:    contracts.IntList{Action.ID, Adventure.ID},
+					GenresMatch: ptr("all"),
+				},
+				exp: []*contracts.Movie{&StarWars.Movie},
+			},
+			{
+				name: "release date range",
+				req: &contracts.GetMoviesPaginatedRequest{
+					ReleasedFrom: ptr(time.Date(1978, time.January, 1, 0, 0, 0, 0, time.UTC)),
+					ReleasedTo:   ptr(time.Date(1979, time.December, 31, 0, 0, 0, 0, time.UTC)),
+				},
+				exp: []*contracts.Movie{&StarTrek.Movie},
+			},
+			{
+				name: "directed by George Lucas",
+				req: &contracts.GetMoviesPaginatedRequest{
+					StarID:   ptr(GeorgeLucas.ID),
+					StarRole: ptr("director"),
+				},
+				exp: []*contracts.Movie{&StarWars.Movie},
+			},
+			{
+				name: "George Lucas as actor",
+				req: &contracts.GetMoviesPaginatedRequest{
+					StarID:   ptr(GeorgeLucas.ID),
+					StarRole: ptr("actor"),
+				},
+				exp: nil,
+			},
+		}
+
+		for _, cc := range cases {
+			t.Run(cc.name, func(t *testing.T) {
+				res, err := c.GetMovies(cc.req)
+				require.NoError(t, err)
+
+				require.Equal(t, len(cc.exp), *res.Total)
+				require.Equal(t, cc.exp, res.Items)
+			})
+		}
+	})
+
+	t.Run("movies.GetMovies: exclude reviewed without token", func(t *testing.T) {
+		_, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{ExcludeReviewed: true})
+		requireUnauthorizedError(t, err, "excludeReviewed requires authentication")
+	})
+
+	t.Run("movies.GetMovies: unknown role", func(t *testing.T) {
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{StarRole: ptr("gaffer")})
+		require.NoError(t, err)
+		require.Empty(t, res.Items)
+	})
 }
 
 func getMovie(t *testing.T, c *client.Client, id int) *contracts.MovieDetails {
@@ -208,6 +736,12 @@ func getMovie(t *testing.T, c *client.Client, id int) *contracts.MovieDetails {
 	return m
 }
 
+func mustMarshal(t *testing.T, v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
 func createRandomMovie(t *testing.T, c *client.Client) *contracts.MovieDetails {
 	r := rand.Intn(10000)
 
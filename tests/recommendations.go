@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/boichique/movie-reviews/internal/modules/recommendations"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+func recommendationsAPIChecks(t *testing.T, c *client.Client, cfg *config.Config) {
+	newcomer := registerRandomUser(t, c)
+	newcomerToken := login(t, c, newcomer.Email, standardPassword)
+
+	t.Run("recommendations.GetRecommendations: top chart for a cold-start user", func(t *testing.T) {
+		req := &contracts.GetRecommendationsRequest{UserID: newcomer.ID}
+		recommendations, err := c.GetRecommendations(contracts.NewAuthenticated(req, newcomerToken))
+		require.NoError(t, err)
+
+		require.Len(t, recommendations, 1)
+		require.Equal(t, StarWars.ID, recommendations[0].Movie.ID)
+		require.Equal(t, "top_chart", recommendations[0].Source)
+		require.NotZero(t, recommendations[0].Score)
+	})
+
+	t.Run("recommendations.GetRecommendations: reviewed movies are excluded", func(t *testing.T) {
+		review, err := c.CreateReview(contracts.NewAuthenticated(&contracts.CreateReviewRequest{
+			MovieID: StarWars.ID,
+			UserID:  newcomer.ID,
+			Rating:  7,
+			Title:   "Good enough",
+			Content: "Not my favorite space opera, but it holds up surprisingly well.",
+		}, newcomerToken))
+		require.NoError(t, err)
+
+		req := &contracts.GetRecommendationsRequest{UserID: newcomer.ID}
+		recommendations, err := c.GetRecommendations(contracts.NewAuthenticated(req, newcomerToken))
+		require.NoError(t, err)
+		require.Empty(t, recommendations)
+
+		err = c.DeleteReview(contracts.NewAuthenticated(&contracts.DeleteReviewRequest{
+			ReviewID: review.ID,
+			UserID:   newcomer.ID,
+		}, newcomerToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("recommendations.GetRecommendations: movies liked by the same people after a refresh", func(t *testing.T) {
+		first, second := createRandomMovie(t, c), createRandomMovie(t, c)
+		fan, follower := registerRandomUser(t, c), registerRandomUser(t, c)
+		fanToken, followerToken := login(t, c, fan.Email, standardPassword), login(t, c, follower.Email, standardPassword)
+
+		like := func(user *contracts.User, token string, movieID int) {
+			_, err := c.CreateReview(contracts.NewAuthenticated(&contracts.CreateReviewRequest{
+				MovieID: movieID,
+				UserID:  user.ID,
+				Rating:  8,
+				Title:   "Loved it",
+				Content: "A thoughtful film that rewards a second viewing more than the first.",
+			}, token))
+			require.NoError(t, err)
+		}
+		like(fan, fanToken, first.ID)
+		like(fan, fanToken, second.ID)
+		like(follower, followerToken, first.ID)
+
+		db, err := pgxpool.New(context.Background(), cfg.DBUrl)
+		require.NoError(t, err)
+		defer db.Close()
+		err = recommendations.NewModule(db, cfg.Recommendations).Service.Refresh(context.Background())
+		require.NoError(t, err)
+
+		// one shared like over sqrt(2 likes of the first * 1 like of the second)
+		req := &contracts.GetRecommendationsRequest{UserID: follower.ID}
+		got, err := c.GetRecommendations(contracts.NewAuthenticated(req, followerToken))
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, second.ID, got[0].Movie.ID)
+		require.Equal(t, "similar_movies", got[0].Source)
+		require.InDelta(t, 1/math.Sqrt2, got[0].Score, 1e-9)
+
+		// the fan has seen everything similar and falls back to the top chart
+		req = &contracts.GetRecommendationsRequest{UserID: fan.ID}
+		got, err = c.GetRecommendations(contracts.NewAuthenticated(req, fanToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, got)
+		for _, recommendation := range got {
+			require.Equal(t, "top_chart", recommendation.Source)
+			require.NotContains(t, []int{first.ID, second.ID}, recommendation.Movie.ID)
+		}
+	})
+
+	t.Run("recommendations.GetRecommendations: another user", func(t *testing.T) {
+		req := &contracts.GetRecommendationsRequest{UserID: newcomer.ID}
+		_, err := c.GetRecommendations(contracts.NewAuthenticated(req, johnDoeToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+}
@@ -122,6 +122,13 @@ func reviewsAPIChecks(t *testing.T, c *client.Client) {
 				},
 				exp: []*contracts.Review{review1, review3},
 			},
+			{
+				req: &contracts.GetReviewsRequest{
+					MovieID: ptr(StarWars.ID),
+					Sort:    ptr("rating:asc"),
+				},
+				exp: []*contracts.Review{review2, review1},
+			},
 		}
 
 		for _, cc := range cases {
@@ -165,6 +172,36 @@ func reviewsAPIChecks(t *testing.T, c *client.Client) {
 		requireRatingEqual(t, 8.0, *res.Items[1].AvgRating)
 	})
 
+	t.Run("movies.GetMovies: sort by rating ascending", func(t *testing.T) {
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{
+			Sort: ptr("rating:asc,title:desc"),
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, StarTrek.ID, res.Items[0].ID)
+		require.Equal(t, StarWars.ID, res.Items[1].ID)
+	})
+
+	t.Run("movies.GetMovies: rating range", func(t *testing.T) {
+		res, err := c.GetMovies(&contracts.GetMoviesPaginatedRequest{
+			MinRating: ptr(9.0),
+			MaxRating: ptr(10.0),
+		})
+		require.NoError(t, err)
+
+		require.Len(t, res.Items, 1)
+		require.Equal(t, StarWars.ID, res.Items[0].ID)
+	})
+
+	t.Run("movies.GetMovies: exclude reviewed", func(t *testing.T) {
+		req := &contracts.GetMoviesPaginatedRequest{ExcludeReviewed: true}
+		res, err := c.GetMoviesAuthenticated(contracts.NewAuthenticated(req, reviewer2Token))
+		require.NoError(t, err)
+
+		require.Len(t, res.Items, 1)
+		require.Equal(t, StarTrek.ID, res.Items[0].ID)
+	})
+
 	t.Run("reviews.DeleteReview: not found", func(t *testing.T) {
 		nonExistingID := 10000
 		req := &contracts.DeleteReviewRequest{
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/boichique/movie-reviews/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+const testBucket = "movie-reviews"
+
+// s3StandIn is an in-memory stand-in for an S3 compatible service. It only checks that requests are signed,
+// the signature itself is checked against the AWS examples by the tests of the storage package.
+type s3StandIn struct {
+	*httptest.Server
+
+	mx      sync.RWMutex
+	objects map[string][]byte
+}
+
+func newS3StandIn() *s3StandIn {
+	s := &s3StandIn{objects: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *s3StandIn) config() config.StorageConfig {
+	return config.StorageConfig{
+		Driver: "s3",
+		S3: config.S3Config{
+			Endpoint:  s.URL,
+			Region:    "us-east-1",
+			Bucket:    testBucket,
+			AccessKey: "access",
+			SecretKey: "secret",
+		},
+	}
+}
+
+func (s *s3StandIn) handle(w http.ResponseWriter, r *http.Request) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		s.objects[r.URL.Path] = data
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// getImage downloads and decodes the image at the url, nil if there is no such image.
+func getImage(t *testing.T, url string) image.Image {
+	res, err := http.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	img, _, err := image.Decode(res.Body)
+	require.NoError(t, err)
+	return img
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func searchAPIChecks(t *testing.T, c *client.Client) {
+	t.Run("search.Suggest: movies by prefix", func(t *testing.T) {
+		suggestions, err := c.Suggest(&contracts.SuggestRequest{SearchTerm: "sta"})
+		require.NoError(t, err)
+
+		require.Equal(t, []*contracts.Suggestion{
+			{Type: "movie", ID: StarWars.ID, Text: StarWars.Title, Year: ptr(1977)},
+			{Type: "movie", ID: StarTrek.ID, Text: StarTrek.Title, Year: ptr(1979)},
+		}, suggestions)
+	})
+
+	t.Run("search.Suggest: stars with a typo", func(t *testing.T) {
+		suggestions, err := c.Suggest(&contracts.SuggestRequest{SearchTerm: "Wiliam"})
+		require.NoError(t, err)
+
+		require.Equal(t, []*contracts.Suggestion{
+			{Type: "star", ID: WilliamShatner.ID, Text: "William Shatner"},
+		}, suggestions)
+	})
+
+	t.Run("search.Suggest: nothing found", func(t *testing.T) {
+		suggestions, err := c.Suggest(&contracts.SuggestRequest{SearchTerm: "zzzzzz"})
+		require.NoError(t, err)
+		require.Empty(t, suggestions)
+	})
+
+	t.Run("search.Suggest: empty term", func(t *testing.T) {
+		_, err := c.Suggest(&contracts.SuggestRequest{})
+		requireBadRequestError(t, err, "SearchTerm")
+	})
+
+	t.Run("search.Search: grouped by type", func(t *testing.T) {
+		res, err := c.Search(&contracts.SearchRequest{SearchTerm: "Trainspotting"})
+		require.NoError(t, err)
+
+		require.Equal(t, []*contracts.Star{&EvanMcGregor.Star}, res.Stars.Items)
+		require.Equal(t, 1, *res.Stars.Total)
+		require.Empty(t, res.Movies.Items)
+		require.Empty(t, res.Reviews.Items)
+		require.Empty(t, res.Users.Items)
+	})
+
+	t.Run("search.Search: selected types", func(t *testing.T) {
+		res, err := c.Search(&contracts.SearchRequest{
+			SearchTerm: "Kirk",
+			Types:      contracts.StringList{"movies"},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []*contracts.Movie{&StarTrek.Movie}, res.Movies.Items)
+		require.Nil(t, res.Stars)
+		require.Nil(t, res.Reviews)
+		require.Nil(t, res.Users)
+	})
+
+	t.Run("search.Search: movies in their own language", func(t *testing.T) {
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Война и мир",
+			ReleaseDate: time.Date(1966, time.March, 14, 0, 0, 0, 0, time.UTC),
+			Description: "Экранизация одноимённого романа Льва Толстого",
+			Language:    "ru",
+		}, johnDoeToken))
+		require.NoError(t, err)
+
+		res, err := c.Search(&contracts.SearchRequest{
+			SearchTerm: "романы",
+			Types:      contracts.StringList{"movies"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.Movie{&movie.Movie}, res.Movies.Items)
+
+		err = c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("search.Search: users", func(t *testing.T) {
+		res, err := c.Search(&contracts.SearchRequest{
+			SearchTerm: "admin",
+			Types:      contracts.StringList{"users"},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, res.Users.Items, 1)
+		require.Equal(t, "admin", res.Users.Items[0].Username)
+	})
+
+	t.Run("search.Search: unknown type", func(t *testing.T) {
+		_, err := c.Search(&contracts.SearchRequest{
+			SearchTerm: "Kirk",
+			Types:      contracts.StringList{"genres"},
+		})
+		requireBadRequestError(t, err, `unknown search type "genres"`)
+
+		// the types are checked before any of them is searched
+		_, err = c.Search(&contracts.SearchRequest{
+			SearchTerm: "Kirk",
+			Types:      contracts.StringList{"movies", "genres"},
+		})
+		requireBadRequestError(t, err, `unknown search type "genres"`)
+	})
+
+	t.Run("search.Search: cursor for several types", func(t *testing.T) {
+		req := &contracts.SearchRequest{SearchTerm: "Kirk"}
+		req.SetAfter("cursor")
+		_, err := c.Search(req)
+		requireBadRequestError(t, err, "cursors require a single type")
+	})
+}
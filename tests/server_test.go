@@ -18,7 +18,11 @@ func TestServer(t *testing.T) {
 }
 
 func runServer(t *testing.T, pgConnString string) {
+	s3 := newS3StandIn()
+	defer s3.Close()
+
 	cfg := getConfig(pgConnString)
+	cfg.Storage = s3.config()
 
 	srv, err := server.New(context.Background(), cfg)
 	require.NoError(t, err)
@@ -59,4 +63,14 @@ func tests(t *testing.T, port int, cfg *config.Config) {
 	starsAPIChecks(t, c)
 	moviesAPIChecks(t, c)
 	reviewsAPIChecks(t, c)
+	recommendationsAPIChecks(t, c, cfg)
+	searchAPIChecks(t, c)
+	collectionsAPIChecks(t, c)
+	trashAPIChecks(t, c)
+	mergesAPIChecks(t, c, addr)
+	conditionalAPIChecks(t, c, addr)
+	filmographyAPIChecks(t, c)
+	starSearchAPIChecks(t, c)
+	starNamesAPIChecks(t, c)
+	movieRolesAPIChecks(t, c)
 }
@@ -1,8 +1,10 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -98,7 +100,7 @@ func starsAPIChecks(t *testing.T, c *client.Client) {
 		res, err := c.GetStars(req)
 		require.NoError(t, err)
 
-		require.Equal(t, 4, res.Total)
+		require.Equal(t, 4, *res.Total)
 		require.Equal(t, 1, res.Page)
 		require.Equal(t, testPaginationSize, res.Size)
 		require.Equal(t, []*contracts.Star{&GeorgeLucas.Star, &MarkHamill.Star}, res.Items)
@@ -107,12 +109,69 @@ func starsAPIChecks(t *testing.T, c *client.Client) {
 		res, err = c.GetStars(req)
 		require.NoError(t, err)
 
-		require.Equal(t, 4, res.Total)
+		require.Equal(t, 4, *res.Total)
 		require.Equal(t, 2, res.Page)
 		require.Equal(t, testPaginationSize, res.Size)
 		require.Equal(t, []*contracts.Star{&EvanMcGregor.Star, &WilliamShatner.Star}, res.Items)
 	})
 
+	t.Run("stars.GetStars: sorted", func(t *testing.T) {
+		req := &contracts.GetStarsPaginatedRequest{
+			Sort: ptr("last_name:desc"),
+		}
+		res, err := c.GetStars(req)
+		require.NoError(t, err)
+
+		require.Equal(t, 4, *res.Total)
+		require.Equal(t, []*contracts.Star{&WilliamShatner.Star, &EvanMcGregor.Star}, res.Items)
+	})
+
+	t.Run("stars.GetStars: unknown sort field", func(t *testing.T) {
+		req := &contracts.GetStarsPaginatedRequest{
+			Sort: ptr("bio:asc"),
+		}
+		_, err := c.GetStars(req)
+		requireBadRequestError(t, err, `unknown sort field "bio"`)
+	})
+
+	t.Run("stars.GetStars: cursors", func(t *testing.T) {
+		req := &contracts.GetStarsPaginatedRequest{
+			Sort: ptr("last_name:desc"),
+		}
+		res, err := c.GetStars(req)
+		require.NoError(t, err)
+		require.Nil(t, res.PrevCursor)
+		require.NotNil(t, res.NextCursor)
+
+		req.SetAfter(*res.NextCursor)
+		res, err = c.GetStars(req)
+		require.NoError(t, err)
+		require.Nil(t, res.Total)
+		require.Nil(t, res.NextCursor)
+		require.NotNil(t, res.PrevCursor)
+		require.Equal(t, []*contracts.Star{&MarkHamill.Star, &GeorgeLucas.Star}, res.Items)
+
+		req.After, req.Before = nil, res.PrevCursor
+		res, err = c.GetStars(req)
+		require.NoError(t, err)
+		require.Nil(t, res.PrevCursor)
+		require.Equal(t, []*contracts.Star{&WilliamShatner.Star, &EvanMcGregor.Star}, res.Items)
+
+		all, err := client.Paginate(&contracts.GetStarsPaginatedRequest{}, c.GetStars)
+		require.NoError(t, err)
+		require.Len(t, all, 4)
+	})
+
+	t.Run("stars.GetStars: cursor of another sort", func(t *testing.T) {
+		res, err := c.GetStars(&contracts.GetStarsPaginatedRequest{Sort: ptr("last_name:desc")})
+		require.NoError(t, err)
+
+		req := &contracts.GetStarsPaginatedRequest{}
+		req.SetAfter(*res.NextCursor)
+		_, err = c.GetStars(req)
+		requireBadRequestError(t, err, "cursor does not match the requested sort")
+	})
+
 	t.Run("stars.UpdateStar: success", func(t *testing.T) {
 		req := &contracts.UpdateStarRequest{
 			StarID:     EvanMcGregor.ID,
@@ -130,6 +189,52 @@ func starsAPIChecks(t *testing.T, c *client.Client) {
 
 		EvanMcGregor = getStar(t, c, EvanMcGregor.ID)
 		require.Equal(t, req.Bio, EvanMcGregor.Bio)
+		require.Equal(t, req.Version+1, EvanMcGregor.Version)
+
+		// Concurrent update should fail
+		err = c.UpdateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireVersionMismatchError(t, err, "star", "id", req.StarID, req.Version)
+	})
+
+	t.Run("stars.UpdateStar: validation", func(t *testing.T) {
+		req := &contracts.UpdateStarRequest{
+			StarID:    EvanMcGregor.ID,
+			Version:   EvanMcGregor.Version,
+			FirstName: EvanMcGregor.FirstName,
+			LastName:  strings.Repeat("McGregor", 10),
+			BirthDate: EvanMcGregor.BirthDate,
+		}
+		err := c.UpdateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "LastName: greater than max")
+
+		req.LastName, req.BirthDate = EvanMcGregor.LastName, time.Time{}
+		err = c.UpdateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "BirthDate: zero value")
+	})
+
+	t.Run("stars.PatchStar: success", func(t *testing.T) {
+		star := createRandomStar(t, c, johnDoeToken)
+		req := &contracts.PatchStarRequest{
+			StarID:  star.ID,
+			Version: ptr(star.Version),
+			Fields:  json.RawMessage(`{"bio": "Known for a single role", "middle_name": null}`),
+		}
+		patched, err := c.PatchStar(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, star.FirstName, patched.FirstName)
+		require.Equal(t, star.BirthPlace, patched.BirthPlace)
+		require.Nil(t, patched.MiddleName)
+		require.Equal(t, "Known for a single role", *patched.Bio)
+		require.Equal(t, star.Version+1, patched.Version)
+		require.Equal(t, patched, getStar(t, c, star.ID))
+
+		_, err = c.PatchStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireVersionMismatchError(t, err, "star", "id", star.ID, star.Version)
+
+		req.Version = ptr(patched.Version)
+		req.Fields = json.RawMessage(`{"birth_place": null, "birth_date": null}`)
+		_, err = c.PatchStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "BirthDate: zero value")
 	})
 
 	t.Run("stars.DeleteStar: success", func(t *testing.T) {
@@ -140,6 +245,298 @@ func starsAPIChecks(t *testing.T, c *client.Client) {
 		star = getStar(t, c, star.ID)
 		require.Nil(t, star)
 	})
+
+	t.Run("stars.UploadStarPhoto: success", func(t *testing.T) {
+		star := createRandomStar(t, c, johnDoeToken)
+		req := &contracts.UploadStarPhotoRequest{StarID: star.ID}
+
+		photo, err := c.UploadStarPhoto(contracts.NewAuthenticated(req, johnDoeToken), pngImage(t, 100, 100))
+		require.NoError(t, err)
+		require.Equal(t, testThumbnailWidth, getImage(t, photo.ThumbnailURL).Bounds().Dx())
+		require.Equal(t, photo, getStar(t, c, star.ID).Photo)
+
+		err = c.DeleteStarPhoto(contracts.NewAuthenticated(&contracts.DeleteStarPhotoRequest{StarID: star.ID}, johnDoeToken))
+		require.NoError(t, err)
+		require.Nil(t, getStar(t, c, star.ID).Photo)
+		require.Nil(t, getImage(t, photo.ThumbnailURL))
+
+		err = c.DeleteStar(contracts.NewAuthenticated(&contracts.DeleteStarRequest{StarID: star.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("stars.GetStarByExternalID: success", func(t *testing.T) {
+		req := &contracts.CreateStarRequest{
+			FirstName:   "Carrie",
+			LastName:    "Fisher",
+			BirthDate:   time.Date(1956, time.October, 21, 0, 0, 0, 0, time.UTC),
+			ExternalIDs: []*contracts.ExternalID{{Source: "IMDb", ID: "nm0000402"}},
+		}
+		star, err := c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, []*contracts.ExternalID{{Source: "imdb", ID: "nm0000402"}}, star.ExternalIDs)
+
+		s, err := c.GetStarByExternalID("imdb", "nm0000402")
+		require.NoError(t, err)
+		require.Equal(t, star, s)
+
+		_, err = c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistsError(t, err, "star", "imdb", "nm0000402")
+
+		err = c.DeleteStar(contracts.NewAuthenticated(&contracts.DeleteStarRequest{StarID: star.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		_, err = c.GetStarByExternalID("imdb", "nm0000402")
+		requireNotFoundError(t, err, "star", "imdb", "nm0000402")
+
+		// the deleted star gives its external ids up
+		recreated, err := c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		s, err = c.GetStarByExternalID("imdb", "nm0000402")
+		require.NoError(t, err)
+		require.Equal(t, recreated.ID, s.ID)
+
+		err = c.DeleteStar(contracts.NewAuthenticated(&contracts.DeleteStarRequest{StarID: recreated.ID}, johnDoeToken))
+		require.NoError(t, err)
+	})
+
+	t.Run("stars.PutStarExternalID: success", func(t *testing.T) {
+		star, other := createRandomStar(t, c, johnDoeToken), createRandomStar(t, c, johnDoeToken)
+		req := &contracts.PutStarExternalIDRequest{
+			StarID:               star.ID,
+			PutExternalIDRequest: contracts.PutExternalIDRequest{Source: "imdb", ExternalID: "nm0000184"},
+		}
+		id, err := c.PutStarExternalID(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, &contracts.ExternalID{Source: "imdb", ID: "nm0000184"}, id)
+		require.Equal(t, []*contracts.ExternalID{id}, getStar(t, c, star.ID).ExternalIDs)
+
+		// putting the same id again changes nothing
+		_, err = c.PutStarExternalID(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+
+		req.StarID = other.ID
+		_, err = c.PutStarExternalID(contracts.NewAuthenticated(req, johnDoeToken))
+		requireAlreadyExistsError(t, err, "star", "imdb", "nm0000184")
+	})
+}
+
+func starSearchAPIChecks(t *testing.T, c *client.Client) {
+	createStar := func(firstName, lastName string, born int, died *time.Time) *contracts.StarDetails {
+		star, err := c.CreateStar(contracts.NewAuthenticated(&contracts.CreateStarRequest{
+			FirstName: firstName,
+			LastName:  lastName,
+			BirthDate: time.Date(born, time.May, 1, 0, 0, 0, 0, time.UTC),
+			DeathDate: died,
+		}, johnDoeToken))
+		require.NoError(t, err)
+		return star
+	}
+	penelope := createStar("Penélope", "Álvarez", 1974, nil)
+	peter := createStar("Peter", "Öberg", 1930, ptr(time.Date(2001, time.July, 1, 0, 0, 0, 0, time.UTC)))
+	agnes := createStar("Agnès", "Varda", 1928, ptr(time.Date(2019, time.March, 29, 0, 0, 0, 0, time.UTC)))
+
+	createMovie := func(cast ...*contracts.MovieCreditInfo) *contracts.MovieDetails {
+		movie, err := c.CreateMovie(contracts.NewAuthenticated(&contracts.CreateMovieRequest{
+			Title:       "Dancing in the Rain",
+			ReleaseDate: time.Date(1999, time.April, 1, 0, 0, 0, 0, time.UTC),
+			Description: "A musical with a lot of songs",
+			GenresID:    []int{Drama.ID},
+			Cast:        cast,
+		}, johnDoeToken))
+		require.NoError(t, err)
+		return movie
+	}
+	createMovie(
+		&contracts.MovieCreditInfo{StarID: penelope.ID, Role: "composer"},
+		&contracts.MovieCreditInfo{StarID: peter.ID, Role: "composer"},
+	)
+	createMovie(&contracts.MovieCreditInfo{StarID: peter.ID, Role: "actor"})
+	deleted := createMovie(&contracts.MovieCreditInfo{StarID: agnes.ID, Role: "composer"})
+	err := c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: deleted.ID}, johnDoeToken))
+	require.NoError(t, err)
+
+	starIDs := func(req *contracts.GetStarsPaginatedRequest) []int {
+		res, err := c.GetStars(req)
+		require.NoError(t, err)
+
+		ids := make([]int, len(res.Items))
+		for i, star := range res.Items {
+			ids[i] = star.ID
+		}
+		return ids
+	}
+
+	t.Run("stars.GetStars: by name", func(t *testing.T) {
+		for _, name := range []string{"penelope", "PENÉLOPE Á", "alvarez", "Penelpe Alvarez"} {
+			ids := starIDs(&contracts.GetStarsPaginatedRequest{Name: ptr(name)})
+			require.NotEmpty(t, ids, name)
+			require.Equal(t, penelope.ID, ids[0], name)
+		}
+
+		require.Empty(t, starIDs(&contracts.GetStarsPaginatedRequest{Name: ptr("zzzzzz")}))
+	})
+
+	t.Run("stars.GetStars: by role", func(t *testing.T) {
+		// credits in deleted movies don't count
+		role := ptr("composer")
+		require.ElementsMatch(t, []int{penelope.ID, peter.ID}, starIDs(&contracts.GetStarsPaginatedRequest{Role: role}))
+		require.Equal(t, []int{penelope.ID}, starIDs(&contracts.GetStarsPaginatedRequest{Role: role, Alive: true}))
+		require.Equal(t, []int{peter.ID}, starIDs(&contracts.GetStarsPaginatedRequest{
+			Role:   role,
+			BornTo: ptr(time.Date(1950, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		}))
+		require.Equal(t, []int{peter.ID}, starIDs(&contracts.GetStarsPaginatedRequest{
+			Role:     role,
+			DiedFrom: ptr(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			DiedTo:   ptr(time.Date(2002, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		}))
+		require.Equal(t, []int{agnes.ID}, starIDs(&contracts.GetStarsPaginatedRequest{
+			Name:     ptr("varda"),
+			BornFrom: ptr(time.Date(1928, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			BornTo:   ptr(time.Date(1929, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		}))
+	})
+
+	t.Run("stars.GetStars: unknown role", func(t *testing.T) {
+		res, err := c.GetStars(&contracts.GetStarsPaginatedRequest{Role: ptr("gaffer")})
+		require.NoError(t, err)
+		require.Empty(t, res.Items)
+	})
+
+	t.Run("stars.GetStars: sorted by name and credits", func(t *testing.T) {
+		role := ptr("composer")
+		require.Equal(t, []int{penelope.ID, peter.ID}, starIDs(&contracts.GetStarsPaginatedRequest{Role: role, Sort: ptr("name")}))
+		require.Equal(t, []int{peter.ID, penelope.ID}, starIDs(&contracts.GetStarsPaginatedRequest{Role: role, Sort: ptr("credits:desc")}))
+	})
+}
+
+func starNamesAPIChecks(t *testing.T, c *client.Client) {
+	t.Run("stars.CreateStar: derived names", func(t *testing.T) {
+		star, err := c.CreateStar(contracts.NewAuthenticated(&contracts.CreateStarRequest{
+			FirstName: "Zendaya",
+			BirthDate: time.Date(1996, time.September, 1, 0, 0, 0, 0, time.UTC),
+		}, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, "Zendaya", star.DisplayName)
+		require.Equal(t, "Zendaya", star.SortName)
+
+		patched, err := c.PatchStar(contracts.NewAuthenticated(&contracts.PatchStarRequest{
+			StarID:  star.ID,
+			Version: ptr(star.Version),
+			Fields:  json.RawMessage(`{"last_name": "Coleman"}`),
+		}, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, "Zendaya Coleman", patched.DisplayName)
+		require.Equal(t, "Coleman, Zendaya", patched.SortName)
+	})
+
+	var duke *contracts.StarDetails
+	t.Run("stars.CreateStar: display name and aliases", func(t *testing.T) {
+		req := &contracts.CreateStarRequest{
+			DisplayName: ptr("John Wayne"),
+			SortName:    ptr("Wayne, John"),
+			Aliases: []*contracts.StarAlias{
+				{Name: "Marion Robert Morrison", Kind: "birth"},
+				{Name: "The Duke", Kind: "nickname"},
+			},
+			FirstName:  "Marion",
+			MiddleName: ptr("Robert"),
+			LastName:   "Morrison",
+			BirthDate:  time.Date(1907, time.May, 26, 0, 0, 0, 0, time.UTC),
+			DeathDate:  ptr(time.Date(1979, time.June, 11, 0, 0, 0, 0, time.UTC)),
+		}
+		var err error
+		duke, err = c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, "John Wayne", duke.DisplayName)
+		require.Equal(t, "Wayne, John", duke.SortName)
+		require.Equal(t, req.Aliases, duke.Aliases)
+		require.Equal(t, duke, getStar(t, c, duke.ID))
+
+		// the names are kept on patch, as they are not derived
+		patched, err := c.PatchStar(contracts.NewAuthenticated(&contracts.PatchStarRequest{
+			StarID:  duke.ID,
+			Version: ptr(duke.Version),
+			Fields:  json.RawMessage(`{"birth_place": "Winterset, Iowa, U.S."}`),
+		}, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, duke.DisplayName, patched.DisplayName)
+		require.Equal(t, duke.SortName, patched.SortName)
+		require.Equal(t, duke.Aliases, patched.Aliases)
+		duke = patched
+	})
+
+	t.Run("stars.GetStars: by alias", func(t *testing.T) {
+		for _, name := range []string{"john wayne", "the duke", "marion morrison", "Morison"} {
+			res, err := c.GetStars(&contracts.GetStarsPaginatedRequest{Name: ptr(name)})
+			require.NoError(t, err)
+			require.NotEmpty(t, res.Items, name)
+			require.Equal(t, duke.ID, res.Items[0].ID, name)
+			require.Equal(t, "John Wayne", res.Items[0].DisplayName)
+		}
+
+		res, err := c.Search(&contracts.SearchRequest{
+			SearchTerm: "duke",
+			Types:      contracts.StringList{"stars"},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Stars.Items, 1)
+		require.Equal(t, duke.ID, res.Stars.Items[0].ID)
+
+		// suggestions match the aliases too and show the display name
+		for _, term := range []string{"john way", "the duk", "marion rob"} {
+			suggestions, err := c.Suggest(&contracts.SuggestRequest{SearchTerm: term})
+			require.NoError(t, err)
+			require.Contains(t, suggestions, &contracts.Suggestion{Type: "star", ID: duke.ID, Text: "John Wayne"}, term)
+		}
+	})
+
+	t.Run("stars.UpdateStar: aliases replaced", func(t *testing.T) {
+		req := &contracts.UpdateStarRequest{
+			StarID:      duke.ID,
+			Version:     duke.Version,
+			DisplayName: &duke.DisplayName,
+			SortName:    &duke.SortName,
+			Aliases:     []*contracts.StarAlias{{Name: "Duke Morrison", Kind: "stage"}},
+			FirstName:   duke.FirstName,
+			MiddleName:  duke.MiddleName,
+			LastName:    duke.LastName,
+			BirthDate:   duke.BirthDate,
+			DeathDate:   duke.DeathDate,
+		}
+		err := c.UpdateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		require.NoError(t, err)
+		require.Equal(t, req.Aliases, getStar(t, c, duke.ID).Aliases)
+
+		// the birth name is not an alias anymore
+		res, err := c.GetStars(&contracts.GetStarsPaginatedRequest{Name: ptr("marion robert")})
+		require.NoError(t, err)
+		for _, star := range res.Items {
+			require.NotEqual(t, duke.ID, star.ID)
+		}
+	})
+
+	t.Run("stars.CreateStar: names validation", func(t *testing.T) {
+		req := &contracts.CreateStarRequest{
+			BirthDate: time.Date(1907, time.May, 26, 0, 0, 0, 0, time.UTC),
+		}
+		_, err := c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "either display_name or first_name or last_name must be provided")
+
+		req.DisplayName = ptr("John Wayne")
+		req.Aliases = []*contracts.StarAlias{{Name: "The Duke", Kind: "title"}}
+		_, err = c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "alias kind should be birth/stage/nickname/other")
+
+		req.Aliases = []*contracts.StarAlias{{Name: "The Duke", Kind: "nickname"}, {Name: "the duke", Kind: "stage"}}
+		_, err = c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, `alias "the duke" is given more than once`)
+
+		req.Aliases = []*contracts.StarAlias{nil}
+		_, err = c.CreateStar(contracts.NewAuthenticated(req, johnDoeToken))
+		requireBadRequestError(t, err, "alias 0 is null")
+	})
 }
 
 func getStar(t *testing.T, c *client.Client, id int) *contracts.StarDetails {
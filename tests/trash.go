@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/boichique/movie-reviews/client"
+	"github.com/boichique/movie-reviews/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func trashAPIChecks(t *testing.T, c *client.Client) {
+	movie := createRandomMovie(t, c)
+	t.Run("trash.GetTrash: deleted movie", func(t *testing.T) {
+		err := c.DeleteMovie(contracts.NewAuthenticated(&contracts.DeleteMovieRequest{MovieID: movie.ID}, johnDoeToken))
+		require.NoError(t, err)
+
+		req := &contracts.GetTrashRequest{
+			PaginatedRequest: contracts.PaginatedRequest{Size: 1},
+			Entity:           "movies",
+			Sort:             ptr("deleted_at:desc"),
+		}
+		res, err := c.GetTrash(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+		require.Len(t, res.Items, 1)
+
+		item := res.Items[0]
+		require.Equal(t, movie.ID, item.ID)
+		require.Equal(t, movie.Title, item.Title)
+		require.Equal(t, item.DeletedAt.Add(testTrashRetention), item.PurgeAt)
+	})
+
+	t.Run("trash.GetTrash: non-admin", func(t *testing.T) {
+		req := &contracts.GetTrashRequest{Entity: "movies"}
+		_, err := c.GetTrash(contracts.NewAuthenticated(req, johnDoeToken))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+
+	t.Run("trash.GetTrash: unknown entity", func(t *testing.T) {
+		req := &contracts.GetTrashRequest{Entity: "genres"}
+		_, err := c.GetTrash(contracts.NewAuthenticated(req, adminToken))
+		requireBadRequestError(t, err, `unknown entity "genres"`)
+	})
+
+	t.Run("trash.RestoreFromTrash: movie", func(t *testing.T) {
+		req := &contracts.RestoreFromTrashRequest{Entity: "movies", ID: movie.ID}
+		err := c.RestoreFromTrash(contracts.NewAuthenticated(req, adminToken))
+		require.NoError(t, err)
+		require.Equal(t, movie.Title, getMovie(t, c, movie.ID).Title)
+
+		err = c.RestoreFromTrash(contracts.NewAuthenticated(req, adminToken))
+		requireNotFoundError(t, err, "deleted movie", "id", movie.ID)
+	})
+
+	t.Run("trash.RestoreFromTrash: review", func(t *testing.T) {
+		reviewer := registerRandomUser(t, c)
+		token := login(t, c, reviewer.Email, standardPassword)
+
+		createReq := &contracts.CreateReviewRequest{
+			MovieID: movie.ID,
+			UserID:  reviewer.ID,
+			Rating:  8,
+			Title:   "Worth restoring",
+			Content: "It was deleted by mistake, the opinion still stands.",
+		}
+		review, err := c.CreateReview(contracts.NewAuthenticated(createReq, token))
+		require.NoError(t, err)
+
+		deleteReq := &contracts.DeleteReviewRequest{ReviewID: review.ID, UserID: reviewer.ID}
+		err = c.DeleteReview(contracts.NewAuthenticated(deleteReq, token))
+		require.NoError(t, err)
+		require.Nil(t, getMovie(t, c, movie.ID).AvgRating)
+
+		restoreReq := &contracts.RestoreFromTrashRequest{Entity: "reviews", ID: review.ID}
+		err = c.RestoreFromTrash(contracts.NewAuthenticated(restoreReq, adminToken))
+		require.NoError(t, err)
+		require.Equal(t, 8.0, *getMovie(t, c, movie.ID).AvgRating)
+
+		// a new review of the same movie by the same user blocks the restore
+		err = c.DeleteReview(contracts.NewAuthenticated(deleteReq, token))
+		require.NoError(t, err)
+		_, err = c.CreateReview(contracts.NewAuthenticated(createReq, token))
+		require.NoError(t, err)
+
+		err = c.RestoreFromTrash(contracts.NewAuthenticated(restoreReq, adminToken))
+		requireAlreadyExistsError(t, err, "review", "(movie_id,user_id)", fmt.Sprintf("(%d,%d)", movie.ID, reviewer.ID))
+	})
+}
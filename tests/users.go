@@ -1,6 +1,10 @@
 package tests
 
 import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
 	"net/http"
 	"testing"
 
@@ -111,6 +115,65 @@ func usersAPIChecks(t *testing.T, c *client.Client, cfg *config.Config) {
 		requireBadRequestError(t, err, "Role")
 	})
 
+	t.Run("users.GetUserIdenticon: default avatar", func(t *testing.T) {
+		require.Equal(t, &contracts.Avatar{
+			Small:   fmt.Sprintf("%s/api/users/%d/identicon?size=%d", testPublicURL, johnDoe.ID, users.AvatarSmall),
+			Medium:  fmt.Sprintf("%s/api/users/%d/identicon?size=%d", testPublicURL, johnDoe.ID, users.AvatarMedium),
+			Large:   fmt.Sprintf("%s/api/users/%d/identicon?size=%d", testPublicURL, johnDoe.ID, users.AvatarLarge),
+			Default: true,
+		}, johnDoe.Avatar)
+
+		data, err := c.GetUserIdenticon(&contracts.GetUserIdenticonRequest{UserID: johnDoe.ID, Size: users.AvatarSmall})
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, users.AvatarSmall, users.AvatarSmall), img.Bounds())
+
+		same, err := c.GetUserIdenticon(&contracts.GetUserIdenticonRequest{UserID: johnDoe.ID, Size: users.AvatarSmall})
+		require.NoError(t, err)
+		require.Equal(t, data, same)
+
+		_, err = c.GetUserIdenticon(&contracts.GetUserIdenticonRequest{UserID: johnDoe.ID, Size: 1000})
+		requireBadRequestError(t, err, "Size")
+	})
+
+	t.Run("users.UploadUserAvatar: success", func(t *testing.T) {
+		req := &contracts.UploadUserAvatarRequest{UserID: johnDoe.ID}
+		avatar, err := c.UploadUserAvatar(contracts.NewAuthenticated(req, johnDoeToken), pngImage(t, 300, 200))
+		require.NoError(t, err)
+		require.False(t, avatar.Default)
+
+		for size, url := range map[int]string{
+			users.AvatarSmall:  avatar.Small,
+			users.AvatarMedium: avatar.Medium,
+			users.AvatarLarge:  avatar.Large,
+		} {
+			require.Equal(t, image.Rect(0, 0, size, size), getImage(t, url).Bounds())
+		}
+
+		johnDoe = getUser(t, c, johnDoe.ID)
+		require.Equal(t, avatar, johnDoe.Avatar)
+
+		err = c.DeleteUserAvatar(contracts.NewAuthenticated(&contracts.DeleteUserAvatarRequest{UserID: johnDoe.ID}, johnDoeToken))
+		require.NoError(t, err)
+		require.Nil(t, getImage(t, avatar.Large))
+
+		johnDoe = getUser(t, c, johnDoe.ID)
+		require.True(t, johnDoe.Avatar.Default)
+	})
+
+	t.Run("users.UploadUserAvatar: not an image", func(t *testing.T) {
+		req := &contracts.UploadUserAvatarRequest{UserID: johnDoe.ID}
+		_, err := c.UploadUserAvatar(contracts.NewAuthenticated(req, johnDoeToken), []byte("<html>not an image</html>"))
+		requireBadRequestError(t, err, "unsupported image type")
+	})
+
+	t.Run("users.UploadUserAvatar: another user", func(t *testing.T) {
+		req := &contracts.UploadUserAvatarRequest{UserID: johnDoe.ID + 1}
+		_, err := c.UploadUserAvatar(contracts.NewAuthenticated(req, johnDoeToken), pngImage(t, 10, 10))
+		requireForbiddenError(t, err, "insufficient permissions")
+	})
+
 	randomUser := registerRandomUser(t, c)
 	t.Run("users.DeleteUser: another user", func(t *testing.T) {
 		req := &contracts.GetOrDeleteUserRequest{